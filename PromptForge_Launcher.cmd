@@ -2,4 +2,9 @@
 setlocal
 
 set "ROOT=%~dp0"
-start "" "%ROOT%ui\index.html"
+rem Prefer the binary: it serves the UI with live lint and compile.
+if exist "%ROOT%promptforge.exe" (
+  "%ROOT%promptforge.exe" ui
+  exit /b
+)
+start "" "%ROOT%ui\index.html"
\ No newline at end of file
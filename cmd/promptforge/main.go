@@ -0,0 +1,18 @@
+// Command promptforge compiles human intent into machine-enforceable prompt
+// contracts. See internal/cli for the commands and flags.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/promptforge/promptforge"
+	"github.com/promptforge/promptforge/internal/cli"
+)
+
+func main() {
+	if err := cli.Execute(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(promptforge.ExitCode(err))
+	}
+}
@@ -0,0 +1,218 @@
+// Package promptforge holds the error model shared by the parser, compiler and
+// core, so that the CLI, editor integrations and the HTTP server can render
+// errors consistently and tooling can branch on error codes.
+package promptforge
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+	"syscall"
+)
+
+// Code classifies an Error. Codes are stable and safe to branch on.
+type Code string
+
+// Error codes.
+const (
+	// CodeInvalidArgument reports a bad argument, such as an empty path.
+	CodeInvalidArgument Code = "invalid-argument"
+
+	// CodeNotFound reports a missing file or directory.
+	CodeNotFound Code = "not-found"
+
+	// CodePermissionDenied reports a file that cannot be read or written
+	// (EACCES, EPERM).
+	CodePermissionDenied Code = "permission-denied"
+
+	// CodeDiskFull reports a write that failed for lack of space (ENOSPC).
+	CodeDiskFull Code = "disk-full"
+
+	// CodeIO reports any other file system failure.
+	CodeIO Code = "io"
+
+	// CodeLocked reports a project lock held by another promptforge process.
+	CodeLocked Code = "locked"
+
+	// CodeAlreadyExists reports a file that would be overwritten, such as
+	// plan.md on init.
+	CodeAlreadyExists Code = "already-exists"
+
+	// CodeUsage reports a malformed command line: an unknown command or flag, or
+	// a missing argument.
+	CodeUsage Code = "usage"
+
+	// CodeFindings reports a check that ran and found problems: lint errors,
+	// audit errors, failed test cases or an unformatted plan.
+	CodeFindings Code = "findings"
+
+	// CodeContractViolation reports an input payload or model response that
+	// does not satisfy the contract.
+	CodeContractViolation Code = "contract-violation"
+
+	// CodeVariable reports a prompt variable that is missing, undeclared or does
+	// not match its declared type.
+	CodeVariable Code = "variable"
+
+	// CodePlanEmpty reports an empty plan.md.
+	CodePlanEmpty Code = "plan-empty"
+
+	// CodePlanSyntax reports plan.md content that cannot be parsed, such as a
+	// missing Goal section or a malformed parameter.
+	CodePlanSyntax Code = "plan-syntax"
+
+	// CodePlanInclude reports an include or extends directive in plan.md that
+	// cannot be resolved.
+	CodePlanInclude Code = "plan-include"
+
+	// CodeIRParse reports prompt.ir.json content that is not valid JSON.
+	CodeIRParse Code = "ir-parse"
+
+	// CodeIRInvalid reports an IR that fails validation.
+	CodeIRInvalid Code = "ir-invalid"
+
+	// CodeIRVersion reports an IR version that cannot be migrated.
+	CodeIRVersion Code = "ir-version"
+
+	// CodeInternal reports a failure that is not caused by user input.
+	CodeInternal Code = "internal"
+)
+
+// Error is an error with a code and, where known, the source position it
+// refers to and a hint on how to fix it.
+//
+// Error implements Unwrap, so errors.Is(err, syscall.ENOSPC) and
+// errors.Is(err, fs.ErrPermission) see through it to the file system error it
+// wraps. errors.Is(err, &Error{Code: c}) matches any Error with code c.
+type Error struct {
+	Code    Code
+	Message string
+
+	// File, Line and Column locate the error; Line and Column are 1-based and 0
+	// when unknown.
+	File   string
+	Line   int
+	Column int
+
+	// Hint suggests how to fix the error.
+	Hint string
+
+	// Err is the underlying cause.
+	Err error
+}
+
+// Errorf returns an Error with code and a formatted message.
+func Errorf(code Code, format string, args ...interface{}) *Error {
+	return &Error{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// Wrap returns an Error with code and a formatted message that wraps cause.
+func Wrap(cause error, code Code, format string, args ...interface{}) *Error {
+	return &Error{Code: code, Message: fmt.Sprintf(format, args...), Err: cause}
+}
+
+// At sets the source position of e and returns it.
+func (e *Error) At(file string, line, column int) *Error {
+	e.File = file
+	e.Line = line
+	e.Column = column
+	return e
+}
+
+// WithHint sets the hint of e and returns it.
+func (e *Error) WithHint(hint string) *Error {
+	e.Hint = hint
+	return e
+}
+
+// Error renders e as "file:line:column: message: cause. hint". The position is
+// only rendered when the line is known; without a file it is reported as
+// "message at line N".
+func (e *Error) Error() string {
+	var b strings.Builder
+	if e.File != "" && e.Line > 0 {
+		fmt.Fprintf(&b, "%s:%d", e.File, e.Line)
+		if e.Column > 0 {
+			fmt.Fprintf(&b, ":%d", e.Column)
+		}
+		b.WriteString(": ")
+	}
+	b.WriteString(e.Message)
+	if e.File == "" && e.Line > 0 {
+		fmt.Fprintf(&b, " at line %d", e.Line)
+	}
+	if e.Err != nil {
+		b.WriteString(": ")
+		b.WriteString(e.Err.Error())
+	}
+	if e.Hint != "" {
+		b.WriteString(". ")
+		b.WriteString(e.Hint)
+	}
+	return b.String()
+}
+
+// Unwrap returns the underlying cause.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is an *Error with the same code.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code != "" && t.Code == e.Code
+}
+
+// CodeOf returns the code of the first Error in err's chain, or "" if there is none.
+func CodeOf(err error) Code {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return ""
+}
+
+// diskFullErrnos are the errnos reported when a disk is full.
+var diskFullErrnos = []syscall.Errno{syscall.ENOSPC}
+
+// IsDiskFull reports whether err was caused by a full disk.
+func IsDiskFull(err error) bool {
+	for _, errno := range diskFullErrnos {
+		if errors.Is(err, errno) {
+			return true
+		}
+	}
+	return false
+}
+
+// IOCode classifies a file system error as CodeDiskFull, CodePermissionDenied,
+// CodeNotFound or CodeIO.
+func IOCode(err error) Code {
+	switch {
+	case IsDiskFull(err):
+		return CodeDiskFull
+	case errors.Is(err, fs.ErrPermission):
+		return CodePermissionDenied
+	case errors.Is(err, fs.ErrNotExist):
+		return CodeNotFound
+	default:
+		return CodeIO
+	}
+}
+
+// ReadError returns the error for a failed read of the file at path, described
+// by what (such as "prompt.ir.json"). The code is derived from err.
+func ReadError(err error, what, path string) *Error {
+	return &Error{Code: IOCode(err), Message: fmt.Sprintf("cannot read %s at %s", what, path), File: path, Err: err}
+}
+
+// WriteError returns the error for a failed write of the file at path, described
+// by what (such as "prompt.ir.json"). The code is derived from err.
+func WriteError(err error, what, path string) *Error {
+	e := &Error{Code: IOCode(err), Message: fmt.Sprintf("cannot write %s to %s", what, path), File: path, Err: err}
+	if e.Code == CodeDiskFull {
+		e.Hint = "Free up disk space and try again"
+	}
+	return e
+}
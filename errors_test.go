@@ -0,0 +1,68 @@
+package promptforge
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"syscall"
+	"testing"
+)
+
+func TestError_Error(t *testing.T) {
+	tests := []struct {
+		err  *Error
+		want string
+	}{
+		{err: Errorf(CodePlanEmpty, "plan content is empty"), want: "plan content is empty"},
+		{err: &Error{Message: "Goal section is empty", Line: 3, Hint: "Please provide a goal description"}, want: "Goal section is empty at line 3. Please provide a goal description"},
+		{err: Errorf(CodePlanSyntax, "Goal section is empty").At("plan.md", 3, 0), want: "plan.md:3: Goal section is empty"},
+		{err: Wrap(errors.New("invalid character"), CodeIRParse, "failed to parse prompt.ir.json").At("prompt.ir.json", 2, 7), want: "prompt.ir.json:2:7: failed to parse prompt.ir.json: invalid character"},
+		{err: &Error{Message: "cannot read x at /p", File: "/p"}, want: "cannot read x at /p"},
+	}
+	for _, tt := range tests {
+		if got := tt.err.Error(); got != tt.want {
+			t.Errorf("Error() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestError_IsAndAs(t *testing.T) {
+	cause := &fs.PathError{Op: "write", Path: "/p/prompt.ir.json", Err: syscall.ENOSPC}
+	err := fmt.Errorf("compile: %w", WriteError(cause, "prompt.ir.json", "/p/prompt.ir.json"))
+
+	if !errors.Is(err, syscall.ENOSPC) {
+		t.Error("Expected errors.Is(err, syscall.ENOSPC)")
+	}
+	if !errors.Is(err, &Error{Code: CodeDiskFull}) || errors.Is(err, &Error{Code: CodeIO}) {
+		t.Error("Expected errors.Is to match by code")
+	}
+	var e *Error
+	if !errors.As(err, &e) || e.File != "/p/prompt.ir.json" || e.Hint == "" {
+		t.Errorf("Expected errors.As to find the disk-full error, got %+v", e)
+	}
+	if CodeOf(err) != CodeDiskFull || CodeOf(errors.New("plain")) != "" {
+		t.Errorf("Unexpected CodeOf results")
+	}
+}
+
+func TestIOCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want Code
+	}{
+		{err: &fs.PathError{Op: "write", Path: "p", Err: syscall.ENOSPC}, want: CodeDiskFull},
+		{err: &fs.PathError{Op: "open", Path: "p", Err: syscall.EACCES}, want: CodePermissionDenied},
+		{err: &fs.PathError{Op: "open", Path: "p", Err: syscall.ENOENT}, want: CodeNotFound},
+		{err: &fs.PathError{Op: "read", Path: "p", Err: syscall.EIO}, want: CodeIO},
+	}
+	for _, tt := range tests {
+		if got := IOCode(tt.err); got != tt.want {
+			t.Errorf("IOCode(%v) = %s, want %s", tt.err, got, tt.want)
+		}
+	}
+
+	err := ReadError(&fs.PathError{Op: "open", Path: "p", Err: syscall.EACCES}, "plan.md", "p")
+	if !errors.Is(err, syscall.EACCES) || !errors.Is(err, fs.ErrPermission) {
+		t.Error("Expected ReadError to wrap EACCES")
+	}
+}
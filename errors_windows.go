@@ -0,0 +1,8 @@
+package promptforge
+
+import "syscall"
+
+func init() {
+	// ERROR_HANDLE_DISK_FULL and ERROR_DISK_FULL
+	diskFullErrnos = append(diskFullErrnos, syscall.Errno(39), syscall.Errno(112))
+}
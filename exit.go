@@ -0,0 +1,69 @@
+package promptforge
+
+import (
+	"errors"
+	"io/fs"
+)
+
+// Exit codes of the promptforge command. They are stable, so scripts can tell
+// findings from failures without parsing output.
+const (
+	// ExitOK reports success.
+	ExitOK = 0
+
+	// ExitFindings reports a check that found problems (CodeFindings), and any
+	// failure without a more specific code.
+	ExitFindings = 1
+
+	// ExitUsage reports a malformed command line or invalid argument.
+	ExitUsage = 2
+
+	// ExitIO reports a file that is missing, exists already, cannot be read or
+	// written, or a project locked by another process.
+	ExitIO = 3
+
+	// ExitValidation reports a plan, IR, variable, payload or response that
+	// fails validation.
+	ExitValidation = 4
+)
+
+// exitCodes maps error codes to exit codes; codes not listed exit with
+// ExitFindings.
+var exitCodes = map[Code]int{
+	CodeInvalidArgument:   ExitUsage,
+	CodeUsage:             ExitUsage,
+	CodeNotFound:          ExitIO,
+	CodePermissionDenied:  ExitIO,
+	CodeDiskFull:          ExitIO,
+	CodeIO:                ExitIO,
+	CodeLocked:            ExitIO,
+	CodeAlreadyExists:     ExitIO,
+	CodePlanEmpty:         ExitValidation,
+	CodePlanSyntax:        ExitValidation,
+	CodePlanInclude:       ExitValidation,
+	CodeIRParse:           ExitValidation,
+	CodeIRInvalid:         ExitValidation,
+	CodeIRVersion:         ExitValidation,
+	CodeContractViolation: ExitValidation,
+	CodeVariable:          ExitValidation,
+}
+
+// ExitCode returns the exit code for err: ExitOK for nil, otherwise the code
+// mapped from the first Error in err's chain. A file system error without an
+// Error exits with ExitIO.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	if code := CodeOf(err); code != "" {
+		if exit, ok := exitCodes[code]; ok {
+			return exit
+		}
+		return ExitFindings
+	}
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) || IsDiskFull(err) {
+		return ExitIO
+	}
+	return ExitFindings
+}
@@ -0,0 +1,32 @@
+package promptforge
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"syscall"
+	"testing"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{err: nil, want: ExitOK},
+		{err: Errorf(CodeFindings, "lint failed with 1 error(s)"), want: ExitFindings},
+		{err: errors.New("unclassified"), want: ExitFindings},
+		{err: Errorf(CodeUsage, "unknown command: x"), want: ExitUsage},
+		{err: Errorf(CodeInvalidArgument, "output path cannot be empty"), want: ExitUsage},
+		{err: fmt.Errorf("compile: %w", Errorf(CodeLocked, "locked")), want: ExitIO},
+		{err: &fs.PathError{Op: "open", Path: "/p", Err: fs.ErrNotExist}, want: ExitIO},
+		{err: fmt.Errorf("write: %w", syscall.ENOSPC), want: ExitIO},
+		{err: fmt.Errorf("compilation failed: %w", Errorf(CodePlanSyntax, "Goal section is empty")), want: ExitValidation},
+		{err: Errorf(CodeContractViolation, "output validation failed"), want: ExitValidation},
+	}
+	for _, tt := range tests {
+		if got := ExitCode(tt.err); got != tt.want {
+			t.Errorf("ExitCode(%v) = %d, want %d", tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,302 @@
+// Package guard enforces a compiled prompt contract (prompt.ir.json) around model
+// calls in Go services.
+//
+// Load the contract once and share the Guard between goroutines:
+//
+//	g, err := guard.Load("prompt.ir.json", guard.WithRepairRetries(1))
+//	...
+//	result, err := guard.Call[Decision](ctx, g, input, model)
+//	if err != nil {
+//		return err // the model call itself failed
+//	}
+//	if !result.OK() {
+//		// result.FailureMode and result.Violations say why
+//	}
+//	use(result.Value)
+//
+// Inputs are checked against input_schema before the model is called; invalid
+// inputs map to the "invalid-input" or "missing-required" failure modes without
+// a model call. Responses are checked against output_schema and the rules that
+// can be checked mechanically. Valid payloads are recognized without allocating;
+// detailed violations are only computed for payloads that fail that check.
+package guard
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/promptforge/promptforge/internal/compiler"
+	"github.com/promptforge/promptforge/internal/contract"
+	"github.com/promptforge/promptforge/internal/ir"
+	"github.com/promptforge/promptforge/internal/render"
+	"github.com/promptforge/promptforge/internal/repair"
+)
+
+// Violation is one way an input or response breaks the contract. ID is the rule
+// or failure mode it maps to, such as "output-json", "output-schema" or
+// "missing-required".
+type Violation = contract.Violation
+
+// Change describes a deterministic repair that was applied to a response, or
+// refused because it could change the response's meaning.
+type Change = repair.Change
+
+// RepairResult is the outcome of Guard.Repair.
+type RepairResult = repair.Result
+
+// Request is a single model call made by Call.
+type Request struct {
+	// SystemPrompt is the rendered system prompt of the contract.
+	SystemPrompt string
+
+	// Input is the input payload as a JSON document.
+	Input json.RawMessage
+
+	// Attempt is 1 for the first call and increases with each repair retry.
+	Attempt int
+
+	// Repair is empty on the first attempt. On retries it holds instructions that
+	// list the rules the previous response violated; send it to the model after
+	// PreviousResponse, for example as a follow-up user message.
+	Repair string
+
+	// PreviousResponse is the rejected response on retries.
+	PreviousResponse string
+}
+
+// Model calls a model and returns its raw reply.
+type Model func(ctx context.Context, req Request) (string, error)
+
+// Result is the outcome of Call.
+type Result[T any] struct {
+	// Value is the decoded response. It is only set when OK reports true.
+	Value T
+
+	// Raw is the last model response; empty when the input was rejected.
+	Raw string
+
+	// FailureMode is set when the input was rejected ("invalid-input" or
+	// "missing-required") or the model reported a failure mode.
+	FailureMode string
+
+	// Violations lists how the input or the last response breaks the contract.
+	Violations []Violation
+
+	// Attempts is the number of model calls made.
+	Attempts int
+
+	// Repairs lists the deterministic repairs applied to the last response
+	// (see WithAutoRepair).
+	Repairs []Change
+}
+
+// OK reports whether the model returned a response that satisfies the contract.
+func (r *Result[T]) OK() bool {
+	return r.FailureMode == "" && len(r.Violations) == 0
+}
+
+// Guard checks inputs and responses against one contract. It is immutable after
+// construction and safe for concurrent use.
+type Guard struct {
+	validator     *contract.Validator
+	repairer      *repair.Repairer
+	systemPrompt  string
+	repairRetries int
+	autoRepair    bool
+}
+
+type options struct {
+	repairRetries int
+	autoRepair    bool
+	variables     map[string]string
+}
+
+// Option configures a Guard.
+type Option func(*options)
+
+// WithRepairRetries makes Call retry up to n times when a response breaks the
+// contract, sending the model a repair prompt that lists the violated rules.
+func WithRepairRetries(n int) Option {
+	return func(o *options) {
+		if n > 0 {
+			o.repairRetries = n
+		}
+	}
+}
+
+// WithAutoRepair makes Call apply safe deterministic repairs (see Guard.Repair)
+// to a response that breaks the contract before retrying the model.
+func WithAutoRepair() Option {
+	return func(o *options) {
+		o.autoRepair = true
+	}
+}
+
+// WithVariables fills the contract's {{variables}} when the system prompt is
+// rendered. Required variables without a value or default make Load fail.
+func WithVariables(values map[string]string) Option {
+	return func(o *options) {
+		o.variables = values
+	}
+}
+
+// Load reads a compiled contract from prompt.ir.json.
+func Load(path string, opts ...Option) (*Guard, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsPermission(err) {
+			return nil, fmt.Errorf("permission denied: cannot read prompt.ir.json at %s", path)
+		}
+		return nil, fmt.Errorf("failed to read prompt.ir.json at %s: %w", path, err)
+	}
+	return New(data, opts...)
+}
+
+// New builds a Guard from the contents of a prompt.ir.json file.
+func New(data []byte, opts ...Option) (*Guard, error) {
+	var promptIR ir.PromptIR
+	if err := json.Unmarshal(data, &promptIR); err != nil {
+		return nil, fmt.Errorf("failed to parse prompt.ir.json: %w", err)
+	}
+	if err := compiler.ValidateIR(&promptIR); err != nil {
+		return nil, fmt.Errorf("IR validation failed: %w", err)
+	}
+
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	rendered, err := render.Render(&promptIR, o.variables)
+	if err != nil {
+		return nil, fmt.Errorf("render failed: %w", err)
+	}
+	validator, err := contract.NewValidator(rendered)
+	if err != nil {
+		return nil, err
+	}
+	repairer, err := repair.New(rendered)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Guard{
+		validator:     validator,
+		repairer:      repairer,
+		systemPrompt:  render.SystemPrompt(rendered),
+		repairRetries: o.repairRetries,
+		autoRepair:    o.autoRepair,
+	}, nil
+}
+
+// SystemPrompt returns the rendered system prompt.
+func (g *Guard) SystemPrompt() string {
+	return g.systemPrompt
+}
+
+// CheckInput checks a JSON input payload against input_schema and returns nil
+// when it is valid.
+func (g *Guard) CheckInput(input []byte) []Violation {
+	if g.validator.InputValid(input) {
+		return nil
+	}
+	return g.validator.ValidateInputJSON(input)
+}
+
+// CheckOutput checks a raw model response. It returns the failure mode the
+// response reports, if any, and the ways it breaks the contract; both are empty
+// for a valid response.
+func (g *Guard) CheckOutput(response []byte) (string, []Violation) {
+	if g.validator.OutputValid(response) {
+		return "", nil
+	}
+	out := g.validator.ValidateOutput(response)
+	return out.FailureMode, out.Violations
+}
+
+// Repair applies safe deterministic repairs to a response that almost satisfies
+// the contract: it strips a Markdown code fence, extracts the single JSON value
+// from surrounding prose, fixes the case of enum values and drops undeclared
+// properties where additional_properties is false. Repairs that could change the
+// meaning of the response are refused and reported instead.
+func (g *Guard) Repair(response []byte) RepairResult {
+	return g.repairer.Repair(response)
+}
+
+// Call checks input, calls model, and checks and decodes its response into T.
+// The returned error is only set when the model call fails, the context is
+// cancelled, or a valid response cannot be decoded into T; contract outcomes are
+// reported in the Result.
+func Call[T any](ctx context.Context, g *Guard, input []byte, model Model) (*Result[T], error) {
+	result := &Result[T]{}
+	if violations := g.CheckInput(input); len(violations) > 0 {
+		result.FailureMode = violations[0].ID
+		result.Violations = violations
+		return result, nil
+	}
+
+	req := Request{SystemPrompt: g.systemPrompt, Input: input}
+	for attempt := 1; attempt <= 1+g.repairRetries; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		req.Attempt = attempt
+		raw, err := model(ctx, req)
+		result.Attempts = attempt
+		if err != nil {
+			return result, fmt.Errorf("model call failed (attempt %d): %w", attempt, err)
+		}
+		result.Raw = raw
+
+		failureMode, violations := g.CheckOutput([]byte(raw))
+		result.Repairs = nil
+		if len(violations) > 0 && g.autoRepair {
+			repaired := g.repairer.Repair([]byte(raw))
+			if len(repaired.Violations) == 0 {
+				raw = string(repaired.Output)
+				failureMode, violations = repaired.FailureMode, nil
+				result.Repairs = repaired.Applied
+			}
+		}
+		result.FailureMode = failureMode
+		result.Violations = violations
+		switch {
+		case failureMode == "" && len(violations) == 0:
+			if err := json.Unmarshal([]byte(raw), &result.Value); err != nil {
+				return result, fmt.Errorf("failed to decode response: %w", err)
+			}
+			return result, nil
+		case failureMode != "" && len(violations) == 0:
+			// the model reported a declared failure mode; retrying will not help
+			return result, nil
+		}
+
+		req.PreviousResponse = result.Raw
+		req.Repair = RepairPrompt(violations)
+	}
+	return result, nil
+}
+
+// RepairPrompt returns instructions asking the model to correct a response that
+// broke the listed rules.
+func RepairPrompt(violations []Violation) string {
+	var ids []string
+	seen := make(map[string]bool)
+	for _, violation := range violations {
+		if !seen[violation.ID] {
+			seen[violation.ID] = true
+			ids = append(ids, violation.ID)
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Your previous response violated: %s.\n", strings.Join(ids, ", "))
+	for _, violation := range violations {
+		fmt.Fprintf(&b, "- %s\n", violation)
+	}
+	b.WriteString("Reply again with only a corrected response that satisfies every rule.\n")
+	return b.String()
+}
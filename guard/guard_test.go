@@ -0,0 +1,251 @@
+package guard
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const testContract = `{
+  "version": "1.0",
+  "system_role": "You triage refund requests for {{company}}.",
+  "rules": [
+    {"id": "output-json", "description": "Output must be valid JSON"},
+    {"id": "no-explanations", "description": "Do not include explanations unless explicitly requested"}
+  ],
+  "input_schema": {
+    "type": "object",
+    "properties": {"order_id": {"type": "string"}},
+    "required": ["order_id"]
+  },
+  "output_schema": {
+    "type": "object",
+    "properties": {"decision": {"type": "string", "enum": ["refund", "deny"]}},
+    "required": ["decision"]
+  },
+  "failure_modes": [
+    {"id": "invalid-input", "condition": "Input does not match input_schema", "response": "Return error"},
+    {"id": "missing-required", "condition": "Required fields are missing", "response": "Return error"},
+    {"id": "ambiguous-request", "condition": "Request is ambiguous", "response": "Return error"}
+  ],
+  "variables": [{"name": "company", "type": "string", "required": true}]
+}`
+
+type decision struct {
+	Decision string `json:"decision"`
+}
+
+func newTestGuard(t *testing.T, opts ...Option) *Guard {
+	t.Helper()
+	opts = append([]Option{WithVariables(map[string]string{"company": "Acme"})}, opts...)
+	g, err := New([]byte(testContract), opts...)
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	return g
+}
+
+// replies returns a model that answers with the given responses in order.
+func replies(responses ...string) (Model, *[]Request) {
+	var requests []Request
+	return func(ctx context.Context, req Request) (string, error) {
+		requests = append(requests, req)
+		return responses[len(requests)-1], nil
+	}, &requests
+}
+
+func TestNew_RendersVariables(t *testing.T) {
+	g := newTestGuard(t)
+	if !strings.HasPrefix(g.SystemPrompt(), "You triage refund requests for Acme.") {
+		t.Errorf("Unexpected system prompt %q", g.SystemPrompt())
+	}
+
+	if _, err := New([]byte(testContract)); err == nil || !strings.Contains(err.Error(), "missing required variables: company") {
+		t.Errorf("Expected a missing variable error, got %v", err)
+	}
+}
+
+func TestCall_ValidResponse(t *testing.T) {
+	g := newTestGuard(t)
+	model, requests := replies(`{"decision": "refund"}`)
+
+	result, err := Call[decision](context.Background(), g, []byte(`{"order_id": "A-1"}`), model)
+	if err != nil {
+		t.Fatalf("Call() failed: %v", err)
+	}
+	if !result.OK() || result.Value.Decision != "refund" || result.Attempts != 1 {
+		t.Errorf("Unexpected result %+v", result)
+	}
+	if (*requests)[0].SystemPrompt != g.SystemPrompt() || string((*requests)[0].Input) != `{"order_id": "A-1"}` {
+		t.Errorf("Unexpected request %+v", (*requests)[0])
+	}
+}
+
+func TestCall_InvalidInputSkipsModel(t *testing.T) {
+	g := newTestGuard(t)
+	model, requests := replies()
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: `{}`, want: "missing-required"},
+		{input: `{"order_id": 7}`, want: "invalid-input"},
+		{input: `not json`, want: "invalid-input"},
+	}
+	for _, tt := range tests {
+		result, err := Call[decision](context.Background(), g, []byte(tt.input), model)
+		if err != nil {
+			t.Fatalf("Call(%s) failed: %v", tt.input, err)
+		}
+		if result.OK() || result.FailureMode != tt.want || result.Attempts != 0 {
+			t.Errorf("Call(%s) = %+v, want failure mode %s without a model call", tt.input, result, tt.want)
+		}
+	}
+	if len(*requests) != 0 {
+		t.Errorf("Expected no model calls, got %d", len(*requests))
+	}
+}
+
+func TestCall_RepairRetry(t *testing.T) {
+	g := newTestGuard(t, WithRepairRetries(2))
+	model, requests := replies("Sure! {\"decision\": \"refund\"}", `{"decision": "maybe"}`, `{"decision": "deny"}`)
+
+	result, err := Call[decision](context.Background(), g, []byte(`{"order_id": "A-1"}`), model)
+	if err != nil {
+		t.Fatalf("Call() failed: %v", err)
+	}
+	if !result.OK() || result.Value.Decision != "deny" || result.Attempts != 3 {
+		t.Fatalf("Unexpected result %+v", result)
+	}
+
+	second := (*requests)[1]
+	if second.Attempt != 2 || second.PreviousResponse != "Sure! {\"decision\": \"refund\"}" {
+		t.Errorf("Unexpected retry request %+v", second)
+	}
+	if !strings.HasPrefix(second.Repair, "Your previous response violated: output-json.") {
+		t.Errorf("Unexpected repair prompt %q", second.Repair)
+	}
+	if !strings.HasPrefix((*requests)[2].Repair, "Your previous response violated: output-schema.") {
+		t.Errorf("Unexpected repair prompt %q", (*requests)[2].Repair)
+	}
+}
+
+func TestCall_RetriesExhausted(t *testing.T) {
+	g := newTestGuard(t, WithRepairRetries(1))
+	model, _ := replies(`{"decision": "maybe"}`, `{"decision": "later"}`)
+
+	result, err := Call[decision](context.Background(), g, []byte(`{"order_id": "A-1"}`), model)
+	if err != nil {
+		t.Fatalf("Call() failed: %v", err)
+	}
+	if result.OK() || result.Attempts != 2 || result.Violations[0].ID != "output-schema" || result.Value.Decision != "" {
+		t.Errorf("Unexpected result %+v", result)
+	}
+}
+
+func TestCall_ModelReportsFailureMode(t *testing.T) {
+	g := newTestGuard(t, WithRepairRetries(3))
+	model, requests := replies(`{"error": "ambiguous-request"}`)
+
+	result, err := Call[decision](context.Background(), g, []byte(`{"order_id": "A-1"}`), model)
+	if err != nil {
+		t.Fatalf("Call() failed: %v", err)
+	}
+	if result.OK() || result.FailureMode != "ambiguous-request" || len(result.Violations) != 0 || len(*requests) != 1 {
+		t.Errorf("Unexpected result %+v after %d call(s)", result, len(*requests))
+	}
+}
+
+func TestCall_ModelError(t *testing.T) {
+	g := newTestGuard(t)
+	failing := func(ctx context.Context, req Request) (string, error) {
+		return "", errors.New("connection refused")
+	}
+
+	if _, err := Call[decision](context.Background(), g, []byte(`{"order_id": "A-1"}`), failing); err == nil || !strings.Contains(err.Error(), "connection refused") {
+		t.Fatalf("Expected the model error, got %v", err)
+	}
+}
+
+func TestGuard_ConcurrentUse(t *testing.T) {
+	g := newTestGuard(t)
+	model := func(ctx context.Context, req Request) (string, error) {
+		if strings.Contains(string(req.Input), "deny") {
+			return `{"decision": "deny"}`, nil
+		}
+		return `{"decision": "refund"}`, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			input, want := `{"order_id": "refund"}`, "refund"
+			if i%2 == 0 {
+				input, want = `{"order_id": "deny"}`, "deny"
+			}
+			for j := 0; j < 50; j++ {
+				result, err := Call[decision](context.Background(), g, []byte(input), model)
+				if err != nil || result.Value.Decision != want {
+					t.Errorf("Call() = %+v, %v; want %s", result, err, want)
+					return
+				}
+				if violations := g.CheckInput([]byte(`{}`)); len(violations) == 0 {
+					t.Error("CheckInput() accepted an input without order_id")
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestCheck_ValidPayloadsDoNotAllocate(t *testing.T) {
+	g := newTestGuard(t)
+	input := []byte(`{"order_id": "A-1"}`)
+	response := []byte(`{"decision": "refund"}`)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		if violations := g.CheckInput(input); violations != nil {
+			t.Fatal("expected a valid input")
+		}
+		if failureMode, violations := g.CheckOutput(response); failureMode != "" || violations != nil {
+			t.Fatal("expected a valid response")
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("CheckInput and CheckOutput allocated %.0f times per run, want 0", allocs)
+	}
+}
+
+func TestCall_AutoRepair(t *testing.T) {
+	g := newTestGuard(t, WithAutoRepair(), WithRepairRetries(1))
+	model, requests := replies("```json\n{\"decision\": \"Deny\"}\n```")
+
+	result, err := Call[decision](context.Background(), g, []byte(`{"order_id": "A-1"}`), model)
+	if err != nil {
+		t.Fatalf("Call() failed: %v", err)
+	}
+	if !result.OK() || result.Value.Decision != "deny" {
+		t.Errorf("Expected the repaired response to be accepted, got %+v", result)
+	}
+	if len(*requests) != 1 {
+		t.Errorf("Expected no retry after a deterministic repair, got %d calls", len(*requests))
+	}
+	if len(result.Repairs) != 2 {
+		t.Errorf("Expected two repairs, got %v", result.Repairs)
+	}
+}
+
+func TestRepair_RefusesAmbiguousFix(t *testing.T) {
+	g := newTestGuard(t)
+
+	result := g.Repair([]byte(`{"decision": "refund"} or {"decision": "deny"}`))
+	if len(result.Refused) != 1 || len(result.Violations) == 0 {
+		t.Errorf("Expected the repair to be refused, got %+v", result)
+	}
+}
@@ -0,0 +1,151 @@
+// Package atomicfile writes files so that readers never see them half written,
+// and commits several files together so that a failure leaves either all of the
+// new files or all of the old ones in place. A Lock serializes writers of the
+// same project across processes.
+package atomicfile
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/promptforge/promptforge"
+)
+
+// file is a file staged by a Transaction.
+type file struct {
+	path string
+	what string
+	data []byte
+	perm os.FileMode
+
+	// temp is the staged copy; previous is the content it replaces, or nil if
+	// the file did not exist.
+	temp     string
+	previous []byte
+}
+
+// Transaction collects files and writes them together on Commit. The zero value
+// is ready to use.
+type Transaction struct {
+	files []*file
+}
+
+// Add stages data to be written to path with perm on Commit. what describes the
+// file in errors (such as "prompt.ir.json").
+func (t *Transaction) Add(path, what string, data []byte, perm os.FileMode) {
+	t.files = append(t.files, &file{path: path, what: what, data: data, perm: perm})
+}
+
+// Commit writes every file to a temporary file next to its target, syncs it,
+// and only once all of them are on disk renames them over their targets. If
+// staging fails, no target is touched. If a rename fails, the targets already
+// replaced are restored to their previous content. Errors are
+// *promptforge.Error values from promptforge.WriteError.
+func (t *Transaction) Commit() error {
+	defer t.cleanup()
+
+	for _, f := range t.files {
+		if err := f.stage(); err != nil {
+			return promptforge.WriteError(err, f.what, f.path)
+		}
+	}
+
+	for i, f := range t.files {
+		if err := os.Rename(f.temp, f.path); err != nil {
+			t.rollback(t.files[:i])
+			return promptforge.WriteError(err, f.what, f.path)
+		}
+		f.temp = ""
+	}
+
+	for _, dir := range t.dirs() {
+		syncDir(dir)
+	}
+	return nil
+}
+
+// stage writes f to a synced temporary file in its target directory and records
+// the content it will replace.
+func (f *file) stage() error {
+	previous, err := os.ReadFile(f.path)
+	if err == nil {
+		f.previous = previous
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(f.path), "."+filepath.Base(f.path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	f.temp = temp.Name()
+
+	if _, err := temp.Write(f.data); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Chmod(f.perm); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Sync(); err != nil {
+		temp.Close()
+		return err
+	}
+	return temp.Close()
+}
+
+// rollback restores files that were already renamed into place.
+func (t *Transaction) rollback(replaced []*file) {
+	for _, f := range replaced {
+		if f.previous == nil {
+			os.Remove(f.path)
+			continue
+		}
+		WriteFile(f.path, f.what, f.previous, f.perm)
+	}
+}
+
+// cleanup removes temporary files left by a failed Commit.
+func (t *Transaction) cleanup() {
+	for _, f := range t.files {
+		if f.temp != "" {
+			os.Remove(f.temp)
+			f.temp = ""
+		}
+	}
+}
+
+// dirs returns the directories of the staged files, once each.
+func (t *Transaction) dirs() []string {
+	seen := make(map[string]bool)
+	var dirs []string
+	for _, f := range t.files {
+		dir := filepath.Dir(f.path)
+		if !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// WriteFile atomically replaces the file at path with data: readers see either
+// the old content or the new one, never a partial write. what describes the file
+// in errors.
+func WriteFile(path, what string, data []byte, perm os.FileMode) error {
+	var t Transaction
+	t.Add(path, what, data, perm)
+	return t.Commit()
+}
+
+// syncDir flushes a directory so that renames into it survive a crash. It is
+// best effort: some platforms cannot sync directories.
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	d.Sync()
+	d.Close()
+}
@@ -0,0 +1,185 @@
+package atomicfile
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/promptforge/promptforge"
+)
+
+func TestTransaction_CommitsAllFiles(t *testing.T) {
+	dir := t.TempDir()
+	irPath := filepath.Join(dir, "prompt.ir.json")
+	schemaPath := filepath.Join(dir, "prompt.ir.schema.json")
+	if err := os.WriteFile(irPath, []byte("old"), 0644); err != nil {
+		t.Fatalf("Failed to write prompt.ir.json: %v", err)
+	}
+
+	var txn Transaction
+	txn.Add(irPath, "prompt.ir.json", []byte("new ir"), 0644)
+	txn.Add(schemaPath, "prompt.ir.schema.json", []byte("new schema"), 0644)
+	if err := txn.Commit(); err != nil {
+		t.Fatalf("Commit() failed: %v", err)
+	}
+
+	for path, want := range map[string]string{irPath: "new ir", schemaPath: "new schema"} {
+		data, err := os.ReadFile(path)
+		if err != nil || string(data) != want {
+			t.Errorf("%s = %q, %v; want %q", filepath.Base(path), data, err, want)
+		}
+	}
+	assertNoTempFiles(t, dir)
+}
+
+func TestTransaction_FailedStagingLeavesTargets(t *testing.T) {
+	dir := t.TempDir()
+	irPath := filepath.Join(dir, "prompt.ir.json")
+	if err := os.WriteFile(irPath, []byte("old"), 0644); err != nil {
+		t.Fatalf("Failed to write prompt.ir.json: %v", err)
+	}
+
+	var txn Transaction
+	txn.Add(irPath, "prompt.ir.json", []byte("new ir"), 0644)
+	txn.Add(filepath.Join(dir, "missing", "prompt.ir.schema.json"), "prompt.ir.schema.json", []byte("new schema"), 0644)
+	err := txn.Commit()
+	if promptforge.CodeOf(err) != promptforge.CodeNotFound || !strings.Contains(err.Error(), "cannot write prompt.ir.schema.json") {
+		t.Fatalf("Expected a not-found write error for the schema, got %v", err)
+	}
+
+	if data, _ := os.ReadFile(irPath); string(data) != "old" {
+		t.Errorf("prompt.ir.json was replaced by a failed transaction: %q", data)
+	}
+	assertNoTempFiles(t, dir)
+}
+
+func TestTransaction_RollsBackFailedRename(t *testing.T) {
+	dir := t.TempDir()
+	irPath := filepath.Join(dir, "prompt.ir.json")
+	newPath := filepath.Join(dir, "prompt.ir.schema.json")
+	explainPath := filepath.Join(dir, "explain")
+	if err := os.WriteFile(irPath, []byte("old"), 0644); err != nil {
+		t.Fatalf("Failed to write prompt.ir.json: %v", err)
+	}
+	// A non-empty directory cannot be replaced by a file, so the last rename fails.
+	if err := os.MkdirAll(filepath.Join(explainPath, "keep"), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+
+	var txn Transaction
+	txn.Add(irPath, "prompt.ir.json", []byte("new ir"), 0644)
+	txn.Add(newPath, "prompt.ir.schema.json", []byte("new schema"), 0644)
+	txn.Add(explainPath, "prompt.ir.explain.json", []byte("new explain"), 0644)
+	if err := txn.Commit(); err == nil {
+		t.Fatal("Commit() should fail when a target cannot be replaced")
+	}
+
+	if data, _ := os.ReadFile(irPath); string(data) != "old" {
+		t.Errorf("prompt.ir.json was not restored: %q", data)
+	}
+	if _, err := os.Stat(newPath); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("prompt.ir.schema.json should have been removed, got %v", err)
+	}
+	assertNoTempFiles(t, dir)
+}
+
+func TestLock_SerializesHolders(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".promptforge", "compile.lock")
+
+	lock, err := Acquire(path, 0)
+	if err != nil {
+		t.Fatalf("Acquire() failed: %v", err)
+	}
+
+	_, err = Acquire(path, 20*time.Millisecond)
+	var pfErr *promptforge.Error
+	if !errors.As(err, &pfErr) || pfErr.Code != promptforge.CodeLocked || pfErr.Hint == "" {
+		t.Fatalf("Expected a locked error with a hint, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "process ") {
+		t.Errorf("Expected the error to name the holder, got %v", err)
+	}
+
+	if err := lock.Release(); err != nil {
+		t.Fatalf("Release() failed: %v", err)
+	}
+	lock, err = Acquire(path, 0)
+	if err != nil {
+		t.Fatalf("Acquire() after Release() failed: %v", err)
+	}
+	lock.Release()
+}
+
+func TestLock_TakesOverStaleLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "compile.lock")
+	if err := os.WriteFile(path, []byte("999999\n"), 0644); err != nil {
+		t.Fatalf("Failed to write lock file: %v", err)
+	}
+	old := time.Now().Add(-2 * StaleLockAge)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatalf("Failed to age lock file: %v", err)
+	}
+
+	lock, err := Acquire(path, 0)
+	if err != nil {
+		t.Fatalf("Acquire() should take over a stale lock: %v", err)
+	}
+	lock.Release()
+}
+
+func TestLock_ReleaseKeepsTakenOverLock(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "compile.lock")
+
+	first, err := Acquire(path, 0)
+	if err != nil {
+		t.Fatalf("Acquire() failed: %v", err)
+	}
+	old := time.Now().Add(-2 * StaleLockAge)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatalf("Failed to age lock file: %v", err)
+	}
+	second, err := Acquire(path, 0)
+	if err != nil {
+		t.Fatalf("Acquire() should take over a stale lock: %v", err)
+	}
+
+	err = first.Release()
+	var pfErr *promptforge.Error
+	if !errors.As(err, &pfErr) || pfErr.Code != promptforge.CodeLocked {
+		t.Fatalf("Expected Release() of a taken-over lock to fail with %s, got %v", promptforge.CodeLocked, err)
+	}
+	if _, err := Acquire(path, 0); err == nil {
+		t.Fatal("The new holder's lock should survive the old holder's Release()")
+	}
+
+	if err := second.Release(); err != nil {
+		t.Fatalf("Release() failed: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Expected the lock file to be removed, stat: %v", err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", dir, err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("Expected no files left behind, got %v", entries)
+	}
+}
+
+func assertNoTempFiles(t *testing.T, dir string) {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", dir, err)
+	}
+	for _, entry := range entries {
+		if strings.Contains(entry.Name(), ".tmp-") {
+			t.Errorf("Temporary file %s was left behind", entry.Name())
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package atomicfile
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/promptforge/promptforge"
+)
+
+// StaleLockAge is how old a lock file must be before it is considered left over
+// by a process that crashed, and taken over.
+const StaleLockAge = 2 * time.Minute
+
+// lockPoll is how often Acquire retries a held lock.
+const lockPoll = 50 * time.Millisecond
+
+// Lock is an advisory lock held through a lock file. It serializes processes
+// that agree to acquire it; it does not stop other writers.
+type Lock struct {
+	path string
+
+	// owner is the content of the lock file: the holder's PID and a random
+	// token, so a lock taken over by another process is not released by this one.
+	owner string
+}
+
+// Acquire creates the lock file at path, waiting up to wait for another holder
+// to release it. A lock file older than StaleLockAge is removed and taken over.
+// When the lock stays held, the error has code promptforge.CodeLocked and names
+// the process holding it.
+func Acquire(path string, wait time.Duration) (*Lock, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, promptforge.WriteError(err, "lock file", path)
+	}
+
+	owner := fmt.Sprintf("%d %s", os.Getpid(), newToken())
+	deadline := time.Now().Add(wait)
+	for {
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err == nil {
+			_, err = fmt.Fprintf(f, "%s\n", owner)
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				os.Remove(path)
+				return nil, promptforge.WriteError(err, "lock file", path)
+			}
+			return &Lock{path: path, owner: owner}, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, promptforge.WriteError(err, "lock file", path)
+		}
+
+		info, statErr := os.Stat(path)
+		if statErr == nil && time.Since(info.ModTime()) > StaleLockAge {
+			removeStale(path, info)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, errLocked(path)
+		}
+		time.Sleep(lockPoll)
+	}
+}
+
+// Release removes the lock file. A lock that another process has taken over as
+// stale is left in place, and Release reports it with code
+// promptforge.CodeLocked.
+func (l *Lock) Release() error {
+	data, err := os.ReadFile(l.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return promptforge.ReadError(err, "lock file", l.path)
+	}
+	if strings.TrimSpace(string(data)) != l.owner {
+		e := promptforge.Errorf(promptforge.CodeLocked, "lock %s was taken over by another process", l.path)
+		e.File = l.path
+		return e
+	}
+	if err := os.Remove(l.path); err != nil && !os.IsNotExist(err) {
+		return promptforge.Wrap(err, promptforge.IOCode(err), "cannot remove lock file %s", l.path)
+	}
+	return nil
+}
+
+// removeStale removes the stale lock file described by stale. The file is first
+// renamed to a unique name, which only one process can do, and removed only if it
+// is still the file that was found stale; a lock created in the meantime by
+// another process is put back.
+func removeStale(path string, stale os.FileInfo) {
+	claimed := path + ".stale-" + newToken()
+	if err := os.Rename(path, claimed); err != nil {
+		return
+	}
+	if info, err := os.Stat(claimed); err == nil && os.SameFile(info, stale) {
+		os.Remove(claimed)
+		return
+	}
+	if err := os.Link(claimed, path); err != nil && !errors.Is(err, os.ErrExist) {
+		os.Rename(claimed, path)
+	}
+	os.Remove(claimed)
+}
+
+// newToken returns a random hex string that tells lock holders apart.
+func newToken() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return hex.EncodeToString(b)
+}
+
+// errLocked reports a lock that is still held, naming its holder when the lock
+// file records one.
+func errLocked(path string) error {
+	holder := "another process"
+	if data, err := os.ReadFile(path); err == nil {
+		if fields := strings.Fields(string(data)); len(fields) > 0 {
+			if pid, err := strconv.Atoi(fields[0]); err == nil {
+				holder = fmt.Sprintf("process %d", pid)
+			}
+		}
+	}
+	e := promptforge.Errorf(promptforge.CodeLocked, "%s holds the lock %s", holder, path)
+	e.File = path
+	return e.WithHint("Wait for it to finish, or delete the lock file if no promptforge command is running")
+}
@@ -1,109 +1,670 @@
 package cli
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"runtime/debug"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/promptforge/promptforge"
+	"github.com/promptforge/promptforge/internal/codegen"
 	"github.com/promptforge/promptforge/internal/commands"
+	"github.com/promptforge/promptforge/internal/provider"
 )
 
-// Execute runs the CLI application.
+// Version is the version printed by --version. Release builds set it with
+// -ldflags "-X github.com/promptforge/promptforge/internal/cli.Version=v1.2.3";
+// otherwise the module version from the build info is used.
+var Version = "dev"
+
+// invocation is a parsed command line.
+type invocation struct {
+	env   *commands.Env
+	cmd   *command
+	args  []string
+	flags flagSet
+
+	// commands is the command table, for help and completion.
+	commands []*command
+}
+
+// Execute runs the CLI application with the process arguments and streams.
 func Execute() error {
-	if len(os.Args) < 2 {
-		printHelp()
-		return fmt.Errorf("expected command: init, compile, lint, templates, migrate, or audit")
-	}
+	return Run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr)
+}
 
-	command := os.Args[1]
+// Run runs the CLI with args (without the program name), reading from stdin and
+// printing to stdout and stderr. It returns the error of the command, which the
+// caller reports.
+func Run(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	table := commandTable()
 
-	// Handle help flags
-	if command == "--help" || command == "-h" || command == "help" {
-		printHelp()
+	inv, err := parse(table, args)
+	if err != nil {
+		if inv != nil && (inv.flags.bool("json") || inv.flags.value("format") == "json") {
+			var command string
+			if inv.cmd != nil {
+				command = inv.cmd.path()
+			}
+			return printEnvelope(stdout, commands.NewEnvelope(command, nil, "", 0, err), err)
+		}
+		if inv == nil || inv.cmd == nil {
+			printHelp(stderr, table)
+		}
+		return err
+	}
+
+	switch {
+	case inv.flags.bool("version"):
+		fmt.Fprintf(stdout, "promptforge %s\n", version())
+		return nil
+	case inv.cmd == nil && inv.flags.bool("help"):
+		printHelp(stdout, table)
+		return nil
+	case inv.cmd == nil:
+		printHelp(stderr, table)
+		return usageErrorf("expected command: %s", commandNames(table))
+	case inv.flags.bool("help"):
+		printCommandHelp(stdout, inv.cmd)
 		return nil
 	}
 
-	switch command {
-	case "init":
-		var description string
-		var templateName string
-		for i := 2; i < len(os.Args); i++ {
-			arg := os.Args[i]
-			switch arg {
-			case "--template":
-				if i+1 >= len(os.Args) {
-					return fmt.Errorf("missing value for --template")
+	inv.env, err = newEnv(inv.flags, inv.cmd.formats, stdin, stdout, stderr)
+	if err != nil {
+		return err
+	}
+	if inv.env.JSON {
+		return runJSON(inv, stdout)
+	}
+	return runCommand(inv)
+}
+
+// runCommand checks the argument count and runs the command.
+func runCommand(inv *invocation) error {
+	if len(inv.args) > inv.cmd.maxArgs && inv.cmd.maxArgs >= 0 {
+		return usageErrorf("usage: %s", inv.cmd.usageLine())
+	}
+	return inv.cmd.run(inv)
+}
+
+// runJSON runs the command with its text output captured and prints the result
+// envelope to stdout instead.
+func runJSON(inv *invocation, stdout io.Writer) error {
+	var output bytes.Buffer
+	inv.env.Stdout = &output
+	inv.env.Result = &commands.Result{}
+
+	start := time.Now()
+	err := runCommand(inv)
+	envelope := commands.NewEnvelope(inv.cmd.path(), inv.env.Result, output.String(), time.Since(start), err)
+	return printEnvelope(stdout, envelope, err)
+}
+
+// printEnvelope prints envelope as JSON and returns err, the command's error.
+func printEnvelope(w io.Writer, envelope *commands.Envelope, err error) error {
+	data, marshalErr := json.MarshalIndent(envelope, "", "  ")
+	if marshalErr != nil {
+		return promptforge.Wrap(marshalErr, promptforge.CodeInternal, "failed to encode result envelope")
+	}
+	fmt.Fprintln(w, string(data))
+	return err
+}
+
+// parse splits args into the command, its arguments and flags. Flags may appear
+// anywhere; "--" ends them.
+func parse(table []*command, args []string) (*invocation, error) {
+	inv := &invocation{flags: flagSet{}, commands: table}
+
+	flagsDone := false
+	for i := 0; i < len(args); {
+		arg := args[i]
+		if !flagsDone && arg == "--" {
+			flagsDone = true
+			i++
+			continue
+		}
+
+		if flagsDone || !isFlag(arg) {
+			switch {
+			case inv.cmd == nil && arg == "help":
+				// promptforge help [command]
+				inv.flags["help"] = []string{"true"}
+				if i+1 < len(args) {
+					inv.cmd = findCommand(table, args[i+1])
+					if inv.cmd == nil {
+						return inv, usageErrorf("unknown command: %s (expected: %s)", args[i+1], commandNames(table))
+					}
 				}
-				templateName = os.Args[i+1]
-				i++
-			default:
-				if strings.HasPrefix(arg, "-") {
-					return fmt.Errorf("unknown flag for init: %s", arg)
+				return inv, nil
+			case inv.cmd == nil:
+				inv.cmd = findCommand(table, arg)
+				if inv.cmd == nil {
+					return inv, usageErrorf("unknown command: %s (expected: %s)", arg, commandNames(table))
 				}
-				if description != "" {
-					description += " "
+			case len(inv.args) == 0 && len(inv.cmd.subcommands) > 0:
+				sub := findCommand(inv.cmd.subcommands, arg)
+				if sub == nil {
+					return inv, usageErrorf("unknown %s subcommand: %s (expected: %s)", inv.cmd.name, arg, commandNames(inv.cmd.subcommands))
 				}
-				description += arg
+				inv.cmd = sub
+			default:
+				inv.args = append(inv.args, arg)
 			}
+			i++
+			continue
 		}
-		return commands.Init(description, templateName)
-	case "compile":
-		explain := false
-		for i := 2; i < len(os.Args); i++ {
-			arg := os.Args[i]
-			switch arg {
-			case "--explain":
-				explain = true
-			default:
-				return fmt.Errorf("unknown flag for compile: %s", arg)
+
+		specs := globalFlags
+		if inv.cmd != nil {
+			specs = append(append([]flagSpec{}, inv.cmd.flags...), globalFlags...)
+		}
+		spec, ok := lookupFlag(specs, arg)
+		if !ok {
+			if inv.cmd == nil {
+				return inv, usageErrorf("unknown flag: %s", arg)
 			}
+			return inv, usageErrorf("unknown flag for %s: %s", inv.cmd.path(), arg)
 		}
-		return commands.Compile(explain)
-	case "lint":
-		return commands.Lint()
-	case "templates":
-		return commands.ListTemplates()
-	case "migrate":
-		return commands.Migrate()
-	case "audit":
-		return commands.Audit()
-	default:
-		printHelp()
-		return fmt.Errorf("unknown command: %s (expected: init, compile, lint, templates, migrate, or audit)", command)
+		n, err := parseFlag(spec, args, i, inv.flags)
+		if err != nil {
+			return inv, err
+		}
+		i += n
+	}
+
+	// A command with subcommands runs its first one, except for --help
+	if inv.cmd != nil && inv.cmd.run == nil && !inv.flags.bool("help") {
+		inv.cmd = inv.cmd.subcommands[0]
 	}
+	return inv, nil
 }
 
-// printHelp displays usage information.
-func printHelp() {
-	fmt.Println("PromptForge - Compile human intent into machine-enforceable prompt contracts")
-	fmt.Println()
-	fmt.Println("Usage:")
-	fmt.Println("  promptforge init [description]    Initialize a new project")
-	fmt.Println("  promptforge compile                Compile plan.md to prompt.ir.json")
-	fmt.Println("  promptforge lint                   Lint plan.md and report issues")
-	fmt.Println("  promptforge templates              List available templates")
-	fmt.Println("  promptforge migrate                Upgrade prompt.ir.json to current version")
-	fmt.Println("  promptforge audit                  Validate prompt.ir.json integrity")
-	fmt.Println()
-	fmt.Println("Commands:")
-	fmt.Println("  init      Create promptforge/plan.md with your rough idea")
-	fmt.Println("            If description is provided, it populates the Goal section")
-	fmt.Println("            Use --template <name> to start from a preset")
-	fmt.Println()
-	fmt.Println("  compile   Read promptforge/plan.md and generate prompt.ir.json")
-	fmt.Println("            Use --explain to write prompt.ir.explain.json")
-	fmt.Println("  lint      Analyze promptforge/plan.md and print diagnostics")
-	fmt.Println("  templates List built-in plan templates")
-	fmt.Println("  migrate   Upgrade prompt.ir.json to the latest IR format")
-	fmt.Println("  audit     Validate prompt.ir.json and schema compatibility")
-	fmt.Println()
-	fmt.Println("Examples:")
-	fmt.Println("  promptforge init \"I want a chatbot assistant\"")
-	fmt.Println("  promptforge compile")
-	fmt.Println("  promptforge lint")
-	fmt.Println("  promptforge templates")
-	fmt.Println("  promptforge migrate")
-	fmt.Println("  promptforge audit")
-	fmt.Println()
-	fmt.Println("For more information, see: https://github.com/promptforge/promptforge")
+// newEnv builds the command environment from the global flags.
+func newEnv(flags flagSet, formats []string, stdin io.Reader, stdout, stderr io.Writer) (*commands.Env, error) {
+	env := &commands.Env{
+		PlanPath: flags.value("plan"),
+		OutDir:   flags.value("out"),
+		Quiet:    flags.bool("quiet"),
+		Stdin:    stdin,
+		Stdout:   stdout,
+		Stderr:   stderr,
+	}
+
+	switch format := flags.value("format"); {
+	case format == "" || format == "text":
+		env.JSON = flags.bool("json")
+	case format == "json":
+		env.JSON = true
+	case !slices.Contains(formats, format):
+		expected := append([]string{"text"}, formats...)
+		return nil, usageErrorf("invalid --format %q (expected %s or json)", format, strings.Join(expected, ", "))
+	}
+
+	projectDir := flags.value("project-dir")
+	if projectDir == "" {
+		projectDir = "."
+	}
+	absDir, err := filepath.Abs(projectDir)
+	if err != nil {
+		return nil, promptforge.Wrap(err, promptforge.CodeInvalidArgument, "failed to resolve project directory %s", projectDir)
+	}
+	env.ProjectDir = absDir
+	return env, nil
+}
+
+// version returns Version, or the module version for builds installed with
+// go install.
+func version() string {
+	if Version != "dev" {
+		return Version
+	}
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+	return Version
+}
+
+// planArg makes a plan argument, such as "-" for stdin, override --plan.
+func planArg(inv *invocation) {
+	if len(inv.args) > 0 {
+		inv.env.PlanPath = inv.args[0]
+	}
+}
+
+// usageErrorf returns an error for a malformed command line, which exits with
+// promptforge.ExitUsage.
+func usageErrorf(format string, args ...interface{}) error {
+	return promptforge.Errorf(promptforge.CodeUsage, format, args...)
+}
+
+// keyValues parses repeated name=value flags into a map.
+func keyValues(inv *invocation, flag, expected string) (map[string]string, error) {
+	values := make(map[string]string)
+	for _, pair := range inv.flags[flag] {
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok || name == "" {
+			return nil, usageErrorf("invalid --%s %q (expected %s)", flag, pair, expected)
+		}
+		values[name] = value
+	}
+	return values, nil
+}
+
+// commandTable returns every command in the order help lists them.
+func commandTable() []*command {
+	table := []*command{
+		{
+			name:    "init",
+			args:    "[description]",
+			maxArgs: -1,
+			summary: "Initialize a new project",
+			help: []string{
+				"Create promptforge/plan.md (or --plan) with your rough idea.",
+				"If description is provided, it populates the Goal section.",
+				"Required template parameters that are not set are prompted for",
+				"when stdin is a terminal.",
+			},
+			flags: []flagSpec{
+				{name: "template", arg: "name", usage: "Start from a preset"},
+				{name: "set", arg: "name=value", usage: "Fill a template parameter (repeatable; list values are comma-separated)"},
+			},
+			examples: []string{
+				`promptforge init "I want a chatbot assistant"`,
+				"promptforge init --template triage --set domain=Payments --set categories=bug,billing",
+			},
+			run: func(inv *invocation) error {
+				var params map[string]string
+				if len(inv.flags["set"]) > 0 {
+					var err error
+					if params, err = keyValues(inv, "set", "name=value"); err != nil {
+						return err
+					}
+				}
+				return commands.Init(inv.env, strings.Join(inv.args, " "), inv.flags.value("template"), params)
+			},
+		},
+		{
+			name:    "compile",
+			args:    "[plan|-]",
+			maxArgs: 1,
+			summary: "Compile plan.md to prompt.ir.json",
+			help: []string{
+				"Read promptforge/plan.md (or the given plan) and generate",
+				"prompt.ir.json and prompt.ir.schema.json in the project root",
+				"(or --out). With - the plan is read from stdin; with - or",
+				"--out - the IR is printed to stdout and no files are written.",
+			},
+			flags: []flagSpec{
+				{name: "explain", usage: "Also write prompt.ir.explain.json"},
+			},
+			examples: []string{
+				"promptforge compile",
+				"promptforge --project-dir examples/refunds --out build compile --explain",
+				"cat plan.md | promptforge compile - > prompt.ir.json",
+			},
+			run: func(inv *invocation) error {
+				planArg(inv)
+				return commands.Compile(inv.env, inv.flags.bool("explain"))
+			},
+		},
+		{
+			name:    "lint",
+			args:    "[plan|-]",
+			maxArgs: 1,
+			summary: "Lint plan.md and report issues",
+			help: []string{
+				"Analyze promptforge/plan.md (or the given plan, - for stdin)",
+				"and print diagnostics.",
+				"--json lists diagnostics and their fixes in the result envelope.",
+			},
+			flags: []flagSpec{
+				{name: "fix", usage: "Apply available fixes to plan.md first"},
+			},
+			examples: []string{"promptforge lint --fix", "promptforge lint --json", "promptforge lint - < plan.md"},
+			run: func(inv *invocation) error {
+				planArg(inv)
+				return commands.Lint(inv.env, inv.flags.bool("fix"))
+			},
+		},
+		{
+			name:    "fmt",
+			args:    "[plan|-]",
+			maxArgs: 1,
+			summary: "Rewrite plan.md in canonical form",
+			help: []string{
+				"Rewrite promptforge/plan.md (or the given plan) in canonical",
+				"form. With - the plan is read from stdin and printed to stdout.",
+			},
+			flags: []flagSpec{
+				{name: "check", usage: "Print a diff and fail if plan.md is not formatted"},
+			},
+			examples: []string{"promptforge fmt --check", "promptforge fmt - < draft.md > plan.md"},
+			run: func(inv *invocation) error {
+				planArg(inv)
+				return commands.Fmt(inv.env, inv.flags.bool("check"))
+			},
+		},
+		{
+			name:    "templates",
+			summary: "List available templates",
+			help: []string{
+				"List plan templates (built-in, ~/.config/promptforge/templates",
+				"and .promptforge/templates; project templates win).",
+			},
+			examples: []string{"promptforge templates", "promptforge templates show code-review"},
+			subcommands: []*command{
+				{
+					name:    "list",
+					summary: "List available templates",
+					run: func(inv *invocation) error {
+						return commands.ListTemplates(inv.env)
+					},
+				},
+				{
+					name:    "show",
+					args:    "<name>",
+					maxArgs: 1,
+					summary: "Print a template",
+					run: func(inv *invocation) error {
+						if len(inv.args) != 1 {
+							return usageErrorf("usage: %s", inv.cmd.usageLine())
+						}
+						return commands.ShowTemplate(inv.env, inv.args[0])
+					},
+				},
+				{
+					name:    "add",
+					args:    "<file>",
+					maxArgs: 1,
+					summary: "Install a template into the project",
+					flags: []flagSpec{
+						{name: "user", usage: "Install it for the current user instead"},
+					},
+					run: func(inv *invocation) error {
+						if len(inv.args) != 1 {
+							return usageErrorf("usage: %s", inv.cmd.usageLine())
+						}
+						return commands.AddTemplate(inv.env, inv.args[0], inv.flags.bool("user"))
+					},
+				},
+				{
+					name:    "verify",
+					summary: "Lint, compile and validate every template",
+					run: func(inv *invocation) error {
+						return commands.VerifyTemplates(inv.env)
+					},
+				},
+			},
+		},
+		{
+			name:     "migrate",
+			summary:  "Upgrade prompt.ir.json to current version",
+			help:     []string{"Upgrade prompt.ir.json to the latest IR format."},
+			examples: []string{"promptforge migrate"},
+			run: func(inv *invocation) error {
+				return commands.Migrate(inv.env)
+			},
+		},
+		{
+			name:    "audit",
+			summary: "Validate prompt.ir.json integrity",
+			help: []string{
+				"Validate prompt.ir.json and schema compatibility, and scan the",
+				"prompt text for secrets, personal data and prompt injection.",
+				"Each issue has a stable code (PF3xx, PF4xx), the artifact it",
+				"concerns and a JSON pointer into it. --format sarif prints a",
+				"SARIF 2.1.0 log for code scanning.",
+				"--policy checks the IR against an organization policy pack",
+				"(JSON or YAML) and reports each violated policy as PF5xx.",
+			},
+			flags: []flagSpec{
+				{name: "policy", arg: "file", usage: "Check the IR against a policy pack (repeatable)"},
+				{name: "format", arg: "text|json|sarif", usage: "Output format; sarif prints a SARIF 2.1.0 log"},
+				{name: "fail-on", arg: "warn|error", usage: "Fail on issues of this severity or worse (default: error)"},
+			},
+			formats: []string{"sarif"},
+			examples: []string{
+				"promptforge audit",
+				"promptforge audit --format sarif --fail-on warn > audit.sarif",
+				"promptforge audit --policy security/policy.yaml",
+			},
+			run: func(inv *invocation) error {
+				return commands.Audit(inv.env, inv.flags["policy"], inv.flags.value("format") == "sarif", inv.flags.value("fail-on"))
+			},
+		},
+		{
+			name:    "export-schema",
+			summary: "Generate JSON Schema, TypeScript and Go types",
+			help: []string{
+				"Write input/output JSON Schemas, TypeScript interfaces and Go",
+				"structs for prompt.ir.json to promptforge/generated (or --out).",
+			},
+			flags: []flagSpec{
+				{name: "package", arg: "name", usage: "Package clause of the generated Go file"},
+			},
+			examples: []string{"promptforge export-schema --package contract"},
+			run: func(inv *invocation) error {
+				return commands.ExportSchema(inv.env, inv.flags.value("package"))
+			},
+		},
+		{
+			name:    "emit",
+			args:    "[ir|-]",
+			maxArgs: 1,
+			summary: "Print one artifact for the IR to stdout",
+			help: []string{
+				"Print a single artifact for prompt.ir.json to stdout. The IR is",
+				"read from the given file, from stdin with -, and otherwise",
+				"from the project.",
+				"Targets: " + strings.Join(codegen.Targets, ", ") + ".",
+				"openai prints a chat completions request with the system",
+				"prompt and the output schema as response_format.",
+			},
+			flags: []flagSpec{
+				{name: "target", arg: "name", usage: "Artifact to print (required)"},
+				{name: "model", arg: "name", usage: "Model named in the openai request"},
+				{name: "package", arg: "name", usage: "Package clause of the go target"},
+				{name: "var", arg: "key=value", usage: "Render a variable into the prompt (repeatable)"},
+			},
+			examples: []string{
+				"promptforge emit --target openai - < prompt.ir.json",
+				"promptforge compile - < plan.md | promptforge emit --target output-schema -",
+			},
+			run: func(inv *invocation) error {
+				target := inv.flags.value("target")
+				if target == "" {
+					return usageErrorf("missing --target (expected one of: %s)", strings.Join(codegen.Targets, ", "))
+				}
+				values, err := keyValues(inv, "var", "key=value")
+				if err != nil {
+					return err
+				}
+				var irPath string
+				if len(inv.args) > 0 {
+					irPath = inv.args[0]
+				}
+				opts := codegen.Options{GoPackage: inv.flags.value("package"), Model: inv.flags.value("model")}
+				return commands.Emit(inv.env, irPath, target, opts, values)
+			},
+		},
+		{
+			name:    "render",
+			summary: "Print the system prompt with variables filled in",
+			help: []string{
+				"Substitute {{variables}} declared in plan.md into prompt.ir.json.",
+				"--json returns the rendered IR as the envelope's result.",
+			},
+			flags: []flagSpec{
+				{name: "var", arg: "key=value", usage: "Set a variable (repeatable)"},
+			},
+			examples: []string{"promptforge render --var company_name=Acme"},
+			run: func(inv *invocation) error {
+				values, err := keyValues(inv, "var", "key=value")
+				if err != nil {
+					return err
+				}
+				return commands.Render(inv.env, values)
+			},
+		},
+		{
+			name:    "test",
+			summary: "Check recorded responses against the contract",
+			help: []string{
+				"Check the recorded cases in promptforge/tests/*.yaml against",
+				"prompt.ir.json without calling a model.",
+			},
+			flags: []flagSpec{
+				{name: "junit", arg: "file", usage: "Also write a JUnit XML report (- for stdout)"},
+			},
+			examples: []string{"promptforge test --junit promptforge-tests.xml"},
+			run: func(inv *invocation) error {
+				return commands.Test(inv.env, inv.flags.value("junit"))
+			},
+		},
+		{
+			name:    "run",
+			summary: "Call a model and check its response",
+			help: []string{
+				"Render prompt.ir.json, send it with the JSON payload from",
+				"--input <file> to a model and check the response.",
+				"--provider mock (default) replies from --fixture <file>",
+				"(promptforge/mock.yaml); --provider openai calls an",
+				"OpenAI-compatible API (--model, --base-url, $OPENAI_API_KEY).",
+			},
+			flags: []flagSpec{
+				{name: "input", arg: "file", usage: "JSON file with the input payload (- for stdin)"},
+				{name: "provider", arg: "name", usage: "mock (default) or openai"},
+				{name: "fixture", arg: "file", usage: "Mock replies (default: promptforge/mock.yaml)"},
+				{name: "model", arg: "name", usage: "Model name"},
+				{name: "base-url", arg: "url", usage: "OpenAI-compatible API base URL"},
+				{name: "var", arg: "key=value", usage: "Set a variable (repeatable)"},
+			},
+			examples: []string{
+				"promptforge run --provider mock --input in.json",
+				"promptforge run --provider openai --base-url http://localhost:8080/v1 --model local --input in.json",
+			},
+			run: func(inv *invocation) error {
+				values, err := keyValues(inv, "var", "key=value")
+				if err != nil {
+					return err
+				}
+				providerName := inv.flags.value("provider")
+				if providerName == "" {
+					providerName = "mock"
+				}
+				opts := provider.Options{
+					Fixture: inv.flags.value("fixture"),
+					Model:   inv.flags.value("model"),
+					BaseURL: inv.flags.value("base-url"),
+				}
+				return commands.Run(inv.env, providerName, inv.flags.value("input"), opts, values)
+			},
+		},
+		{
+			name:    "validate-output",
+			args:    "<file|->",
+			maxArgs: 1,
+			summary: "Check a model response against the contract",
+			help: []string{
+				"Check a saved model response against prompt.ir.json.",
+				"--repair applies safe fixes first (strip code fences, extract",
+				"the JSON, fix enum case, drop undeclared properties).",
+			},
+			flags: []flagSpec{
+				{name: "repair", usage: "Apply safe repairs before checking"},
+				{name: "out", arg: "file", usage: "Write the repaired response to a file (- for stdout)"},
+			},
+			examples: []string{
+				"promptforge validate-output response.txt --repair",
+				"model-cli < in.json | promptforge validate-output - --repair --out - > response.json",
+			},
+			run: func(inv *invocation) error {
+				var responsePath string
+				if len(inv.args) > 0 {
+					responsePath = inv.args[0]
+				}
+				return commands.ValidateOutput(inv.env, responsePath, inv.flags.bool("repair"), inv.flags.value("out"))
+			},
+		},
+		{
+			name:    "serve",
+			summary: "Serve the JSON API on localhost",
+			help: []string{
+				"Serve /lint, /compile, /explain, /audit, /validate-output and",
+				"/emit as a JSON API taking plan/IR content in the request body",
+				"(API description at /openapi.json).",
+			},
+			flags: []flagSpec{
+				{name: "addr", arg: "host:port", usage: "Listen address (default " + commands.DefaultServeAddr + ")"},
+				{name: "max-body", arg: "bytes", usage: "Request body limit (default 1048576)"},
+			},
+			examples: []string{"promptforge serve --addr 127.0.0.1:8787"},
+			run: func(inv *invocation) error {
+				var maxBodyBytes int64
+				if value := inv.flags.value("max-body"); value != "" {
+					n, err := strconv.ParseInt(value, 10, 64)
+					if err != nil || n <= 0 {
+						return usageErrorf("invalid --max-body %q (expected a positive number of bytes)", value)
+					}
+					maxBodyBytes = n
+				}
+				return commands.Serve(inv.env, serveAddr(inv), maxBodyBytes)
+			},
+		},
+		{
+			name:    "ui",
+			summary: "Open the UI with live lint and compile",
+			help: []string{
+				"Serve the PromptForge Lens UI with the JSON API and open it in",
+				"the browser; Plan Builder edits are linted and compiled live.",
+			},
+			flags: []flagSpec{
+				{name: "addr", arg: "host:port", usage: "Listen address (default " + commands.DefaultServeAddr + ")"},
+				{name: "no-open", usage: "Print the URL without opening a browser"},
+			},
+			examples: []string{"promptforge ui"},
+			run: func(inv *invocation) error {
+				return commands.UI(inv.env, serveAddr(inv), inv.flags.bool("no-open"))
+			},
+		},
+		{
+			name:    "completion",
+			args:    "<bash|zsh|fish>",
+			maxArgs: 1,
+			summary: "Print a shell completion script",
+			help: []string{
+				"Print a completion script for bash, zsh or fish. Load it with:",
+				"  bash: source <(promptforge completion bash)",
+				"  zsh:  promptforge completion zsh > \"${fpath[1]}/_promptforge\"",
+				"  fish: promptforge completion fish > ~/.config/fish/completions/promptforge.fish",
+			},
+			examples: []string{"promptforge completion bash"},
+			run: func(inv *invocation) error {
+				if len(inv.args) != 1 {
+					return usageErrorf("usage: %s", inv.cmd.usageLine())
+				}
+				return writeCompletion(inv.env.Stdout, inv.args[0], inv.commands)
+			},
+		},
+	}
+	for _, c := range table {
+		for _, sub := range c.subcommands {
+			sub.parent = c
+		}
+	}
+	return table
+}
+
+func serveAddr(inv *invocation) string {
+	if addr := inv.flags.value("addr"); addr != "" {
+		return addr
+	}
+	return commands.DefaultServeAddr
 }
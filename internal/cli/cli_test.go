@@ -0,0 +1,373 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/promptforge/promptforge"
+)
+
+// run runs the CLI with args and returns stdout, stderr and the error.
+func run(t *testing.T, args ...string) (string, string, error) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	err := Run(args, strings.NewReader(""), &stdout, &stderr)
+	return stdout.String(), stderr.String(), err
+}
+
+func TestRun_Version(t *testing.T) {
+	stdout, _, err := run(t, "--version")
+	if err != nil || !strings.HasPrefix(stdout, "promptforge ") {
+		t.Errorf("Expected a version line, got %q, %v", stdout, err)
+	}
+}
+
+func TestRun_Help(t *testing.T) {
+	stdout, _, err := run(t, "--help")
+	if err != nil {
+		t.Fatalf("--help failed: %v", err)
+	}
+	for _, c := range commandTable() {
+		if !strings.Contains(stdout, "  "+c.name) {
+			t.Errorf("Help does not list %s", c.name)
+		}
+	}
+
+	for _, args := range [][]string{{"compile", "--help"}, {"help", "compile"}, {"compile", "-h"}} {
+		stdout, _, err := run(t, args...)
+		if err != nil || !strings.Contains(stdout, "promptforge compile [plan|-] [flags]") || !strings.Contains(stdout, "--explain") {
+			t.Errorf("%v: expected compile help, got %q, %v", args, stdout, err)
+		}
+	}
+
+	if _, stderr, err := run(t); err == nil || !strings.Contains(stderr, "Usage:") {
+		t.Errorf("Expected usage and an error without a command, got %q, %v", stderr, err)
+	}
+}
+
+func TestRun_UsageErrors(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{args: []string{"compile", "--bogus"}, want: "unknown flag for compile: --bogus"},
+		{args: []string{"templates", "add", "--bogus"}, want: "unknown flag for templates add: --bogus"},
+		{args: []string{"export-schema", "--package"}, want: "missing value for --package"},
+		{args: []string{"bogus"}, want: "unknown command: bogus"},
+		{args: []string{"templates", "bogus"}, want: "unknown templates subcommand: bogus"},
+		{args: []string{"compile", "a", "b"}, want: "usage: promptforge compile [plan|-] [flags]"},
+		{args: []string{"compile", "-", "--explain"}, want: "--explain cannot be used"},
+		{args: []string{"emit"}, want: "missing --target"},
+		{args: []string{"--format", "yaml", "lint"}, want: `invalid --format "yaml"`},
+		{args: []string{"render", "--var", "novalue"}, want: `invalid --var "novalue"`},
+		{args: []string{"completion", "powershell"}, want: `unsupported shell "powershell"`},
+	}
+	for _, tt := range tests {
+		_, _, err := run(t, tt.args...)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%v: expected error containing %q, got %v", tt.args, tt.want, err)
+		}
+	}
+}
+
+func TestRun_GlobalFlags(t *testing.T) {
+	projectDir := t.TempDir()
+
+	// --plan and --out are resolved against --project-dir
+	stdout, _, err := run(t, "--project-dir", projectDir, "--plan", "plans/refunds.md", "init", "Triage refund requests for an online store")
+	if err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	planPath := filepath.Join(projectDir, "plans", "refunds.md")
+	if !strings.Contains(stdout, planPath) {
+		t.Errorf("Expected init to report %s, got %q", planPath, stdout)
+	}
+
+	stdout, _, err = run(t, "compile", "--explain", "-q", "--project-dir="+projectDir, "--plan", "plans/refunds.md", "--out", "build")
+	if err != nil {
+		t.Fatalf("compile failed: %v", err)
+	}
+	if stdout != "" {
+		t.Errorf("Expected no output with --quiet, got %q", stdout)
+	}
+	for _, name := range []string{"prompt.ir.json", "prompt.ir.schema.json", "prompt.ir.explain.json"} {
+		if _, err := os.Stat(filepath.Join(projectDir, "build", name)); err != nil {
+			t.Errorf("Expected build/%s: %v", name, err)
+		}
+	}
+
+	stdout, _, err = run(t, "--json", "lint", "--project-dir", projectDir, "--plan", planPath)
+	if err != nil {
+		t.Fatalf("lint failed: %v", err)
+	}
+	var envelope struct {
+		Command     string                   `json:"command"`
+		Status      string                   `json:"status"`
+		Diagnostics []map[string]interface{} `json:"diagnostics"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &envelope); err != nil {
+		t.Fatalf("Expected a JSON result envelope, got %q: %v", stdout, err)
+	}
+	if envelope.Command != "lint" || envelope.Status != "ok" || len(envelope.Diagnostics) == 0 {
+		t.Errorf("Expected an ok lint envelope with diagnostics, got %+v", envelope)
+	}
+}
+
+func TestRun_ResultEnvelope(t *testing.T) {
+	projectDir := t.TempDir()
+	if _, _, err := run(t, "--project-dir", projectDir, "init", "Triage refund requests for an online store"); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+
+	type envelope struct {
+		Command     string   `json:"command"`
+		Status      string   `json:"status"`
+		ExitCode    int      `json:"exit_code"`
+		Artifacts   []string `json:"artifacts"`
+		Diagnostics []struct {
+			Code string `json:"code"`
+		} `json:"diagnostics"`
+		Error *struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	decode := func(stdout string) envelope {
+		t.Helper()
+		var result envelope
+		if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+			t.Fatalf("Expected a JSON result envelope, got %q: %v", stdout, err)
+		}
+		return result
+	}
+
+	// compile lists the artifacts it wrote
+	stdout, _, err := run(t, "compile", "--json", "--project-dir", projectDir)
+	if err != nil {
+		t.Fatalf("compile failed: %v", err)
+	}
+	result := decode(stdout)
+	if result.Status != "ok" || result.ExitCode != 0 || len(result.Artifacts) != 2 {
+		t.Errorf("Expected an ok envelope with two artifacts, got %+v", result)
+	}
+
+	// lint reports errors as findings
+	planPath := filepath.Join(projectDir, "promptforge", "plan.md")
+	if err := os.WriteFile(planPath, []byte("# Plan\n"), 0644); err != nil {
+		t.Fatalf("Failed to write plan.md: %v", err)
+	}
+	stdout, _, err = run(t, "lint", "--json", "--project-dir", projectDir)
+	if promptforge.ExitCode(err) != promptforge.ExitFindings {
+		t.Fatalf("Expected exit code %d, got %d (%v)", promptforge.ExitFindings, promptforge.ExitCode(err), err)
+	}
+	result = decode(stdout)
+	if result.Status != "findings" || result.ExitCode != 1 || len(result.Diagnostics) == 0 {
+		t.Errorf("Expected a findings envelope with diagnostics, got %+v", result)
+	}
+
+	// usage errors still print an envelope
+	stdout, _, err = run(t, "lint", "--json", "a", "b")
+	if promptforge.ExitCode(err) != promptforge.ExitUsage {
+		t.Fatalf("Expected exit code %d, got %d (%v)", promptforge.ExitUsage, promptforge.ExitCode(err), err)
+	}
+	result = decode(stdout)
+	if result.Status != "error" || result.ExitCode != 2 || result.Error == nil || result.Error.Code != "usage" {
+		t.Errorf("Expected a usage error envelope, got %+v", result)
+	}
+}
+
+func TestRun_Pipeline(t *testing.T) {
+	projectDir := t.TempDir()
+	if _, _, err := run(t, "--project-dir", projectDir, "init", "Triage refund requests for an online store"); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	plan, err := os.ReadFile(filepath.Join(projectDir, "promptforge", "plan.md"))
+	if err != nil {
+		t.Fatalf("Failed to read plan.md: %v", err)
+	}
+
+	// compile - prints the IR and writes nothing
+	var stdout, stderr bytes.Buffer
+	if err := Run([]string{"--project-dir", projectDir, "compile", "-"}, bytes.NewReader(plan), &stdout, &stderr); err != nil {
+		t.Fatalf("compile - failed: %v (%s)", err, stderr.String())
+	}
+	piped := stdout.String()
+	irPath := filepath.Join(projectDir, "prompt.ir.json")
+	if _, err := os.Stat(irPath); !os.IsNotExist(err) {
+		t.Fatalf("compile - should not write prompt.ir.json, stat: %v", err)
+	}
+	if _, _, err := run(t, "--project-dir", projectDir, "compile"); err != nil {
+		t.Fatalf("compile failed: %v", err)
+	}
+	written, err := os.ReadFile(irPath)
+	if err != nil {
+		t.Fatalf("Failed to read prompt.ir.json: %v", err)
+	}
+	if piped != string(written) {
+		t.Errorf("compile - output differs from prompt.ir.json:\n%s\n%s", piped, written)
+	}
+
+	// emit - reads the IR from stdin
+	irFile, err := os.Open(irPath)
+	if err != nil {
+		t.Fatalf("Failed to open prompt.ir.json: %v", err)
+	}
+	defer irFile.Close()
+	stdout.Reset()
+	if err := Run([]string{"emit", "--target", "openai", "--model", "gpt-test", "-"}, irFile, &stdout, &stderr); err != nil {
+		t.Fatalf("emit failed: %v", err)
+	}
+	var request map[string]interface{}
+	if err := json.Unmarshal(stdout.Bytes(), &request); err != nil || request["model"] != "gpt-test" {
+		t.Errorf("Expected an OpenAI request for gpt-test, got %q: %v", stdout.String(), err)
+	}
+
+	// emit without - ignores stdin and reads the project
+	stdout.Reset()
+	if err := Run([]string{"--project-dir", projectDir, "emit", "--target", "openai", "--model", "gpt-test"}, strings.NewReader("not json"), &stdout, &stderr); err != nil {
+		t.Fatalf("emit should read the project IR, not stdin: %v", err)
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &request); err != nil || request["model"] != "gpt-test" {
+		t.Errorf("Expected an OpenAI request for gpt-test, got %q: %v", stdout.String(), err)
+	}
+
+	// lint - names stdin in diagnostics
+	stdout.Reset()
+	err = Run([]string{"--project-dir", projectDir, "lint", "-"}, strings.NewReader("# Prompt Plan\n"), &stdout, &stderr)
+	if err == nil || !strings.Contains(stdout.String(), "<stdin>:1:1: error PF100") {
+		t.Errorf("Expected PF100 at <stdin>, got %q, %v", stdout.String(), err)
+	}
+
+	// validate-output - --out - keeps stdout for the response
+	stdout.Reset()
+	stderr.Reset()
+	err = Run([]string{"--project-dir", projectDir, "validate-output", "-", "--repair", "--out", "-"}, strings.NewReader("```json\n{}\n```"), &stdout, &stderr)
+	if err != nil {
+		t.Fatalf("validate-output failed: %v (%s)", err, stderr.String())
+	}
+	if stdout.String() != "{}\n" || !strings.Contains(stderr.String(), "repaired") {
+		t.Errorf("Expected the repaired response on stdout and repairs on stderr, got %q and %q", stdout.String(), stderr.String())
+	}
+}
+
+func TestParse_FlagForms(t *testing.T) {
+	inv, err := parse(commandTable(), []string{"render", "--var", "a=1", "--var=b=2", "--json=false", "--", "--not-a-flag"})
+	if err != nil {
+		t.Fatalf("parse() failed: %v", err)
+	}
+	if got := inv.flags["var"]; len(got) != 2 || got[0] != "a=1" || got[1] != "b=2" {
+		t.Errorf("Expected repeated --var values, got %v", got)
+	}
+	if inv.flags.bool("json") {
+		t.Error("Expected --json=false to clear --json")
+	}
+	if len(inv.args) != 1 || inv.args[0] != "--not-a-flag" {
+		t.Errorf("Expected -- to end flags, got args %v", inv.args)
+	}
+
+	inv, err = parse(commandTable(), []string{"templates"})
+	if err != nil || inv.cmd.path() != "templates list" {
+		t.Errorf("Expected templates to run templates list, got %v", err)
+	}
+}
+
+func TestRun_Completion(t *testing.T) {
+	for _, shell := range []string{"bash", "zsh", "fish"} {
+		stdout, _, err := run(t, "completion", shell)
+		if err != nil {
+			t.Fatalf("completion %s failed: %v", shell, err)
+		}
+		for _, want := range []string{"validate-output", "project-dir", "explain", "verify"} {
+			if !strings.Contains(stdout, want) {
+				t.Errorf("%s completion is missing %s", shell, want)
+			}
+		}
+	}
+}
+
+func TestRun_AuditFailOn(t *testing.T) {
+	projectDir := t.TempDir()
+	if _, _, err := run(t, "--project-dir", projectDir, "init", "Triage refund requests for an online store"); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	if _, _, err := run(t, "--project-dir", projectDir, "compile"); err != nil {
+		t.Fatalf("compile failed: %v", err)
+	}
+	// A stale schema is a warning: it only fails with --fail-on warn
+	if err := os.WriteFile(filepath.Join(projectDir, "prompt.ir.schema.json"), []byte("{}"), 0644); err != nil {
+		t.Fatalf("Failed to write schema: %v", err)
+	}
+
+	stdout, _, err := run(t, "--project-dir", projectDir, "audit")
+	if err != nil {
+		t.Fatalf("audit failed: %v", err)
+	}
+	if !strings.Contains(stdout, "prompt.ir.schema.json: warn PF403 ") {
+		t.Errorf("Expected a PF403 warning, got %q", stdout)
+	}
+
+	_, _, err = run(t, "--project-dir", projectDir, "audit", "--fail-on", "warn")
+	if promptforge.ExitCode(err) != promptforge.ExitFindings {
+		t.Errorf("Expected --fail-on warn to exit %d, got %v", promptforge.ExitFindings, err)
+	}
+	_, _, err = run(t, "--project-dir", projectDir, "audit", "--fail-on", "info")
+	if promptforge.ExitCode(err) != promptforge.ExitUsage {
+		t.Errorf("Expected an invalid --fail-on to exit %d, got %v", promptforge.ExitUsage, err)
+	}
+
+	stdout, _, err = run(t, "--project-dir", projectDir, "audit", "--format", "sarif")
+	if err != nil {
+		t.Fatalf("audit --format sarif failed: %v", err)
+	}
+	var log struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Results []struct {
+				RuleID string `json:"ruleId"`
+				Level  string `json:"level"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &log); err != nil {
+		t.Fatalf("Expected a SARIF log, got %q: %v", stdout, err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 || len(log.Runs[0].Results) != 1 ||
+		log.Runs[0].Results[0].RuleID != "PF403" || log.Runs[0].Results[0].Level != "warning" {
+		t.Errorf("Unexpected SARIF log: %+v", log)
+	}
+
+	if _, _, err := run(t, "--project-dir", projectDir, "lint", "--format", "sarif"); promptforge.ExitCode(err) != promptforge.ExitUsage {
+		t.Errorf("Expected --format sarif to be rejected by lint, got %v", err)
+	}
+}
+
+func TestRun_AuditPolicy(t *testing.T) {
+	projectDir := t.TempDir()
+	if _, _, err := run(t, "--project-dir", projectDir, "init", "Triage refund requests for an online store"); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	if _, _, err := run(t, "--project-dir", projectDir, "compile"); err != nil {
+		t.Fatalf("compile failed: %v", err)
+	}
+	policy := "policies:\n  - id: closed-output\n    schema: {additional_properties: false}\n"
+	if err := os.WriteFile(filepath.Join(projectDir, "policy.yaml"), []byte(policy), 0644); err != nil {
+		t.Fatalf("Failed to write policy: %v", err)
+	}
+
+	stdout, _, err := run(t, "--project-dir", projectDir, "audit", "--policy", "policy.yaml")
+	if promptforge.ExitCode(err) != promptforge.ExitFindings {
+		t.Fatalf("Expected exit code %d, got %v", promptforge.ExitFindings, err)
+	}
+	if !strings.Contains(stdout, "prompt.ir.json#/output_schema: error PF502 policy closed-output: ") {
+		t.Errorf("Expected a PF502 policy violation, got %q", stdout)
+	}
+
+	_, _, err = run(t, "--project-dir", projectDir, "audit", "--policy", "missing.yaml")
+	if promptforge.ExitCode(err) != promptforge.ExitIO {
+		t.Errorf("Expected a missing policy to exit %d, got %v", promptforge.ExitIO, err)
+	}
+}
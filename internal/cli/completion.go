@@ -0,0 +1,314 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// writeCompletion prints the completion script for shell, generated from the
+// command table so that it never falls behind the commands and flags.
+func writeCompletion(w io.Writer, shell string, table []*command) error {
+	switch shell {
+	case "bash":
+		writeBashCompletion(w, table)
+	case "zsh":
+		writeZshCompletion(w, table)
+	case "fish":
+		writeFishCompletion(w, table)
+	default:
+		return usageErrorf("unsupported shell %q (expected bash, zsh, or fish)", shell)
+	}
+	return nil
+}
+
+// completedCommands returns every command that takes flags or arguments, with
+// subcommands after their parent.
+func completedCommands(table []*command) []*command {
+	var all []*command
+	for _, c := range table {
+		all = append(all, c)
+		all = append(all, c.subcommands...)
+	}
+	return all
+}
+
+// flagNames lists flags as "--name" (and "-s"), separated by spaces.
+func flagNames(flags []flagSpec) string {
+	var names []string
+	for _, f := range flags {
+		if f.short != "" {
+			names = append(names, "-"+f.short)
+		}
+		names = append(names, "--"+f.name)
+	}
+	return strings.Join(names, " ")
+}
+
+// choices returns the values of a flag whose argument is written "a|b".
+func choices(f flagSpec) []string {
+	if !strings.Contains(f.arg, "|") {
+		return nil
+	}
+	return strings.Split(f.arg, "|")
+}
+
+// takesFile reports whether the positional arguments of c are files.
+func takesFile(c *command) bool {
+	return strings.Contains(c.args, "<file>")
+}
+
+func writeBashCompletion(w io.Writer, table []*command) {
+	// Flags taking a value, by how the value is completed
+	valueFlags := map[string][]string{}
+	var allValueFlags []string
+	seen := map[string]bool{}
+	for _, c := range append([]*command{{flags: globalFlags}}, completedCommands(table)...) {
+		for _, f := range c.flags {
+			if !f.takesValue() || seen[f.name] {
+				continue
+			}
+			seen[f.name] = true
+			allValueFlags = append(allValueFlags, "--"+f.name)
+			kind := "other"
+			switch {
+			case f.arg == "dir":
+				kind = "dir"
+			case f.arg == "file":
+				kind = "file"
+			case choices(f) != nil:
+				kind = "--" + f.name
+			}
+			valueFlags[kind] = append(valueFlags[kind], "--"+f.name)
+		}
+	}
+
+	var names, groups []string
+	for _, c := range table {
+		names = append(names, c.name)
+		if len(c.subcommands) > 0 {
+			groups = append(groups, c.name)
+		}
+	}
+
+	fmt.Fprintln(w, "# bash completion for promptforge")
+	fmt.Fprintln(w, "# Load with: source <(promptforge completion bash)")
+	fmt.Fprintln(w, "_promptforge() {")
+	fmt.Fprintln(w, `    local cur="${COMP_WORDS[COMP_CWORD]}" prev="${COMP_WORDS[COMP_CWORD-1]}"`)
+	fmt.Fprintln(w, `    local cmd="" sub="" i`)
+	fmt.Fprintln(w, `    for ((i = 1; i < COMP_CWORD; i++)); do`)
+	fmt.Fprintln(w, `        case "${COMP_WORDS[i]}" in`)
+	fmt.Fprintf(w, "            %s) ((i++)) ;;\n", strings.Join(allValueFlags, "|"))
+	fmt.Fprintln(w, `            -*) ;;`)
+	fmt.Fprintln(w, `            *)`)
+	fmt.Fprintln(w, `                if [[ -z $cmd ]]; then`)
+	fmt.Fprintln(w, `                    cmd="${COMP_WORDS[i]}"`)
+	fmt.Fprintf(w, "                elif [[ -z $sub && \" %s \" == *\" $cmd \"* ]]; then\n", strings.Join(groups, " "))
+	fmt.Fprintln(w, `                    sub="${COMP_WORDS[i]}"`)
+	fmt.Fprintln(w, `                fi ;;`)
+	fmt.Fprintln(w, `        esac`)
+	fmt.Fprintln(w, `    done`)
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, `    case "$prev" in`)
+	if flags := valueFlags["dir"]; len(flags) > 0 {
+		fmt.Fprintf(w, "        %s) COMPREPLY=($(compgen -d -- \"$cur\")); return ;;\n", strings.Join(flags, "|"))
+	}
+	if flags := valueFlags["file"]; len(flags) > 0 {
+		fmt.Fprintf(w, "        %s) COMPREPLY=($(compgen -f -- \"$cur\")); return ;;\n", strings.Join(flags, "|"))
+	}
+	for _, c := range append([]*command{{flags: globalFlags}}, completedCommands(table)...) {
+		for _, f := range c.flags {
+			if values := choices(f); values != nil {
+				fmt.Fprintf(w, "        --%s) COMPREPLY=($(compgen -W \"%s\" -- \"$cur\")); return ;;\n", f.name, strings.Join(values, " "))
+			}
+		}
+	}
+	if flags := valueFlags["other"]; len(flags) > 0 {
+		fmt.Fprintf(w, "        %s) return ;;\n", strings.Join(flags, "|"))
+	}
+	fmt.Fprintln(w, `    esac`)
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "    local global=\"%s\"\n", flagNames(globalFlags))
+	fmt.Fprintln(w, `    if [[ -z $cmd ]]; then`)
+	fmt.Fprintf(w, "        COMPREPLY=($(compgen -W \"%s $global\" -- \"$cur\"))\n", strings.Join(names, " "))
+	fmt.Fprintln(w, `        return`)
+	fmt.Fprintln(w, `    fi`)
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, `    local flags="" words=""`)
+	fmt.Fprintln(w, `    case "$cmd${sub:+ $sub}" in`)
+	for _, c := range completedCommands(table) {
+		var words []string
+		for _, sub := range c.subcommands {
+			words = append(words, sub.name)
+		}
+		if c.name == "completion" {
+			words = []string{"bash", "zsh", "fish"}
+		}
+		if len(c.flags) == 0 && len(words) == 0 && !takesFile(c) {
+			continue
+		}
+		fmt.Fprintf(w, "        %q) flags=%q; words=%q", c.path(), flagNames(c.flags), strings.Join(words, " "))
+		if takesFile(c) {
+			fmt.Fprint(w, "; COMPREPLY=($(compgen -f -- \"$cur\"))")
+		}
+		fmt.Fprintln(w, " ;;")
+	}
+	fmt.Fprintln(w, `    esac`)
+	fmt.Fprintln(w, `    if [[ $cur == -* ]]; then`)
+	fmt.Fprintln(w, `        COMPREPLY=($(compgen -W "$flags $global" -- "$cur"))`)
+	fmt.Fprintln(w, `    elif [[ -n $words ]]; then`)
+	fmt.Fprintln(w, `        COMPREPLY=($(compgen -W "$words" -- "$cur"))`)
+	fmt.Fprintln(w, `    fi`)
+	fmt.Fprintln(w, "}")
+	fmt.Fprintln(w, "complete -F _promptforge promptforge")
+}
+
+// zshQuote escapes s for a single-quoted _arguments or _describe spec.
+func zshQuote(s string) string {
+	s = strings.NewReplacer("[", `\[`, "]", `\]`, ":", `\:`).Replace(s)
+	return strings.ReplaceAll(s, "'", `'\''`)
+}
+
+// zshFlagSpecs returns the _arguments specs for flags.
+func zshFlagSpecs(flags []flagSpec) []string {
+	var specs []string
+	for _, f := range flags {
+		action := ""
+		switch {
+		case f.arg == "dir":
+			action = ":dir:_directories"
+		case f.arg == "file":
+			action = ":file:_files"
+		case choices(f) != nil:
+			action = fmt.Sprintf(":%s:(%s)", f.name, strings.Join(choices(f), " "))
+		case f.takesValue():
+			action = ":" + zshQuote(f.arg) + ": "
+		}
+		description := "[" + zshQuote(f.usage) + "]"
+		if f.short != "" {
+			specs = append(specs, fmt.Sprintf("'(-%s --%s)'{-%s,--%s}'%s%s'", f.short, f.name, f.short, f.name, description, action))
+			continue
+		}
+		specs = append(specs, fmt.Sprintf("'*--%s%s%s'", f.name, description, action))
+	}
+	return specs
+}
+
+func writeZshCompletion(w io.Writer, table []*command) {
+	fmt.Fprintln(w, "#compdef promptforge")
+	fmt.Fprintln(w, "# zsh completion for promptforge")
+	fmt.Fprintln(w, `# Install with: promptforge completion zsh > "${fpath[1]}/_promptforge"`)
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "_promptforge() {")
+	fmt.Fprintln(w, "    local curcontext=\"$curcontext\" state line")
+	fmt.Fprintln(w, "    local -a global_flags commands")
+	fmt.Fprintln(w, "    global_flags=(")
+	for _, spec := range zshFlagSpecs(globalFlags) {
+		fmt.Fprintf(w, "        %s\n", spec)
+	}
+	fmt.Fprintln(w, "    )")
+	fmt.Fprintln(w, "    commands=(")
+	for _, c := range table {
+		fmt.Fprintf(w, "        '%s:%s'\n", c.name, zshQuote(c.summary))
+	}
+	fmt.Fprintln(w, "    )")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "    _arguments -C $global_flags '1: :->command' '*:: :->args'")
+	fmt.Fprintln(w, "    case $state in")
+	fmt.Fprintln(w, "        command)")
+	fmt.Fprintln(w, "            _describe -t commands 'promptforge command' commands ;;")
+	fmt.Fprintln(w, "        args)")
+	fmt.Fprintln(w, "            case $line[1] in")
+	for _, c := range table {
+		specs := zshFlagSpecs(c.flags)
+		switch {
+		case len(c.subcommands) > 0:
+			var subs []string
+			for _, sub := range c.subcommands {
+				subs = append(subs, sub.name)
+				specs = append(specs, zshFlagSpecs(sub.flags)...)
+			}
+			specs = append(specs, fmt.Sprintf("'1: :(%s)'", strings.Join(subs, " ")), "'*:file:_files'")
+		case c.name == "completion":
+			specs = append(specs, "'1: :(bash zsh fish)'")
+		case takesFile(c):
+			specs = append(specs, "'*:file:_files'")
+		}
+		fmt.Fprintf(w, "                %s) _arguments $global_flags %s ;;\n", c.name, strings.Join(specs, " "))
+	}
+	fmt.Fprintln(w, "            esac ;;")
+	fmt.Fprintln(w, "    esac")
+	fmt.Fprintln(w, "}")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, `if [[ "$funcstack[1]" == "_promptforge" ]]; then`)
+	fmt.Fprintln(w, `    _promptforge "$@"`)
+	fmt.Fprintln(w, "else")
+	fmt.Fprintln(w, "    compdef _promptforge promptforge")
+	fmt.Fprintln(w, "fi")
+}
+
+// fishQuote quotes s for fish.
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(s) + "'"
+}
+
+// writeFishFlags prints the complete lines for flags under condition.
+func writeFishFlags(w io.Writer, condition string, flags []flagSpec) {
+	for _, f := range flags {
+		line := "complete -c promptforge"
+		if condition != "" {
+			line += " -n " + fishQuote(condition)
+		}
+		if f.short != "" {
+			line += " -s " + f.short
+		}
+		line += " -l " + f.name
+		switch {
+		case f.arg == "dir":
+			line += " -x -a '(__fish_complete_directories)'"
+		case f.arg == "file":
+			line += " -r -F"
+		case choices(f) != nil:
+			line += " -x -a " + fishQuote(strings.Join(choices(f), " "))
+		case f.takesValue():
+			line += " -x"
+		}
+		fmt.Fprintf(w, "%s -d %s\n", line, fishQuote(f.usage))
+	}
+}
+
+func writeFishCompletion(w io.Writer, table []*command) {
+	fmt.Fprintln(w, "# fish completion for promptforge")
+	fmt.Fprintln(w, "# Install with: promptforge completion fish > ~/.config/fish/completions/promptforge.fish")
+	fmt.Fprintln(w, "complete -c promptforge -f")
+	writeFishFlags(w, "", globalFlags)
+	for _, c := range table {
+		fmt.Fprintf(w, "complete -c promptforge -n __fish_use_subcommand -a %s -d %s\n", c.name, fishQuote(c.summary))
+	}
+	for _, c := range table {
+		seen := "__fish_seen_subcommand_from " + c.name
+		writeFishFlags(w, seen, c.flags)
+		if takesFile(c) {
+			fmt.Fprintf(w, "complete -c promptforge -n %s -F\n", fishQuote(seen))
+		}
+		if c.name == "completion" {
+			fmt.Fprintf(w, "complete -c promptforge -n %s -a 'bash zsh fish'\n", fishQuote(seen))
+		}
+		if len(c.subcommands) == 0 {
+			continue
+		}
+		var subs []string
+		for _, sub := range c.subcommands {
+			subs = append(subs, sub.name)
+		}
+		none := seen + "; and not __fish_seen_subcommand_from " + strings.Join(subs, " ")
+		for _, sub := range c.subcommands {
+			fmt.Fprintf(w, "complete -c promptforge -n %s -a %s -d %s\n", fishQuote(none), sub.name, fishQuote(sub.summary))
+			subSeen := seen + "; and __fish_seen_subcommand_from " + sub.name
+			writeFishFlags(w, subSeen, sub.flags)
+			if takesFile(sub) {
+				fmt.Fprintf(w, "complete -c promptforge -n %s -F\n", fishQuote(subSeen))
+			}
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"strconv"
+	"strings"
+)
+
+// flagSpec describes a flag. Flags are written --name, or -short when short is
+// set, and take a value when arg is set: --name value or --name=value.
+type flagSpec struct {
+	name  string
+	short string
+	arg   string
+	usage string
+}
+
+func (f flagSpec) takesValue() bool {
+	return f.arg != ""
+}
+
+// synopsis renders the flag as shown in help, such as "-q, --quiet" or
+// "--out <dir>".
+func (f flagSpec) synopsis() string {
+	s := "--" + f.name
+	if f.short != "" {
+		s = "-" + f.short + ", " + s
+	}
+	if f.takesValue() {
+		s += " <" + f.arg + ">"
+	}
+	return s
+}
+
+// globalFlags are accepted before and after every command. A command flag of the
+// same name takes precedence.
+var globalFlags = []flagSpec{
+	{name: "project-dir", arg: "dir", usage: "Project root (default: the current directory)"},
+	{name: "plan", arg: "file", usage: "Plan to read, - for stdin (default: promptforge/plan.md)"},
+	{name: "out", arg: "dir", usage: "Directory compile and export-schema write to (compile: - for stdout)"},
+	{name: "format", arg: "text|json", usage: "Output format; json prints a result envelope"},
+	{name: "json", usage: "Short for --format json"},
+	{name: "quiet", short: "q", usage: "Only print results and errors"},
+	{name: "help", short: "h", usage: "Show help for promptforge or a command"},
+	{name: "version", usage: "Print the version and exit"},
+}
+
+// flagSet holds parsed flag values. Boolean flags are stored as "true" or
+// "false"; repeated flags keep every value in order.
+type flagSet map[string][]string
+
+// bool reports whether the boolean flag name was set.
+func (s flagSet) bool(name string) bool {
+	values := s[name]
+	return len(values) > 0 && values[len(values)-1] == "true"
+}
+
+// value returns the last value of flag name, or "" if it was not given.
+func (s flagSet) value(name string) string {
+	values := s[name]
+	if len(values) == 0 {
+		return ""
+	}
+	return values[len(values)-1]
+}
+
+// lookupFlag finds the flag written as arg ("--name", "-s", with or without
+// "=value") among specs.
+func lookupFlag(specs []flagSpec, arg string) (flagSpec, bool) {
+	name, _, _ := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+	long := strings.HasPrefix(arg, "--")
+	for _, spec := range specs {
+		if (long && spec.name == name) || (!long && spec.short != "" && spec.short == name) {
+			return spec, true
+		}
+	}
+	return flagSpec{}, false
+}
+
+// isFlag reports whether arg is written as a flag. A lone "-" is an argument.
+func isFlag(arg string) bool {
+	return len(arg) > 1 && arg[0] == '-'
+}
+
+// parseFlag stores the flag at args[i] in flags and returns how many arguments
+// it used.
+func parseFlag(spec flagSpec, args []string, i int, flags flagSet) (int, error) {
+	arg := args[i]
+	_, value, hasValue := strings.Cut(arg, "=")
+	if !spec.takesValue() {
+		if !hasValue {
+			flags[spec.name] = append(flags[spec.name], "true")
+			return 1, nil
+		}
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return 0, usageErrorf("invalid value for --%s: %q (expected true or false)", spec.name, value)
+		}
+		flags[spec.name] = append(flags[spec.name], strconv.FormatBool(b))
+		return 1, nil
+	}
+
+	if hasValue {
+		flags[spec.name] = append(flags[spec.name], value)
+		return 1, nil
+	}
+	if i+1 >= len(args) {
+		return 0, usageErrorf("missing value for --%s", spec.name)
+	}
+	flags[spec.name] = append(flags[spec.name], args[i+1])
+	return 2, nil
+}
@@ -0,0 +1,170 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// command describes a CLI command for dispatch, help and completion.
+type command struct {
+	name string
+
+	// args is the synopsis of the positional arguments, such as "<file>".
+	args string
+
+	// maxArgs is the number of positional arguments accepted; -1 means any.
+	maxArgs int
+
+	summary string
+
+	// help is the description shown by --help, one line per entry.
+	help []string
+
+	flags []flagSpec
+
+	// formats lists the values --format takes besides text and json, such as
+	// sarif for audit.
+	formats []string
+
+	examples []string
+
+	// parent is set for subcommands, such as templates for templates show.
+	parent *command
+
+	subcommands []*command
+
+	// run executes the command; nil for commands that only group subcommands.
+	run func(inv *invocation) error
+}
+
+// path returns the command as typed after promptforge, such as "templates add".
+func (c *command) path() string {
+	if c.parent != nil {
+		return c.parent.path() + " " + c.name
+	}
+	return c.name
+}
+
+// usageLine returns the synopsis shown in help and usage errors.
+func (c *command) usageLine() string {
+	line := "promptforge " + c.path()
+	if len(c.subcommands) > 0 {
+		line += " [command]"
+	}
+	if c.args != "" {
+		line += " " + c.args
+	}
+	if len(c.flags) > 0 {
+		line += " [flags]"
+	}
+	return line
+}
+
+// findCommand returns the command called name in table, or nil.
+func findCommand(table []*command, name string) *command {
+	for _, c := range table {
+		if c.name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// commandNames lists the names in table for error messages, such as
+// "init, compile, or lint".
+func commandNames(table []*command) string {
+	names := make([]string, len(table))
+	for i, c := range table {
+		names[i] = c.name
+	}
+	if len(names) < 2 {
+		return strings.Join(names, "")
+	}
+	return strings.Join(names[:len(names)-1], ", ") + ", or " + names[len(names)-1]
+}
+
+// printHelp displays usage information.
+func printHelp(w io.Writer, table []*command) {
+	fmt.Fprintln(w, "PromptForge - Compile human intent into machine-enforceable prompt contracts")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Usage:")
+	fmt.Fprintln(w, "  promptforge [global flags] <command> [flags] [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, c := range table {
+		name := c.name
+		if c.args != "" {
+			name += " " + c.args
+		}
+		fmt.Fprintf(tw, "  %s\t%s\n", name, c.summary)
+	}
+	tw.Flush()
+	fmt.Fprintln(w)
+	printFlags(w, "Global flags:", globalFlags)
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Examples:")
+	for _, c := range table {
+		for _, example := range c.examples {
+			fmt.Fprintf(w, "  %s\n", example)
+		}
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Exit codes:")
+	fmt.Fprintln(w, "  0 ok, 1 findings, 2 usage, 3 I/O, 4 validation")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run 'promptforge <command> --help' for the flags of a command.")
+	fmt.Fprintln(w, "For more information, see: https://github.com/promptforge/promptforge")
+}
+
+// printCommandHelp displays the usage, description and flags of c.
+func printCommandHelp(w io.Writer, c *command) {
+	fmt.Fprintln(w, "Usage:")
+	fmt.Fprintf(w, "  %s\n", c.usageLine())
+	fmt.Fprintln(w)
+	description := c.help
+	if len(description) == 0 {
+		description = []string{c.summary + "."}
+	}
+	for _, line := range description {
+		fmt.Fprintln(w, line)
+	}
+
+	if len(c.subcommands) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "Commands:")
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		for _, sub := range c.subcommands {
+			name := sub.name
+			if sub.args != "" {
+				name += " " + sub.args
+			}
+			fmt.Fprintf(tw, "  %s\t%s\n", name, sub.summary)
+		}
+		tw.Flush()
+	}
+	if len(c.flags) > 0 {
+		fmt.Fprintln(w)
+		printFlags(w, "Flags:", c.flags)
+	}
+	fmt.Fprintln(w)
+	printFlags(w, "Global flags:", globalFlags)
+	if len(c.examples) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "Examples:")
+		for _, example := range c.examples {
+			fmt.Fprintf(w, "  %s\n", example)
+		}
+	}
+}
+
+func printFlags(w io.Writer, title string, flags []flagSpec) {
+	fmt.Fprintln(w, title)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, f := range flags {
+		fmt.Fprintf(tw, "  %s\t%s\n", f.synopsis(), f.usage)
+	}
+	tw.Flush()
+}
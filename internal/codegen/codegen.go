@@ -0,0 +1,266 @@
+package codegen
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/promptforge/promptforge/internal/ir"
+)
+
+// Generated file names, relative to the export directory.
+const (
+	InputSchemaFile  = "prompt.input.schema.json"
+	OutputSchemaFile = "prompt.output.schema.json"
+	TypeScriptFile   = "prompt.types.ts"
+	GoFile           = "prompt_types.go"
+)
+
+// DefaultGoPackage is the package name used for generated Go code when none is given.
+const DefaultGoPackage = "contract"
+
+// Artifact is a single generated file.
+type Artifact struct {
+	Name    string
+	Content []byte
+}
+
+// Options controls code generation.
+type Options struct {
+	// GoPackage is the package clause for the generated Go file.
+	GoPackage string
+
+	// Model is the model named in the request emitted for the openai target.
+	Model string
+}
+
+// Generate produces the standalone JSON Schemas, TypeScript interfaces and Go types
+// for the input and output contracts of a compiled PromptIR.
+// Output is deterministic: the same IR always yields byte-identical artifacts.
+func Generate(promptIR *ir.PromptIR, opts Options) ([]Artifact, error) {
+	if promptIR == nil {
+		return nil, fmt.Errorf("prompt IR is nil")
+	}
+
+	inputSchema, err := InputJSONSchema(promptIR)
+	if err != nil {
+		return nil, err
+	}
+	outputSchema, err := OutputJSONSchema(promptIR)
+	if err != nil {
+		return nil, err
+	}
+	ts, err := TypeScript(promptIR)
+	if err != nil {
+		return nil, err
+	}
+	goSource, err := Go(promptIR, opts.GoPackage)
+	if err != nil {
+		return nil, err
+	}
+
+	return []Artifact{
+		{Name: InputSchemaFile, Content: inputSchema},
+		{Name: OutputSchemaFile, Content: outputSchema},
+		{Name: TypeScriptFile, Content: ts},
+		{Name: GoFile, Content: goSource},
+	}, nil
+}
+
+// InputJSONSchema returns a standalone JSON Schema document for input_schema.
+func InputJSONSchema(promptIR *ir.PromptIR) ([]byte, error) {
+	return marshalSchema(ir.ContractJSONSchema(promptIR.InputSchema, "prompt.input.schema.json", "Prompt Input"))
+}
+
+// OutputJSONSchema returns a standalone JSON Schema document for output_schema.
+func OutputJSONSchema(promptIR *ir.PromptIR) ([]byte, error) {
+	return marshalSchema(ir.ContractJSONSchema(promptIR.OutputSchema, "prompt.output.schema.json", "Prompt Output"))
+}
+
+func marshalSchema(doc map[string]interface{}) ([]byte, error) {
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON schema: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// typeKind enumerates the shapes a contract value can take.
+type typeKind int
+
+const (
+	kindAny typeKind = iota
+	kindString
+	kindNumber
+	kindInteger
+	kindBoolean
+	kindMap
+	kindArray
+	kindNamed
+)
+
+// typeRef points at the type of a field, array element or root contract.
+type typeRef struct {
+	kind  typeKind
+	named string
+	elem  *typeRef
+}
+
+// typeDef is a named object type with fields.
+type typeDef struct {
+	name        string
+	origin      string
+	description string
+	fields      []fieldDef
+}
+
+// fieldDef is a single object field.
+type fieldDef struct {
+	jsonName    string
+	identifier  string
+	description string
+	required    bool
+	enum        []interface{}
+	ref         typeRef
+}
+
+// typeModel is the language-neutral view of a contract schema.
+type typeModel struct {
+	root typeRef
+	defs []typeDef
+
+	// names holds the type names defined so far, so that derived names such as
+	// an array element's "CustomerItem" do not collide with a property's.
+	names map[string]bool
+}
+
+func buildModel(rootName string, schema ir.Schema) typeModel {
+	model := typeModel{names: make(map[string]bool)}
+	model.root = model.resolve(rootName, schema.Type, schema.Properties, schema.Required, schema.Items, "", "")
+	return model
+}
+
+// resolve maps a schema node to a type reference, defining named object types as needed.
+// origin describes where the node appears (e.g. "the customer property of Input").
+func (m *typeModel) resolve(name, kind string, properties map[string]ir.Property, required []string, items *ir.Schema, origin, description string) typeRef {
+	switch kind {
+	case "string":
+		return typeRef{kind: kindString}
+	case "number":
+		return typeRef{kind: kindNumber}
+	case "integer":
+		return typeRef{kind: kindInteger}
+	case "boolean":
+		return typeRef{kind: kindBoolean}
+	case "object":
+		if len(properties) == 0 {
+			return typeRef{kind: kindMap}
+		}
+		name = uniqueIdentifier(name, m.names)
+		m.defineObject(name, properties, required, origin, description)
+		return typeRef{kind: kindNamed, named: name}
+	case "array":
+		if items == nil {
+			return typeRef{kind: kindArray, elem: &typeRef{kind: kindAny}}
+		}
+		elem := m.resolve(name+"Item", items.Type, items.Properties, items.Required, items.Items, "an element of "+name, "")
+		return typeRef{kind: kindArray, elem: &elem}
+	default:
+		return typeRef{kind: kindAny}
+	}
+}
+
+func (m *typeModel) defineObject(name string, properties map[string]ir.Property, required []string, origin, description string) {
+	requiredSet := make(map[string]bool, len(required))
+	for _, r := range required {
+		requiredSet[r] = true
+	}
+
+	index := len(m.defs)
+	m.defs = append(m.defs, typeDef{name: name, origin: origin, description: description})
+
+	used := make(map[string]bool)
+	var fields []fieldDef
+	for _, jsonName := range ir.SortedPropertyNames(properties) {
+		prop := properties[jsonName]
+		identifier := uniqueIdentifier(exportedIdentifier(jsonName), used)
+		origin := fmt.Sprintf("the %s property of %s", jsonName, name)
+		ref := m.resolve(name+identifier, prop.Type, prop.Properties, prop.Required, prop.Items, origin, prop.Description)
+		fields = append(fields, fieldDef{
+			jsonName:    jsonName,
+			identifier:  identifier,
+			description: prop.Description,
+			required:    requiredSet[jsonName],
+			enum:        prop.Enum,
+			ref:         ref,
+		})
+	}
+	m.defs[index].fields = fields
+}
+
+var initialisms = map[string]string{
+	"api":  "API",
+	"html": "HTML",
+	"http": "HTTP",
+	"id":   "ID",
+	"ip":   "IP",
+	"json": "JSON",
+	"sql":  "SQL",
+	"uri":  "URI",
+	"url":  "URL",
+	"uuid": "UUID",
+}
+
+// exportedIdentifier converts a JSON property name into an exported identifier.
+func exportedIdentifier(name string) string {
+	parts := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var b strings.Builder
+	for _, part := range splitCamel(parts) {
+		if upper, ok := initialisms[strings.ToLower(part)]; ok {
+			b.WriteString(upper)
+			continue
+		}
+		runes := []rune(part)
+		b.WriteRune(unicode.ToUpper(runes[0]))
+		b.WriteString(string(runes[1:]))
+	}
+
+	identifier := b.String()
+	if identifier == "" {
+		return "Field"
+	}
+	if unicode.IsDigit([]rune(identifier)[0]) {
+		identifier = "X" + identifier
+	}
+	return identifier
+}
+
+// splitCamel splits camelCase words so that initialisms can be recognised.
+func splitCamel(parts []string) []string {
+	var words []string
+	for _, part := range parts {
+		start := 0
+		runes := []rune(part)
+		for i := 1; i < len(runes); i++ {
+			if unicode.IsUpper(runes[i]) && unicode.IsLower(runes[i-1]) {
+				words = append(words, string(runes[start:i]))
+				start = i
+			}
+		}
+		words = append(words, string(runes[start:]))
+	}
+	return words
+}
+
+func uniqueIdentifier(identifier string, used map[string]bool) string {
+	candidate := identifier
+	for suffix := 2; used[candidate]; suffix++ {
+		candidate = fmt.Sprintf("%s%d", identifier, suffix)
+	}
+	used[candidate] = true
+	return candidate
+}
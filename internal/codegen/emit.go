@@ -0,0 +1,93 @@
+package codegen
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/promptforge/promptforge/internal/ir"
+	"github.com/promptforge/promptforge/internal/render"
+)
+
+// Emit targets: a single artifact written to standard output by promptforge emit.
+const (
+	TargetOpenAI       = "openai"
+	TargetInputSchema  = "input-schema"
+	TargetOutputSchema = "output-schema"
+	TargetTypeScript   = "typescript"
+	TargetGo           = "go"
+)
+
+// Targets lists the emit targets.
+var Targets = []string{TargetOpenAI, TargetInputSchema, TargetOutputSchema, TargetTypeScript, TargetGo}
+
+// Emit produces the artifact for target. The openai target is an OpenAI chat
+// completions request body carrying the system prompt and the output contract as
+// a JSON Schema response format; callers append the user message. The other
+// targets are the individual files written by Generate.
+func Emit(promptIR *ir.PromptIR, target string, opts Options) ([]byte, error) {
+	if promptIR == nil {
+		return nil, fmt.Errorf("prompt IR is nil")
+	}
+
+	switch target {
+	case TargetOpenAI:
+		return OpenAIRequest(promptIR, opts.Model)
+	case TargetInputSchema:
+		return InputJSONSchema(promptIR)
+	case TargetOutputSchema:
+		return OutputJSONSchema(promptIR)
+	case TargetTypeScript:
+		return TypeScript(promptIR)
+	case TargetGo:
+		return Go(promptIR, opts.GoPackage)
+	default:
+		return nil, fmt.Errorf("unknown emit target: %s (expected one of: %s)", target, strings.Join(Targets, ", "))
+	}
+}
+
+type openAIMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type openAIJSONSchema struct {
+	Name   string                 `json:"name"`
+	Schema map[string]interface{} `json:"schema"`
+}
+
+type openAIResponseFormat struct {
+	Type       string           `json:"type"`
+	JSONSchema openAIJSONSchema `json:"json_schema"`
+}
+
+type openAIRequest struct {
+	Model          string               `json:"model,omitempty"`
+	Messages       []openAIMessage      `json:"messages"`
+	Temperature    float64              `json:"temperature"`
+	ResponseFormat openAIResponseFormat `json:"response_format"`
+}
+
+// OpenAIRequest returns an OpenAI chat completions request body for a compiled
+// (and optionally rendered) IR. The model is omitted when empty.
+func OpenAIRequest(promptIR *ir.PromptIR, model string) ([]byte, error) {
+	req := openAIRequest{
+		Model: model,
+		Messages: []openAIMessage{
+			{Role: "system", Content: render.SystemPrompt(promptIR)},
+		},
+		ResponseFormat: openAIResponseFormat{
+			Type: "json_schema",
+			JSONSchema: openAIJSONSchema{
+				Name:   "prompt_output",
+				Schema: ir.SchemaToJSONSchema(promptIR.OutputSchema),
+			},
+		},
+	}
+
+	data, err := json.MarshalIndent(req, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal OpenAI request: %w", err)
+	}
+	return append(data, '\n'), nil
+}
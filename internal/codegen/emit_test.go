@@ -0,0 +1,78 @@
+package codegen
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEmit_MatchesGenerate(t *testing.T) {
+	promptIR := loadFixtureIR(t, filepath.Join("testdata", "contract.ir.json"))
+
+	artifacts, err := Generate(promptIR, Options{GoPackage: "contract"})
+	if err != nil {
+		t.Fatalf("Generate() failed: %v", err)
+	}
+	files := map[string]string{
+		TargetInputSchema:  InputSchemaFile,
+		TargetOutputSchema: OutputSchemaFile,
+		TargetTypeScript:   TypeScriptFile,
+		TargetGo:           GoFile,
+	}
+	for target, name := range files {
+		got, err := Emit(promptIR, target, Options{GoPackage: "contract"})
+		if err != nil {
+			t.Fatalf("Emit(%s) failed: %v", target, err)
+		}
+		for _, artifact := range artifacts {
+			if artifact.Name == name && string(artifact.Content) != string(got) {
+				t.Errorf("Emit(%s) differs from the generated %s", target, name)
+			}
+		}
+	}
+}
+
+func TestEmit_OpenAI(t *testing.T) {
+	promptIR := loadFixtureIR(t, filepath.Join("testdata", "contract.ir.json"))
+
+	data, err := Emit(promptIR, TargetOpenAI, Options{Model: "gpt-test"})
+	if err != nil {
+		t.Fatalf("Emit(openai) failed: %v", err)
+	}
+
+	var req struct {
+		Model    string `json:"model"`
+		Messages []struct {
+			Role    string `json:"role"`
+			Content string `json:"content"`
+		} `json:"messages"`
+		ResponseFormat struct {
+			Type       string `json:"type"`
+			JSONSchema struct {
+				Schema map[string]interface{} `json:"schema"`
+			} `json:"json_schema"`
+		} `json:"response_format"`
+	}
+	if err := json.Unmarshal(data, &req); err != nil {
+		t.Fatalf("Emit(openai) is not JSON: %v\n%s", err, data)
+	}
+	if req.Model != "gpt-test" {
+		t.Errorf("Expected model gpt-test, got %q", req.Model)
+	}
+	if len(req.Messages) != 1 || req.Messages[0].Role != "system" || !strings.Contains(req.Messages[0].Content, promptIR.SystemRole) {
+		t.Errorf("Expected a single system message with the system role, got %+v", req.Messages)
+	}
+	if req.ResponseFormat.Type != "json_schema" || req.ResponseFormat.JSONSchema.Schema["type"] != "object" {
+		t.Errorf("Expected a json_schema response format for the output contract, got %+v", req.ResponseFormat)
+	}
+}
+
+func TestEmit_UnknownTarget(t *testing.T) {
+	promptIR := loadFixtureIR(t, filepath.Join("testdata", "contract.ir.json"))
+
+	_, err := Emit(promptIR, "anthropic", Options{})
+	if err == nil || !strings.Contains(err.Error(), "unknown emit target: anthropic") {
+		t.Fatalf("Expected an unknown target error, got %v", err)
+	}
+}
@@ -0,0 +1,307 @@
+package codegen
+
+import (
+	"fmt"
+	"go/format"
+	"go/token"
+	"strconv"
+	"strings"
+
+	"github.com/promptforge/promptforge/internal/ir"
+)
+
+// Go returns Go source declaring structs with json tags for the input and output
+// contracts, plus ValidateInput and ValidateOutput functions that check raw JSON
+// against the contract.
+func Go(promptIR *ir.PromptIR, packageName string) ([]byte, error) {
+	if packageName == "" {
+		packageName = DefaultGoPackage
+	}
+	if !token.IsIdentifier(packageName) || token.IsKeyword(packageName) {
+		return nil, fmt.Errorf("invalid Go package name: %s", packageName)
+	}
+
+	var b strings.Builder
+	b.WriteString("// Code generated by promptforge export-schema. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %s\n\n", packageName)
+	b.WriteString("import (\n\t\"encoding/json\"\n\t\"fmt\"\n\t\"math\"\n)\n")
+
+	roots := []struct {
+		name      string
+		doc       string
+		validator string
+		schemaVar string
+		schema    ir.Schema
+	}{
+		{name: "Input", doc: "Input is the payload accepted by the prompt (input_schema).", validator: "ValidateInput", schemaVar: "inputSchema", schema: promptIR.InputSchema},
+		{name: "Output", doc: "Output is the payload the model must return (output_schema).", validator: "ValidateOutput", schemaVar: "outputSchema", schema: promptIR.OutputSchema},
+	}
+
+	for _, root := range roots {
+		model := buildModel(root.name, root.schema)
+		if model.root.kind != kindNamed {
+			fmt.Fprintf(&b, "\n// %s\ntype %s %s\n", root.doc, root.name, goType(model.root))
+		}
+		for _, def := range model.defs {
+			doc := root.doc
+			if def.name != root.name {
+				doc = fmt.Sprintf("%s is %s.", def.name, def.origin)
+				if def.description != "" {
+					doc += "\n// " + goComment(def.description)
+				}
+			}
+			writeGoStruct(&b, def, doc)
+		}
+	}
+
+	for _, root := range roots {
+		fmt.Fprintf(&b, "\n// %s checks that data is a JSON document satisfying the %s contract.\n", root.validator, strings.ToLower(root.name))
+		fmt.Fprintf(&b, "func %s(data []byte) error {\n\treturn validateDocument(data, %s)\n}\n", root.validator, root.schemaVar)
+	}
+
+	for _, root := range roots {
+		fmt.Fprintf(&b, "\nvar %s = ", root.schemaVar)
+		writeGoSchemaNode(&b, root.schema.Type, root.schema.Properties, root.schema.Required, nil, root.schema.Items, root.schema.AdditionalProperties)
+		b.WriteString("\n")
+	}
+
+	b.WriteString(goValidatorRuntime)
+
+	source, err := format.Source([]byte(b.String()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated Go source: %w", err)
+	}
+	return source, nil
+}
+
+func writeGoStruct(b *strings.Builder, def typeDef, doc string) {
+	b.WriteString("\n")
+	fmt.Fprintf(b, "// %s\n", doc)
+	fmt.Fprintf(b, "type %s struct {\n", def.name)
+	for _, field := range def.fields {
+		if field.description != "" {
+			fmt.Fprintf(b, "\t// %s\n", goComment(field.description))
+		}
+		if len(field.enum) > 0 {
+			fmt.Fprintf(b, "\t// Allowed values: %s.\n", goEnumList(field.enum))
+		}
+		fieldType := goType(field.ref)
+		tag := field.jsonName
+		if !field.required {
+			tag += ",omitempty"
+			if field.ref.kind == kindNamed {
+				fieldType = "*" + fieldType
+			}
+		}
+		fmt.Fprintf(b, "\t%s %s `json:%s`\n", field.identifier, fieldType, strconv.Quote(tag))
+	}
+	b.WriteString("}\n")
+}
+
+func goType(ref typeRef) string {
+	switch ref.kind {
+	case kindString:
+		return "string"
+	case kindNumber:
+		return "float64"
+	case kindInteger:
+		return "int64"
+	case kindBoolean:
+		return "bool"
+	case kindMap:
+		return "map[string]interface{}"
+	case kindArray:
+		return "[]" + goType(*ref.elem)
+	case kindNamed:
+		return ref.named
+	default:
+		return "interface{}"
+	}
+}
+
+// writeGoSchemaNode writes the schemaNode literal checked by the generated
+// validator. additional is the node's additional_properties; false closes it.
+func writeGoSchemaNode(b *strings.Builder, kind string, properties map[string]ir.Property, required []string, enum []interface{}, items *ir.Schema, additional *bool) {
+	fmt.Fprintf(b, "&schemaNode{\nType: %s,\n", strconv.Quote(kind))
+	if additional != nil && !*additional {
+		b.WriteString("Closed: true,\n")
+	}
+	if len(required) > 0 {
+		quoted := make([]string, len(required))
+		for i, name := range required {
+			quoted[i] = strconv.Quote(name)
+		}
+		fmt.Fprintf(b, "Required: []string{%s},\n", strings.Join(quoted, ", "))
+	}
+	if len(enum) > 0 {
+		literals := make([]string, len(enum))
+		for i, value := range enum {
+			literals[i] = goLiteral(value)
+		}
+		fmt.Fprintf(b, "Enum: []interface{}{%s},\n", strings.Join(literals, ", "))
+	}
+	if len(properties) > 0 {
+		b.WriteString("Properties: []schemaField{\n")
+		for _, name := range ir.SortedPropertyNames(properties) {
+			prop := properties[name]
+			fmt.Fprintf(b, "{Name: %s, Schema: ", strconv.Quote(name))
+			writeGoSchemaNode(b, prop.Type, prop.Properties, prop.Required, prop.Enum, prop.Items, prop.AdditionalProperties)
+			b.WriteString("},\n")
+		}
+		b.WriteString("},\n")
+	}
+	if items != nil {
+		b.WriteString("Items: ")
+		writeGoSchemaNode(b, items.Type, items.Properties, items.Required, nil, items.Items, items.AdditionalProperties)
+		b.WriteString(",\n")
+	}
+	b.WriteString("}")
+}
+
+// goLiteral renders a decoded JSON value as a Go literal comparable to the
+// result of json.Unmarshal into interface{}.
+func goLiteral(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "nil"
+	case string:
+		return strconv.Quote(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return "float64(" + strconv.FormatFloat(v, 'g', -1, 64) + ")"
+	case int:
+		return "float64(" + strconv.Itoa(v) + ")"
+	default:
+		return strconv.Quote(fmt.Sprint(v))
+	}
+}
+
+func goEnumList(enum []interface{}) string {
+	values := make([]string, len(enum))
+	for i, value := range enum {
+		if s, ok := value.(string); ok {
+			values[i] = strconv.Quote(s)
+			continue
+		}
+		values[i] = fmt.Sprint(value)
+	}
+	return strings.Join(values, ", ")
+}
+
+func goComment(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// goValidatorRuntime is emitted verbatim into every generated Go file.
+const goValidatorRuntime = `
+type schemaNode struct {
+	Type       string
+	Closed     bool
+	Required   []string
+	Enum       []interface{}
+	Properties []schemaField
+	Items      *schemaNode
+}
+
+type schemaField struct {
+	Name   string
+	Schema *schemaNode
+}
+
+func validateDocument(data []byte, schema *schemaNode) error {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	return validateValue("$", schema, value)
+}
+
+func validateValue(path string, schema *schemaNode, value interface{}) error {
+	switch schema.Type {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: expected object", path)
+		}
+		for _, name := range schema.Required {
+			if _, ok := object[name]; !ok {
+				return fmt.Errorf("%s: missing required property %q", path, name)
+			}
+		}
+		for _, field := range schema.Properties {
+			child, ok := object[field.Name]
+			if !ok {
+				continue
+			}
+			if err := validateValue(path+"."+field.Name, field.Schema, child); err != nil {
+				return err
+			}
+		}
+		if schema.Closed {
+			if name, ok := undeclaredProperty(schema, object); ok {
+				return fmt.Errorf("%s: unexpected property %q", path, name)
+			}
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s: expected array", path)
+		}
+		if schema.Items != nil {
+			for i, item := range items {
+				if err := validateValue(fmt.Sprintf("%s[%d]", path, i), schema.Items, item); err != nil {
+					return err
+				}
+			}
+		}
+	case "string":
+		if _, ok := value.(string); !ok {
+			return fmt.Errorf("%s: expected string", path)
+		}
+	case "number":
+		if _, ok := value.(float64); !ok {
+			return fmt.Errorf("%s: expected number", path)
+		}
+	case "integer":
+		n, ok := value.(float64)
+		if !ok || math.Trunc(n) != n {
+			return fmt.Errorf("%s: expected integer", path)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("%s: expected boolean", path)
+		}
+	}
+
+	if len(schema.Enum) > 0 {
+		for _, allowed := range schema.Enum {
+			if allowed == value {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s: value %v is not one of the allowed values", path, value)
+	}
+
+	return nil
+}
+
+// undeclaredProperty returns the first property of object, in lexical order,
+// that schema does not declare.
+func undeclaredProperty(schema *schemaNode, object map[string]interface{}) (string, bool) {
+	first, found := "", false
+	for name := range object {
+		declared := false
+		for _, field := range schema.Properties {
+			if field.Name == name {
+				declared = true
+				break
+			}
+		}
+		if !declared && (!found || name < first) {
+			first, found = name, true
+		}
+	}
+	return first, found
+}
+`
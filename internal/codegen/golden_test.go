@@ -0,0 +1,190 @@
+package codegen
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/promptforge/promptforge/internal/ir"
+)
+
+func TestGenerate_Golden(t *testing.T) {
+	assertGolden(t, filepath.Join("testdata", "contract.ir.json"), filepath.Join("testdata", "golden"))
+}
+
+// TestGenerate_GoldenItemNameClash covers an array whose element type name,
+// CustomerItem, is also derived from the customer_item property.
+func TestGenerate_GoldenItemNameClash(t *testing.T) {
+	assertGolden(t, filepath.Join("testdata", "item_clash.ir.json"), filepath.Join("testdata", "golden", "item_clash"))
+}
+
+func assertGolden(t *testing.T, fixturePath, goldenDir string) {
+	t.Helper()
+	promptIR := loadFixtureIR(t, fixturePath)
+
+	artifacts, err := Generate(promptIR, Options{GoPackage: "contract"})
+	if err != nil {
+		t.Fatalf("Generate() failed: %v", err)
+	}
+
+	for _, artifact := range artifacts {
+		expectedPath := filepath.Join(goldenDir, artifact.Name)
+		expected, err := os.ReadFile(expectedPath)
+		if err != nil {
+			t.Fatalf("Failed to read golden file: %v", err)
+		}
+		if string(expected) != string(artifact.Content) {
+			t.Errorf("Golden mismatch for %s. Update %s if this change is expected.\n--- got ---\n%s", artifact.Name, expectedPath, artifact.Content)
+		}
+	}
+}
+
+func TestGenerate_Deterministic(t *testing.T) {
+	promptIR := loadFixtureIR(t, filepath.Join("testdata", "contract.ir.json"))
+
+	first, err := Generate(promptIR, Options{})
+	if err != nil {
+		t.Fatalf("Generate() failed: %v", err)
+	}
+	for i := 0; i < 10; i++ {
+		again, err := Generate(promptIR, Options{})
+		if err != nil {
+			t.Fatalf("Generate() failed: %v", err)
+		}
+		for j := range first {
+			if string(first[j].Content) != string(again[j].Content) {
+				t.Fatalf("Generate() output for %s is not deterministic", first[j].Name)
+			}
+		}
+	}
+}
+
+func TestGenerate_EmptyObjectSchemas(t *testing.T) {
+	promptIR := &ir.PromptIR{
+		InputSchema:  ir.Schema{Type: "object"},
+		OutputSchema: ir.Schema{Type: "object"},
+	}
+
+	artifacts, err := Generate(promptIR, Options{})
+	if err != nil {
+		t.Fatalf("Generate() failed: %v", err)
+	}
+	if len(artifacts) != 4 {
+		t.Fatalf("Expected 4 artifacts, got %d", len(artifacts))
+	}
+}
+
+func TestGo_InvalidPackageName(t *testing.T) {
+	promptIR := &ir.PromptIR{
+		InputSchema:  ir.Schema{Type: "object"},
+		OutputSchema: ir.Schema{Type: "object"},
+	}
+
+	if _, err := Go(promptIR, "not-a-package"); err == nil {
+		t.Fatal("Go() should reject an invalid package name")
+	}
+}
+
+// TestGo_GeneratedValidatorRuns builds the generated Go source into a program and
+// checks that ValidateInput and ValidateOutput enforce closed objects and nested
+// required properties like the exported JSON Schema does.
+func TestGo_GeneratedValidatorRuns(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds a Go program")
+	}
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go tool not found")
+	}
+
+	source, err := Go(loadFixtureIR(t, filepath.Join("testdata", "contract.ir.json")), "main")
+	if err != nil {
+		t.Fatalf("Go() failed: %v", err)
+	}
+	program := `package main
+
+import "fmt"
+
+func main() {
+	checks := []struct {
+		name     string
+		validate func([]byte) error
+		payload  string
+	}{
+		{"good input", ValidateInput, ` + "`" + `{"ticket_id": "T-1", "body": "hi", "customer": {"email": "a@example.com"}}` + "`" + `},
+		{"nested required", ValidateInput, ` + "`" + `{"ticket_id": "T-1", "body": "hi", "customer": {"tier": "pro"}}` + "`" + `},
+		{"nested closed", ValidateInput, ` + "`" + `{"ticket_id": "T-1", "body": "hi", "customer": {"email": "a@example.com", "name": "A"}}` + "`" + `},
+		{"good output", ValidateOutput, ` + "`" + `{"category": "bug", "confidence": 0.9, "metadata": {"any": 1}}` + "`" + `},
+		{"closed output", ValidateOutput, ` + "`" + `{"category": "bug", "confidence": 0.9, "reason": "x"}` + "`" + `},
+	}
+	for _, check := range checks {
+		if err := check.validate([]byte(check.payload)); err != nil {
+			fmt.Printf("%s: %v\n", check.name, err)
+			continue
+		}
+		fmt.Printf("%s: ok\n", check.name)
+	}
+}
+`
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":          "module validatorcheck\n\ngo 1.21\n",
+		"prompt_types.go": string(source),
+		"main.go":         program,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	cmd := exec.Command(goTool, "run", ".")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOWORK=off")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("go run failed: %v\n%s", err, output)
+	}
+
+	expected := `good input: ok
+nested required: $.customer: missing required property "email"
+nested closed: $.customer: unexpected property "name"
+good output: ok
+closed output: $: unexpected property "reason"
+`
+	if string(output) != expected {
+		t.Errorf("Unexpected validator results:\n--- got ---\n%s--- want ---\n%s", output, expected)
+	}
+}
+
+func TestExportedIdentifier(t *testing.T) {
+	cases := map[string]string{
+		"ticket_id":   "TicketID",
+		"size_bytes":  "SizeBytes",
+		"apiURL":      "APIURL",
+		"2fa":         "X2fa",
+		"needs-human": "NeedsHuman",
+	}
+	for input, expected := range cases {
+		if got := exportedIdentifier(input); got != expected {
+			t.Errorf("exportedIdentifier(%q) = %q, want %q", input, got, expected)
+		}
+	}
+}
+
+func loadFixtureIR(t *testing.T, path string) *ir.PromptIR {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read IR fixture: %v", err)
+	}
+
+	var promptIR ir.PromptIR
+	if err := json.Unmarshal(data, &promptIR); err != nil {
+		t.Fatalf("Failed to unmarshal IR fixture: %v", err)
+	}
+	return &promptIR
+}
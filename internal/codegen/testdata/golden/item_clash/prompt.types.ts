@@ -0,0 +1,18 @@
+// Code generated by promptforge export-schema. DO NOT EDIT.
+
+/** Input is the payload accepted by the prompt (input_schema). */
+export type Input = Record<string, unknown>;
+
+/** Output is the payload the model must return (output_schema). */
+export interface Output {
+  customer: OutputCustomerItem[];
+  customer_item?: OutputCustomerItem2;
+}
+
+export interface OutputCustomerItem {
+  email?: string;
+}
+
+export interface OutputCustomerItem2 {
+  sku?: string;
+}
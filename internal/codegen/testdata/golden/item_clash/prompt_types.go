@@ -0,0 +1,178 @@
+// Code generated by promptforge export-schema. DO NOT EDIT.
+
+package contract
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// Input is the payload accepted by the prompt (input_schema).
+type Input map[string]interface{}
+
+// Output is the payload the model must return (output_schema).
+type Output struct {
+	Customer     []OutputCustomerItem `json:"customer"`
+	CustomerItem *OutputCustomerItem2 `json:"customer_item,omitempty"`
+}
+
+// OutputCustomerItem is an element of OutputCustomer.
+type OutputCustomerItem struct {
+	Email string `json:"email,omitempty"`
+}
+
+// OutputCustomerItem2 is the customer_item property of Output.
+type OutputCustomerItem2 struct {
+	Sku string `json:"sku,omitempty"`
+}
+
+// ValidateInput checks that data is a JSON document satisfying the input contract.
+func ValidateInput(data []byte) error {
+	return validateDocument(data, inputSchema)
+}
+
+// ValidateOutput checks that data is a JSON document satisfying the output contract.
+func ValidateOutput(data []byte) error {
+	return validateDocument(data, outputSchema)
+}
+
+var inputSchema = &schemaNode{
+	Type: "object",
+}
+
+var outputSchema = &schemaNode{
+	Type:     "object",
+	Required: []string{"customer"},
+	Properties: []schemaField{
+		{Name: "customer", Schema: &schemaNode{
+			Type: "array",
+			Items: &schemaNode{
+				Type: "object",
+				Properties: []schemaField{
+					{Name: "email", Schema: &schemaNode{
+						Type: "string",
+					}},
+				},
+			},
+		}},
+		{Name: "customer_item", Schema: &schemaNode{
+			Type: "object",
+			Properties: []schemaField{
+				{Name: "sku", Schema: &schemaNode{
+					Type: "string",
+				}},
+			},
+		}},
+	},
+}
+
+type schemaNode struct {
+	Type       string
+	Closed     bool
+	Required   []string
+	Enum       []interface{}
+	Properties []schemaField
+	Items      *schemaNode
+}
+
+type schemaField struct {
+	Name   string
+	Schema *schemaNode
+}
+
+func validateDocument(data []byte, schema *schemaNode) error {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	return validateValue("$", schema, value)
+}
+
+func validateValue(path string, schema *schemaNode, value interface{}) error {
+	switch schema.Type {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: expected object", path)
+		}
+		for _, name := range schema.Required {
+			if _, ok := object[name]; !ok {
+				return fmt.Errorf("%s: missing required property %q", path, name)
+			}
+		}
+		for _, field := range schema.Properties {
+			child, ok := object[field.Name]
+			if !ok {
+				continue
+			}
+			if err := validateValue(path+"."+field.Name, field.Schema, child); err != nil {
+				return err
+			}
+		}
+		if schema.Closed {
+			if name, ok := undeclaredProperty(schema, object); ok {
+				return fmt.Errorf("%s: unexpected property %q", path, name)
+			}
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s: expected array", path)
+		}
+		if schema.Items != nil {
+			for i, item := range items {
+				if err := validateValue(fmt.Sprintf("%s[%d]", path, i), schema.Items, item); err != nil {
+					return err
+				}
+			}
+		}
+	case "string":
+		if _, ok := value.(string); !ok {
+			return fmt.Errorf("%s: expected string", path)
+		}
+	case "number":
+		if _, ok := value.(float64); !ok {
+			return fmt.Errorf("%s: expected number", path)
+		}
+	case "integer":
+		n, ok := value.(float64)
+		if !ok || math.Trunc(n) != n {
+			return fmt.Errorf("%s: expected integer", path)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("%s: expected boolean", path)
+		}
+	}
+
+	if len(schema.Enum) > 0 {
+		for _, allowed := range schema.Enum {
+			if allowed == value {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s: value %v is not one of the allowed values", path, value)
+	}
+
+	return nil
+}
+
+// undeclaredProperty returns the first property of object, in lexical order,
+// that schema does not declare.
+func undeclaredProperty(schema *schemaNode, object map[string]interface{}) (string, bool) {
+	first, found := "", false
+	for name := range object {
+		declared := false
+		for _, field := range schema.Properties {
+			if field.Name == name {
+				declared = true
+				break
+			}
+		}
+		if !declared && (!found || name < first) {
+			first, found = name, true
+		}
+	}
+	return first, found
+}
@@ -0,0 +1,32 @@
+// Code generated by promptforge export-schema. DO NOT EDIT.
+
+/** Input is the payload accepted by the prompt (input_schema). */
+export interface Input {
+  attachments?: InputAttachmentsItem[];
+  body: string;
+  /** Customer who opened the ticket */
+  customer?: InputCustomer;
+  /** Identifier of the support ticket */
+  ticket_id: string;
+}
+
+export interface InputAttachmentsItem {
+  size_bytes?: number;
+  url: string;
+}
+
+/** Customer who opened the ticket */
+export interface InputCustomer {
+  email: string;
+  tier?: "free" | "pro" | "enterprise";
+}
+
+/** Output is the payload the model must return (output_schema). */
+export interface Output {
+  /** Ticket category */
+  category: "bug" | "billing" | "account" | "feature";
+  confidence: number;
+  metadata?: Record<string, unknown>;
+  needs_human?: boolean;
+  tags?: string[];
+}
@@ -0,0 +1,233 @@
+// Code generated by promptforge export-schema. DO NOT EDIT.
+
+package contract
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// Input is the payload accepted by the prompt (input_schema).
+type Input struct {
+	Attachments []InputAttachmentsItem `json:"attachments,omitempty"`
+	Body        string                 `json:"body"`
+	// Customer who opened the ticket
+	Customer *InputCustomer `json:"customer,omitempty"`
+	// Identifier of the support ticket
+	TicketID string `json:"ticket_id"`
+}
+
+// InputAttachmentsItem is an element of InputAttachments.
+type InputAttachmentsItem struct {
+	SizeBytes int64  `json:"size_bytes,omitempty"`
+	URL       string `json:"url"`
+}
+
+// InputCustomer is the customer property of Input.
+// Customer who opened the ticket
+type InputCustomer struct {
+	Email string `json:"email"`
+	// Allowed values: "free", "pro", "enterprise".
+	Tier string `json:"tier,omitempty"`
+}
+
+// Output is the payload the model must return (output_schema).
+type Output struct {
+	// Ticket category
+	// Allowed values: "bug", "billing", "account", "feature".
+	Category   string                 `json:"category"`
+	Confidence float64                `json:"confidence"`
+	Metadata   map[string]interface{} `json:"metadata,omitempty"`
+	NeedsHuman bool                   `json:"needs_human,omitempty"`
+	Tags       []string               `json:"tags,omitempty"`
+}
+
+// ValidateInput checks that data is a JSON document satisfying the input contract.
+func ValidateInput(data []byte) error {
+	return validateDocument(data, inputSchema)
+}
+
+// ValidateOutput checks that data is a JSON document satisfying the output contract.
+func ValidateOutput(data []byte) error {
+	return validateDocument(data, outputSchema)
+}
+
+var inputSchema = &schemaNode{
+	Type:     "object",
+	Required: []string{"ticket_id", "body"},
+	Properties: []schemaField{
+		{Name: "attachments", Schema: &schemaNode{
+			Type: "array",
+			Items: &schemaNode{
+				Type:     "object",
+				Required: []string{"url"},
+				Properties: []schemaField{
+					{Name: "size_bytes", Schema: &schemaNode{
+						Type: "integer",
+					}},
+					{Name: "url", Schema: &schemaNode{
+						Type: "string",
+					}},
+				},
+			},
+		}},
+		{Name: "body", Schema: &schemaNode{
+			Type: "string",
+		}},
+		{Name: "customer", Schema: &schemaNode{
+			Type:     "object",
+			Closed:   true,
+			Required: []string{"email"},
+			Properties: []schemaField{
+				{Name: "email", Schema: &schemaNode{
+					Type: "string",
+				}},
+				{Name: "tier", Schema: &schemaNode{
+					Type: "string",
+					Enum: []interface{}{"free", "pro", "enterprise"},
+				}},
+			},
+		}},
+		{Name: "ticket_id", Schema: &schemaNode{
+			Type: "string",
+		}},
+	},
+}
+
+var outputSchema = &schemaNode{
+	Type:     "object",
+	Closed:   true,
+	Required: []string{"category", "confidence"},
+	Properties: []schemaField{
+		{Name: "category", Schema: &schemaNode{
+			Type: "string",
+			Enum: []interface{}{"bug", "billing", "account", "feature"},
+		}},
+		{Name: "confidence", Schema: &schemaNode{
+			Type: "number",
+		}},
+		{Name: "metadata", Schema: &schemaNode{
+			Type: "object",
+		}},
+		{Name: "needs_human", Schema: &schemaNode{
+			Type: "boolean",
+		}},
+		{Name: "tags", Schema: &schemaNode{
+			Type: "array",
+			Items: &schemaNode{
+				Type: "string",
+			},
+		}},
+	},
+}
+
+type schemaNode struct {
+	Type       string
+	Closed     bool
+	Required   []string
+	Enum       []interface{}
+	Properties []schemaField
+	Items      *schemaNode
+}
+
+type schemaField struct {
+	Name   string
+	Schema *schemaNode
+}
+
+func validateDocument(data []byte, schema *schemaNode) error {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	return validateValue("$", schema, value)
+}
+
+func validateValue(path string, schema *schemaNode, value interface{}) error {
+	switch schema.Type {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: expected object", path)
+		}
+		for _, name := range schema.Required {
+			if _, ok := object[name]; !ok {
+				return fmt.Errorf("%s: missing required property %q", path, name)
+			}
+		}
+		for _, field := range schema.Properties {
+			child, ok := object[field.Name]
+			if !ok {
+				continue
+			}
+			if err := validateValue(path+"."+field.Name, field.Schema, child); err != nil {
+				return err
+			}
+		}
+		if schema.Closed {
+			if name, ok := undeclaredProperty(schema, object); ok {
+				return fmt.Errorf("%s: unexpected property %q", path, name)
+			}
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s: expected array", path)
+		}
+		if schema.Items != nil {
+			for i, item := range items {
+				if err := validateValue(fmt.Sprintf("%s[%d]", path, i), schema.Items, item); err != nil {
+					return err
+				}
+			}
+		}
+	case "string":
+		if _, ok := value.(string); !ok {
+			return fmt.Errorf("%s: expected string", path)
+		}
+	case "number":
+		if _, ok := value.(float64); !ok {
+			return fmt.Errorf("%s: expected number", path)
+		}
+	case "integer":
+		n, ok := value.(float64)
+		if !ok || math.Trunc(n) != n {
+			return fmt.Errorf("%s: expected integer", path)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("%s: expected boolean", path)
+		}
+	}
+
+	if len(schema.Enum) > 0 {
+		for _, allowed := range schema.Enum {
+			if allowed == value {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s: value %v is not one of the allowed values", path, value)
+	}
+
+	return nil
+}
+
+// undeclaredProperty returns the first property of object, in lexical order,
+// that schema does not declare.
+func undeclaredProperty(schema *schemaNode, object map[string]interface{}) (string, bool) {
+	first, found := "", false
+	for name := range object {
+		declared := false
+		for _, field := range schema.Properties {
+			if field.Name == name {
+				declared = true
+				break
+			}
+		}
+		if !declared && (!found || name < first) {
+			first, found = name, true
+		}
+	}
+	return first, found
+}
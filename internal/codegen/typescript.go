@@ -0,0 +1,126 @@
+package codegen
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/promptforge/promptforge/internal/ir"
+)
+
+// TypeScript returns TypeScript declarations for the input and output contracts.
+func TypeScript(promptIR *ir.PromptIR) ([]byte, error) {
+	var b strings.Builder
+	b.WriteString("// Code generated by promptforge export-schema. DO NOT EDIT.\n")
+
+	for _, root := range []struct {
+		name   string
+		doc    string
+		schema ir.Schema
+	}{
+		{name: "Input", doc: "Input is the payload accepted by the prompt (input_schema).", schema: promptIR.InputSchema},
+		{name: "Output", doc: "Output is the payload the model must return (output_schema).", schema: promptIR.OutputSchema},
+	} {
+		model := buildModel(root.name, root.schema)
+		if model.root.kind != kindNamed {
+			expr, err := tsType(model.root, nil)
+			if err != nil {
+				return nil, err
+			}
+			fmt.Fprintf(&b, "\n/** %s */\nexport type %s = %s;\n", root.doc, root.name, expr)
+		}
+		for _, def := range model.defs {
+			doc := def.description
+			if def.name == root.name {
+				doc = root.doc
+			}
+			if err := writeTSInterface(&b, def, doc); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return []byte(b.String()), nil
+}
+
+func writeTSInterface(b *strings.Builder, def typeDef, doc string) error {
+	b.WriteString("\n")
+	if doc != "" {
+		fmt.Fprintf(b, "/** %s */\n", tsComment(doc))
+	}
+	fmt.Fprintf(b, "export interface %s {\n", def.name)
+	for _, field := range def.fields {
+		expr, err := tsType(field.ref, field.enum)
+		if err != nil {
+			return err
+		}
+		if field.description != "" {
+			fmt.Fprintf(b, "  /** %s */\n", tsComment(field.description))
+		}
+		optional := "?"
+		if field.required {
+			optional = ""
+		}
+		fmt.Fprintf(b, "  %s%s: %s;\n", tsPropertyName(field.jsonName), optional, expr)
+	}
+	b.WriteString("}\n")
+	return nil
+}
+
+func tsType(ref typeRef, enum []interface{}) (string, error) {
+	if len(enum) > 0 {
+		literals := make([]string, 0, len(enum))
+		for _, value := range enum {
+			literal, err := json.Marshal(value)
+			if err != nil {
+				return "", fmt.Errorf("failed to encode enum value %v: %w", value, err)
+			}
+			literals = append(literals, string(literal))
+		}
+		return strings.Join(literals, " | "), nil
+	}
+
+	switch ref.kind {
+	case kindString:
+		return "string", nil
+	case kindNumber, kindInteger:
+		return "number", nil
+	case kindBoolean:
+		return "boolean", nil
+	case kindMap:
+		return "Record<string, unknown>", nil
+	case kindArray:
+		elem, err := tsType(*ref.elem, nil)
+		if err != nil {
+			return "", err
+		}
+		if strings.Contains(elem, " ") {
+			elem = "(" + elem + ")"
+		}
+		return elem + "[]", nil
+	case kindNamed:
+		return ref.named, nil
+	default:
+		return "unknown", nil
+	}
+}
+
+func tsPropertyName(name string) string {
+	for i, r := range name {
+		isLetter := r == '_' || r == '$' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+		isDigit := r >= '0' && r <= '9'
+		if !isLetter && !(isDigit && i > 0) {
+			quoted, _ := json.Marshal(name)
+			return string(quoted)
+		}
+	}
+	if name == "" {
+		return `""`
+	}
+	return name
+}
+
+func tsComment(text string) string {
+	text = strings.ReplaceAll(text, "*/", "* /")
+	return strings.Join(strings.Fields(text), " ")
+}
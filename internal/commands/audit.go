@@ -2,38 +2,90 @@ package commands
 
 import (
 	"fmt"
-	"os"
 
+	"github.com/promptforge/promptforge"
 	"github.com/promptforge/promptforge/internal/core"
+	"github.com/promptforge/promptforge/internal/policy"
+	"github.com/promptforge/promptforge/internal/server"
 )
 
-// Audit validates prompt.ir.json and schema integrity.
-func Audit() error {
-	projectDir, err := os.Getwd()
-	if err != nil {
-		return fmt.Errorf("failed to get current directory: %w", err)
+// Severities audit can be told to fail on.
+const (
+	FailOnError = "error"
+	FailOnWarn  = "warn"
+)
+
+// Audit validates prompt.ir.json and schema integrity and checks it against the
+// policy packs at policyPaths. It fails when an issue is at least as severe as
+// failOn (empty means FailOnError). With sarif set, the issues are printed as a
+// SARIF log instead of text.
+func Audit(env *Env, policyPaths []string, sarif bool, failOn string) error {
+	switch failOn {
+	case "":
+		failOn = FailOnError
+	case FailOnError, FailOnWarn:
+	default:
+		return promptforge.Errorf(promptforge.CodeInvalidArgument, "invalid --fail-on %q (expected warn or error)", failOn)
+	}
+
+	packs := make([]*policy.Pack, 0, len(policyPaths))
+	for _, path := range policyPaths {
+		pack, err := core.LoadPolicy(env.Path(path))
+		if err != nil {
+			return err
+		}
+		packs = append(packs, pack)
 	}
 
-	issues, err := core.AuditProject(projectDir)
+	issues, err := core.AuditProject(env.ProjectDir, packs...)
 	if err != nil {
 		return err
 	}
+	if issues == nil {
+		issues = []core.AuditIssue{}
+	}
+
+	env.setData(server.AuditResponse{Issues: issues})
+
+	if sarif {
+		data, err := core.AuditSARIF(issues)
+		if err != nil {
+			return err
+		}
+		if _, err := env.Stdout.Write(data); err != nil {
+			return err
+		}
+	}
 
-	var errorCount int
+	var failing int
 	for _, issue := range issues {
-		fmt.Printf("%s: %s\n", issue.Severity, issue.Message)
-		if issue.Severity == "error" {
-			errorCount++
+		if !sarif {
+			location := issue.Artifact
+			if issue.Pointer != "" {
+				location += "#" + issue.Pointer
+			}
+			fmt.Fprintf(env.Stdout, "%s: %s %s %s\n", location, issue.Severity, issue.Code, issue.Message)
+		}
+		if issue.Severity == "error" || failOn == FailOnWarn {
+			failing++
 		}
 	}
 
-	if errorCount > 0 {
-		return fmt.Errorf("audit failed with %d error(s)", errorCount)
+	if failing > 0 {
+		return promptforge.Errorf(promptforge.CodeFindings, "audit failed with %d %s(s)", failing, failOnLabel(failOn))
 	}
 
-	if len(issues) == 0 {
-		fmt.Println("Audit passed with no issues")
+	if len(issues) == 0 && !sarif {
+		env.Printf("Audit passed with no issues\n")
 	}
 
 	return nil
 }
+
+// failOnLabel names the issues that fail an audit in its error message.
+func failOnLabel(failOn string) string {
+	if failOn == FailOnWarn {
+		return "issue"
+	}
+	return "error"
+}
@@ -1,45 +1,98 @@
 package commands
 
 import (
-	"fmt"
 	"os"
 	"path/filepath"
 
+	"github.com/promptforge/promptforge"
+	"github.com/promptforge/promptforge/internal/compiler"
 	"github.com/promptforge/promptforge/internal/core"
-	"github.com/promptforge/promptforge/internal/ir"
 )
 
-// Compile reads promptforge/plan.md and produces prompt.ir.json in the repository root.
-// This is a thin CLI wrapper around core.CompileProject.
-func Compile(explain bool) error {
-	// Get current working directory for project root
-	projectDir, err := os.Getwd()
-	if err != nil {
-		return fmt.Errorf("failed to get current directory: %w", err)
+// Compile reads the plan and produces prompt.ir.json and prompt.ir.schema.json in
+// the output directory, by default the repository root; a directory given with
+// --out is created if needed. With explain set it also writes
+// prompt.ir.explain.json there.
+// When the plan is read from standard input or --out is "-", the IR is printed
+// to standard output instead and no files are written.
+// This is a thin CLI wrapper around core.CompilePlan.
+func Compile(env *Env, explain bool) error {
+	if env.PlanPath == Stdio || env.OutDir == Stdio {
+		return compileToStdout(env, explain)
 	}
 
-	// CLI-specific: output to repository root
-	outputPath := filepath.Join(projectDir, "prompt.ir.json")
+	// CLI-specific: output to repository root unless --out is given
+	outputDir := env.Out(".")
+	if env.OutDir != "" {
+		if err := os.MkdirAll(outputDir, 0755); err != nil {
+			return promptforge.Wrap(err, promptforge.IOCode(err), "cannot create output directory %s", outputDir)
+		}
+	}
+	outputPath := filepath.Join(outputDir, "prompt.ir.json")
 
 	// Call core compilation logic
-	var compiledIR *ir.PromptIR
+	var explainPath string
 	if explain {
-		explainPath := filepath.Join(projectDir, "prompt.ir.explain.json")
-		compiledIR, err = core.CompileProjectWithExplain(projectDir, outputPath, explainPath)
-		if err != nil {
-			return err
-		}
-		fmt.Printf("Wrote explain report to %s\n", explainPath)
-	} else {
-		compiledIR, err = core.CompileProject(projectDir, outputPath)
-		if err != nil {
-			return err
-		}
+		explainPath = filepath.Join(outputDir, "prompt.ir.explain.json")
+	}
+	planPath := env.Plan()
+	if _, err := core.CompilePlan(env.ProjectDir, planPath, outputPath, explainPath); err != nil {
+		return err
 	}
 
 	// CLI-specific: print success message
-	planPath := filepath.Join(projectDir, "promptforge", "plan.md")
-	fmt.Printf("Compiled %s to %s\n", planPath, outputPath)
-	_ = compiledIR // IR is returned but not used in CLI
+	env.wrote(outputPath, filepath.Join(outputDir, "prompt.ir.schema.json"))
+	if explain {
+		env.wrote(explainPath)
+		env.Printf("Wrote explain report to %s\n", explainPath)
+	}
+	env.Printf("Compiled %s to %s\n", planPath, outputPath)
 	return nil
 }
+
+// compileToStdout prints the IR compiled from the plan without writing files.
+func compileToStdout(env *Env, explain bool) error {
+	if explain {
+		return promptforge.Errorf(promptforge.CodeInvalidArgument, "--explain cannot be used when the IR is printed to standard output").
+			WithHint("Pass --out <dir> to write the IR and explain report to files")
+	}
+	if env.PlanPath == Stdio && env.OutDir != "" && env.OutDir != Stdio {
+		return promptforge.Errorf(promptforge.CodeInvalidArgument, "--out cannot be used with a plan read from standard input").
+			WithHint("Redirect standard output to a file instead")
+	}
+
+	planPath, content, err := readPlan(env)
+	if err != nil {
+		return err
+	}
+	promptIR, _, err := core.CompilePlanContent(env.ProjectDir, planPath, content, false)
+	if err != nil {
+		return err
+	}
+	data, err := compiler.MarshalIR(promptIR)
+	if err != nil {
+		return err
+	}
+	_, err = env.Stdout.Write(data)
+	return err
+}
+
+// readPlan reads the plan, from standard input when it is "-", and returns the
+// path it stands for.
+func readPlan(env *Env) (string, []byte, error) {
+	if env.PlanPath == Stdio {
+		content, err := env.readStdin("plan")
+		return stdinName, content, err
+	}
+
+	planPath := env.Plan()
+	content, err := os.ReadFile(planPath)
+	if err != nil {
+		e := promptforge.ReadError(err, "plan.md", planPath)
+		if e.Code == promptforge.CodeNotFound {
+			e.Hint = "Run 'promptforge init' first"
+		}
+		return "", nil, e
+	}
+	return planPath, content, nil
+}
@@ -1,25 +1,22 @@
 package commands
 
 import (
+	"bytes"
 	"os"
 	"path/filepath"
 	"testing"
 )
 
+// testEnv returns an Env for the project in dir that captures output.
+func testEnv(dir string) *Env {
+	return &Env{ProjectDir: dir, Stdin: &bytes.Buffer{}, Stdout: &bytes.Buffer{}, Stderr: &bytes.Buffer{}}
+}
+
 // TestCompile_SuccessfulCompilation tests successful compilation with existing plan.md.
 func TestCompile_SuccessfulCompilation(t *testing.T) {
 	// Create temporary directory structure
 	tmpDir := t.TempDir()
-	originalDir, err := os.Getwd()
-	if err != nil {
-		t.Fatalf("Failed to get current directory: %v", err)
-	}
-
-	// Change to temp directory
-	if err := os.Chdir(tmpDir); err != nil {
-		t.Fatalf("Failed to change to temp directory: %v", err)
-	}
-	defer os.Chdir(originalDir)
+	env := testEnv(tmpDir)
 
 	// Create promptforge directory
 	promptforgeDir := filepath.Join(tmpDir, "promptforge")
@@ -35,7 +32,7 @@ func TestCompile_SuccessfulCompilation(t *testing.T) {
 	}
 
 	// Run compile
-	err = Compile(false)
+	err := Compile(env, false)
 	if err != nil {
 		t.Fatalf("Compile() failed: %v", err)
 	}
@@ -51,19 +48,10 @@ func TestCompile_SuccessfulCompilation(t *testing.T) {
 func TestCompile_MissingPlanMD(t *testing.T) {
 	// Create temporary directory
 	tmpDir := t.TempDir()
-	originalDir, err := os.Getwd()
-	if err != nil {
-		t.Fatalf("Failed to get current directory: %v", err)
-	}
-
-	// Change to temp directory
-	if err := os.Chdir(tmpDir); err != nil {
-		t.Fatalf("Failed to change to temp directory: %v", err)
-	}
-	defer os.Chdir(originalDir)
+	env := testEnv(tmpDir)
 
 	// Run compile without creating plan.md
-	err = Compile(false)
+	err := Compile(env, false)
 	if err == nil {
 		t.Error("Compile() should fail when plan.md does not exist")
 	}
@@ -0,0 +1,35 @@
+package commands
+
+import (
+	"github.com/promptforge/promptforge/internal/codegen"
+	"github.com/promptforge/promptforge/internal/core"
+)
+
+// Emit prints a single artifact for target, such as an OpenAI request body or
+// the output JSON Schema, to standard output. The IR is read from irPath, from
+// standard input when irPath is "-", and otherwise from prompt.ir.json in the
+// project.
+func Emit(env *Env, irPath, target string, opts codegen.Options, values map[string]string) error {
+	var (
+		data []byte
+		err  error
+	)
+	switch {
+	case irPath == Stdio:
+		var input []byte
+		if input, err = env.readStdin("prompt IR"); err != nil {
+			return err
+		}
+		data, err = core.Emit(stdinName, input, target, opts, values)
+	case irPath != "":
+		data, err = core.EmitFile(env.Path(irPath), target, opts, values)
+	default:
+		data, err = core.EmitProject(env.ProjectDir, target, opts, values)
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = env.Stdout.Write(data)
+	return err
+}
@@ -0,0 +1,125 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/promptforge/promptforge"
+	"github.com/promptforge/promptforge/internal/core"
+	"github.com/promptforge/promptforge/internal/linter"
+)
+
+// Stdio is the file name that stands for standard input, or standard output
+// where a command writes.
+const Stdio = "-"
+
+// stdinName names standard input in errors and diagnostics.
+const stdinName = "<stdin>"
+
+// Env is what a command runs against: the project it works on, where it reads
+// and writes, and the streams it prints to. The CLI builds it from the global
+// flags; tests build it directly.
+type Env struct {
+	// ProjectDir is the project root. Relative paths given to commands are
+	// resolved against it.
+	ProjectDir string
+
+	// PlanPath is the plan to read; empty means promptforge/plan.md and Stdio
+	// means standard input.
+	PlanPath string
+
+	// OutDir is where compile writes its artifacts and export-schema its types;
+	// empty means the project root and promptforge/generated respectively.
+	// Stdio makes compile print the IR instead.
+	OutDir string
+
+	// JSON selects machine-readable output: the CLI prints a result envelope
+	// instead of the command's text output.
+	JSON bool
+
+	// Quiet suppresses progress and success messages. Command results,
+	// diagnostics and errors are still printed.
+	Quiet bool
+
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+
+	// Result, when set, collects artifacts, diagnostics and data for the --json
+	// result envelope.
+	Result *Result
+}
+
+// Path resolves path against the project directory unless it is absolute.
+func (e *Env) Path(path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(e.ProjectDir, path)
+}
+
+// Plan returns the path of the plan to read.
+func (e *Env) Plan() string {
+	if e.PlanPath == "" {
+		return core.PlanPath(e.ProjectDir)
+	}
+	return e.Path(e.PlanPath)
+}
+
+// Out returns the output directory, or def when none was given. Both are
+// resolved against the project directory.
+func (e *Env) Out(def string) string {
+	if e.OutDir == "" {
+		return e.Path(def)
+	}
+	return e.Path(e.OutDir)
+}
+
+// Printf prints a progress or success message unless Quiet is set.
+func (e *Env) Printf(format string, args ...interface{}) {
+	if !e.Quiet {
+		fmt.Fprintf(e.Stdout, format, args...)
+	}
+}
+
+// wrote records files the command wrote in the result envelope.
+func (e *Env) wrote(paths ...string) {
+	if e.Result != nil {
+		e.Result.Artifacts = append(e.Result.Artifacts, paths...)
+	}
+}
+
+// report records diagnostics in the result envelope.
+func (e *Env) report(diagnostics []linter.Diagnostic) {
+	if e.Result != nil {
+		e.Result.Diagnostics = append(e.Result.Diagnostics, diagnostics...)
+	}
+}
+
+// setData records command-specific data in the result envelope.
+func (e *Env) setData(data interface{}) {
+	if e.Result != nil {
+		e.Result.Data = data
+	}
+}
+
+// piped returns a copy of the environment that prints messages to Stderr, and
+// the original Stdout, for commands that write an artifact to standard output.
+func (e *Env) piped() (*Env, io.Writer) {
+	piped := *e
+	piped.Stdout = e.Stderr
+	return &piped, e.Stdout
+}
+
+// readStdin reads all of standard input; what names the content in errors.
+func (e *Env) readStdin(what string) ([]byte, error) {
+	if e.Stdin == nil {
+		return nil, nil
+	}
+	data, err := io.ReadAll(e.Stdin)
+	if err != nil {
+		return nil, promptforge.Wrap(err, promptforge.IOCode(err), "failed to read %s from standard input", what)
+	}
+	return data, nil
+}
@@ -0,0 +1,24 @@
+package commands
+
+import (
+	"path/filepath"
+
+	"github.com/promptforge/promptforge/internal/core"
+)
+
+// ExportSchema writes JSON Schema, TypeScript and Go types for the compiled
+// contract to the output directory, by default promptforge/generated.
+func ExportSchema(env *Env, goPackage string) error {
+	outputDir := env.Out(filepath.Join("promptforge", "generated"))
+
+	written, err := core.ExportSchema(env.ProjectDir, outputDir, goPackage)
+	if err != nil {
+		return err
+	}
+
+	env.wrote(written...)
+	for _, path := range written {
+		env.Printf("Wrote %s\n", path)
+	}
+	return nil
+}
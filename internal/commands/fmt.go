@@ -0,0 +1,45 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/promptforge/promptforge"
+	"github.com/promptforge/promptforge/internal/core"
+)
+
+// Fmt rewrites the plan in canonical form. With check set, it prints a diff
+// instead and fails if the plan is not formatted. A plan read from standard
+// input is printed in canonical form to standard output.
+func Fmt(env *Env, check bool) error {
+	var (
+		result *core.FormatResult
+		err    error
+	)
+	if env.PlanPath == Stdio {
+		content, err := env.readStdin("plan")
+		if err != nil {
+			return err
+		}
+		result = core.FormatContent(stdinName, content)
+		if !check {
+			_, err = env.Stdout.Write(result.Formatted)
+			return err
+		}
+	} else if result, err = core.FormatPlan(env.ProjectDir, env.Plan(), check); err != nil {
+		return err
+	}
+
+	if check {
+		if result.Changed {
+			fmt.Fprint(env.Stdout, result.Diff)
+			return promptforge.Errorf(promptforge.CodeFindings, "plan.md is not formatted (run 'promptforge fmt')")
+		}
+		return nil
+	}
+
+	if result.Changed {
+		env.wrote(result.Path)
+		env.Printf("Formatted %s\n", result.Path)
+	}
+	return nil
+}
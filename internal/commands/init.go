@@ -1,30 +1,88 @@
 package commands
 
 import (
+	"bufio"
 	"fmt"
+	"io"
 	"os"
-	"path/filepath"
+	"strings"
 
+	"github.com/promptforge/promptforge"
 	"github.com/promptforge/promptforge/internal/core"
+	"github.com/promptforge/promptforge/internal/parser"
+	"github.com/promptforge/promptforge/internal/templates"
 )
 
-// Init initializes a new PromptForge project by creating a promptforge/plan.md file.
-// This is a thin CLI wrapper around core.InitializeProjectWithTemplate.
+// Init initializes a new PromptForge project by creating the plan, by default
+// promptforge/plan.md. This is a thin CLI wrapper around core.InitializePlan.
 // If description is provided, it will be used to populate the Goal section.
-func Init(description string, templateName string) error {
-	// Get current working directory for project root
-	projectDir, err := os.Getwd()
-	if err != nil {
-		return fmt.Errorf("failed to get current directory: %w", err)
+// Required template parameters missing from params are prompted for when env.Stdin
+// is a terminal and env.JSON is not set; otherwise initialization fails listing them.
+func Init(env *Env, description string, templateName string, params map[string]string) error {
+	if templateName != "" && !env.JSON && isTerminal(env.Stdin) {
+		tmpl, err := templates.Find(env.ProjectDir, templateName)
+		if err != nil {
+			return err
+		}
+		if missing := tmpl.MissingParams(params); len(missing) > 0 {
+			if params == nil {
+				params = make(map[string]string)
+			}
+			if err := promptParams(env.Stdin, env.Stdout, missing, params); err != nil {
+				return err
+			}
+		}
 	}
 
 	// Call core initialization logic
-	if err := core.InitializeProjectWithTemplate(projectDir, description, templateName); err != nil {
+	planPath := env.Plan()
+	if err := core.InitializePlan(env.ProjectDir, planPath, description, templateName, params); err != nil {
 		return err
 	}
 
 	// CLI-specific: print success message
-	planPath := filepath.Join(projectDir, "promptforge", "plan.md")
-	fmt.Printf("Initialized PromptForge project: created %s\n", planPath)
+	env.wrote(planPath)
+	env.Printf("Initialized PromptForge project: created %s\n", planPath)
+	return nil
+}
+
+// promptParams asks for each missing parameter on out and reads answers from in.
+// Blank answers are skipped so that core reports them as missing.
+func promptParams(in io.Reader, out io.Writer, missing []parser.Parameter, params map[string]string) error {
+	reader := bufio.NewReader(in)
+	for _, param := range missing {
+		label := fmt.Sprintf("%s (%s)", param.Name, param.Type)
+		if _, isList := parser.IsArrayType(param.Type); isList {
+			label += ", comma-separated"
+		}
+		if param.Description != "" {
+			label += " - " + param.Description
+		}
+		fmt.Fprintf(out, "%s: ", label)
+
+		answer, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return promptforge.Wrap(err, promptforge.IOCode(err), "failed to read value for %s", param.Name)
+		}
+		if answer = strings.TrimSpace(answer); answer != "" {
+			params[param.Name] = answer
+		}
+		if err == io.EOF {
+			break
+		}
+	}
 	return nil
 }
+
+// isTerminal reports whether r is an interactive terminal.
+func isTerminal(r io.Reader) bool {
+	f, ok := r.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -2,36 +2,70 @@ package commands
 
 import (
 	"fmt"
-	"os"
-	"path/filepath"
 
+	"github.com/promptforge/promptforge"
 	"github.com/promptforge/promptforge/internal/core"
 	"github.com/promptforge/promptforge/internal/linter"
 )
 
-// Lint reads promptforge/plan.md and prints diagnostics.
-func Lint() error {
-	projectDir, err := os.Getwd()
-	if err != nil {
-		return fmt.Errorf("failed to get current directory: %w", err)
-	}
+// Lint reads the plan and prints diagnostics. With fix set, it first applies the
+// available fixes to the plan. With env.JSON set, diagnostics (including their
+// fixes) are recorded in the result envelope instead of printed. A plan read from standard input is named
+// <stdin> in diagnostics and cannot be fixed.
+func Lint(env *Env, fix bool) error {
+	planPath := env.Plan()
 
-	diagnostics, err := core.LintProject(projectDir)
-	if err != nil {
-		return err
+	var diagnostics []linter.Diagnostic
+	var err error
+	if env.PlanPath == Stdio {
+		if fix {
+			return promptforge.Errorf(promptforge.CodeInvalidArgument, "--fix cannot be used with a plan read from standard input")
+		}
+		var content []byte
+		if planPath, content, err = readPlan(env); err != nil {
+			return err
+		}
+		if diagnostics, err = core.LintPlanContent(env.ProjectDir, planPath, content); err != nil {
+			return err
+		}
+	} else if fix {
+		var applied int
+		applied, diagnostics, err = core.FixPlan(env.ProjectDir, planPath)
+		if err != nil {
+			return err
+		}
+		if applied > 0 {
+			env.wrote(planPath)
+		}
+		if !env.JSON {
+			env.Printf("Applied %d fix(es)\n", applied)
+		}
+	} else {
+		diagnostics, err = core.LintPlan(env.ProjectDir, planPath)
+		if err != nil {
+			return err
+		}
 	}
 
-	planPath := filepath.Join(projectDir, "promptforge", "plan.md")
 	var errorCount int
-	for _, diag := range diagnostics {
-		fmt.Printf("%s:%d:%d: %s %s %s\n", planPath, diag.Line, diag.Column, diag.Severity, diag.Code, diag.Message)
+	for i, diag := range diagnostics {
+		if diag.File == "" {
+			diagnostics[i].File = planPath
+		}
 		if diag.Severity == linter.SeverityError {
 			errorCount++
 		}
 	}
 
+	env.report(diagnostics)
+	if !env.JSON {
+		for _, diag := range diagnostics {
+			fmt.Fprintf(env.Stdout, "%s:%d:%d: %s %s %s\n", diag.File, diag.Line, diag.Column, diag.Severity, diag.Code, diag.Message)
+		}
+	}
+
 	if errorCount > 0 {
-		return fmt.Errorf("lint failed with %d error(s)", errorCount)
+		return promptforge.Errorf(promptforge.CodeFindings, "lint failed with %d error(s)", errorCount)
 	}
 
 	return nil
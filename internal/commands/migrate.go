@@ -1,23 +1,17 @@
 package commands
 
 import (
-	"fmt"
-	"os"
-
 	"github.com/promptforge/promptforge/internal/core"
 )
 
 // Migrate upgrades prompt.ir.json to the current IR version.
-func Migrate() error {
-	projectDir, err := os.Getwd()
+func Migrate(env *Env) error {
+	written, err := core.MigrateIR(env.ProjectDir)
 	if err != nil {
-		return fmt.Errorf("failed to get current directory: %w", err)
-	}
-
-	if err := core.MigrateIR(projectDir); err != nil {
 		return err
 	}
+	env.wrote(written...)
 
-	fmt.Println("Migrated prompt.ir.json to current version")
+	env.Printf("Migrated prompt.ir.json to current version\n")
 	return nil
 }
@@ -0,0 +1,25 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/promptforge/promptforge/internal/core"
+	"github.com/promptforge/promptforge/internal/render"
+)
+
+// Render prints the system prompt from prompt.ir.json with variables substituted.
+// With env.JSON set, the rendered IR is recorded in the result envelope instead.
+func Render(env *Env, values map[string]string) error {
+	rendered, err := core.RenderProject(env.ProjectDir, values)
+	if err != nil {
+		return err
+	}
+
+	if env.JSON {
+		env.setData(rendered)
+		return nil
+	}
+
+	fmt.Fprint(env.Stdout, render.SystemPrompt(rendered))
+	return nil
+}
@@ -0,0 +1,104 @@
+package commands
+
+import (
+	"errors"
+	"time"
+
+	"github.com/promptforge/promptforge"
+	"github.com/promptforge/promptforge/internal/linter"
+)
+
+// Envelope statuses.
+const (
+	StatusOK       = "ok"
+	StatusFindings = "findings"
+	StatusError    = "error"
+)
+
+// Result collects what a command did for the --json result envelope: the files
+// it wrote, its diagnostics and any command-specific data. Commands record into
+// it through Env; it is nil unless the command runs with --json.
+type Result struct {
+	Artifacts   []string
+	Diagnostics []linter.Diagnostic
+	Data        interface{}
+}
+
+// Envelope is printed instead of a command's usual output when it runs with
+// --json. Its fields are stable; commands add data under Result.
+type Envelope struct {
+	Command string `json:"command"`
+
+	// Status is "ok", "findings" (the command ran and reported problems) or
+	// "error", following ExitCode.
+	Status   string `json:"status"`
+	ExitCode int    `json:"exit_code"`
+
+	DurationMS int64 `json:"duration_ms"`
+
+	// Artifacts lists the files the command wrote.
+	Artifacts []string `json:"artifacts"`
+
+	Diagnostics []linter.Diagnostic `json:"diagnostics"`
+
+	// Result is the command-specific data, such as the test report.
+	Result interface{} `json:"result,omitempty"`
+
+	// Output is the text the command printed, for commands whose result is text,
+	// such as emit or templates show.
+	Output string `json:"output,omitempty"`
+
+	Error *ErrorInfo `json:"error,omitempty"`
+}
+
+// ErrorInfo describes the error a command failed with. Code, the position and
+// Hint are set when the error is a *promptforge.Error.
+type ErrorInfo struct {
+	Message string           `json:"message"`
+	Code    promptforge.Code `json:"code,omitempty"`
+	File    string           `json:"file,omitempty"`
+	Line    int              `json:"line,omitempty"`
+	Column  int              `json:"column,omitempty"`
+	Hint    string           `json:"hint,omitempty"`
+}
+
+// NewEnvelope builds the envelope for a command that ran for elapsed, recorded
+// result, printed output and returned err.
+func NewEnvelope(command string, result *Result, output string, elapsed time.Duration, err error) *Envelope {
+	envelope := &Envelope{
+		Command:     command,
+		Status:      StatusOK,
+		ExitCode:    promptforge.ExitCode(err),
+		DurationMS:  elapsed.Milliseconds(),
+		Artifacts:   []string{},
+		Diagnostics: []linter.Diagnostic{},
+		Output:      output,
+	}
+	if result != nil {
+		if result.Artifacts != nil {
+			envelope.Artifacts = result.Artifacts
+		}
+		if result.Diagnostics != nil {
+			envelope.Diagnostics = result.Diagnostics
+		}
+		envelope.Result = result.Data
+	}
+
+	if err == nil {
+		return envelope
+	}
+	envelope.Status = StatusError
+	if envelope.ExitCode == promptforge.ExitFindings && promptforge.CodeOf(err) == promptforge.CodeFindings {
+		envelope.Status = StatusFindings
+	}
+	envelope.Error = &ErrorInfo{Message: err.Error()}
+	var e *promptforge.Error
+	if errors.As(err, &e) {
+		envelope.Error.Code = e.Code
+		envelope.Error.File = e.File
+		envelope.Error.Line = e.Line
+		envelope.Error.Column = e.Column
+		envelope.Error.Hint = e.Hint
+	}
+	return envelope
+}
@@ -0,0 +1,76 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/promptforge/promptforge"
+	"github.com/promptforge/promptforge/internal/core"
+	"github.com/promptforge/promptforge/internal/provider"
+)
+
+// Run renders prompt.ir.json, sends it with the input payload read from inputPath
+// to the named provider, and prints the response and whether it satisfies the
+// contract. Relative paths are resolved against the project directory; the mock
+// provider's fixture defaults to promptforge/mock.yaml. An inputPath of "-"
+// reads the payload from standard input.
+func Run(env *Env, providerName string, inputPath string, opts provider.Options, values map[string]string) error {
+	if inputPath == "" {
+		return promptforge.Errorf(promptforge.CodeUsage, "missing --input (a JSON file with the input payload, or - for stdin)")
+	}
+	input, err := readInput(env, inputPath)
+	if err != nil {
+		return err
+	}
+
+	if opts.Fixture == "" {
+		opts.Fixture = filepath.Join("promptforge", "mock.yaml")
+	}
+	opts.Fixture = env.Path(opts.Fixture)
+
+	p, err := provider.New(providerName, opts)
+	if err != nil {
+		return err
+	}
+
+	result, err := core.RunProject(context.Background(), env.ProjectDir, p, input, values)
+	if err != nil {
+		return err
+	}
+
+	env.setData(result)
+	fmt.Fprintln(env.Stdout, result.Response)
+	fmt.Fprintln(env.Stdout)
+	if len(result.Violations) > 0 {
+		fmt.Fprintf(env.Stdout, "Result: %s (%d contract violation(s))\n", result.Outcome, len(result.Violations))
+		for _, violation := range result.Violations {
+			fmt.Fprintf(env.Stdout, "  %s\n", violation)
+		}
+		return promptforge.Errorf(promptforge.CodeContractViolation, "run failed: the response does not satisfy the contract")
+	}
+	fmt.Fprintf(env.Stdout, "Result: %s\n", result.Outcome)
+	return nil
+}
+
+// readInput reads the input payload at inputPath, or from standard input when it
+// is "-".
+func readInput(env *Env, inputPath string) ([]byte, error) {
+	if inputPath == Stdio {
+		return env.readStdin("input payload")
+	}
+
+	inputPath = env.Path(inputPath)
+	input, err := os.ReadFile(inputPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, promptforge.Errorf(promptforge.CodeNotFound, "input file not found at %s", inputPath)
+		}
+		if os.IsPermission(err) {
+			return nil, promptforge.Errorf(promptforge.CodePermissionDenied, "permission denied: cannot read input file at %s", inputPath)
+		}
+		return nil, promptforge.Wrap(err, promptforge.IOCode(err), "failed to read input file at %s", inputPath)
+	}
+	return input, nil
+}
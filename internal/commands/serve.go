@@ -0,0 +1,89 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/promptforge/promptforge"
+	"github.com/promptforge/promptforge/internal/core"
+	"github.com/promptforge/promptforge/internal/server"
+)
+
+// DefaultServeAddr is the address serve listens on when none is given. It only
+// accepts local connections.
+const DefaultServeAddr = "127.0.0.1:8787"
+
+// shutdownTimeout bounds how long serve waits for in-flight requests on shutdown.
+const shutdownTimeout = 10 * time.Second
+
+// Serve runs the JSON API on addr until it receives SIGINT or SIGTERM, then
+// finishes in-flight requests and exits. The project's lint config, if any, is
+// used by /lint and /audit.
+func Serve(env *Env, addr string, maxBodyBytes int64) error {
+	return serve(env, addr, server.Options{MaxBodyBytes: maxBodyBytes}, func(url string) {
+		env.Printf("Listening on %s (API description at /openapi.json)\n", url)
+	})
+}
+
+// serve runs the API handler built from opts on addr until it receives SIGINT or
+// SIGTERM. started is called with the server URL once it accepts connections.
+func serve(env *Env, addr string, opts server.Options, started func(url string)) error {
+	var err error
+	opts.LintConfig, err = core.LoadLintConfig(env.ProjectDir)
+	if err != nil {
+		return err
+	}
+	handler, err := server.New(opts)
+	if err != nil {
+		return err
+	}
+
+	if addr == "" {
+		addr = DefaultServeAddr
+	}
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return promptforge.Wrap(err, promptforge.CodeIO, "failed to listen on %s", addr)
+	}
+
+	srv := &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       time.Minute,
+		WriteTimeout:      time.Minute,
+		IdleTimeout:       2 * time.Minute,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- srv.Serve(listener)
+	}()
+	started(fmt.Sprintf("http://%s", listener.Addr()))
+
+	select {
+	case err := <-serveErr:
+		return fmt.Errorf("server failed: %w", err)
+	case <-ctx.Done():
+	}
+
+	env.Printf("Shutting down\n")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("graceful shutdown failed: %w", err)
+	}
+	if err := <-serveErr; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("server failed: %w", err)
+	}
+	return nil
+}
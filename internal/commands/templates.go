@@ -2,21 +2,155 @@ package commands
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/promptforge/promptforge"
+	"github.com/promptforge/promptforge/internal/core"
+	"github.com/promptforge/promptforge/internal/linter"
 	"github.com/promptforge/promptforge/internal/templates"
 )
 
-// ListTemplates prints available templates.
-func ListTemplates() error {
-	list := templates.List()
+// templateInfo describes a template in the result envelope of templates list.
+type templateInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Source      string `json:"source"`
+	Path        string `json:"path,omitempty"`
+
+	// Error is set for a template that failed to load.
+	Error string `json:"error,omitempty"`
+}
+
+// templateCheck is the result of templates verify for one template.
+type templateCheck struct {
+	Name        string              `json:"name"`
+	Source      string              `json:"source"`
+	OK          bool                `json:"ok"`
+	Error       string              `json:"error,omitempty"`
+	Diagnostics []linter.Diagnostic `json:"diagnostics"`
+}
+
+// ListTemplates prints available templates, including user and project templates.
+func ListTemplates(env *Env) error {
+
+	list, err := templates.Catalog(env.ProjectDir)
+	if err != nil {
+		return err
+	}
+	infos := make([]templateInfo, len(list))
+	for i, tmpl := range list {
+		infos[i] = templateInfo{Name: tmpl.Name, Description: tmpl.Description, Source: tmpl.Source, Path: tmpl.Path}
+		if tmpl.Err != nil {
+			infos[i].Error = tmpl.Err.Error()
+		}
+	}
+	env.setData(infos)
 	if len(list) == 0 {
-		fmt.Println("No templates available")
+		fmt.Fprintln(env.Stdout, "No templates available")
 		return nil
 	}
 
 	for _, tmpl := range list {
-		fmt.Printf("%s - %s\n", tmpl.Name, tmpl.Description)
+		if tmpl.Err != nil {
+			fmt.Fprintf(env.Stdout, "%s - invalid: %v (%s)\n", tmpl.Name, tmpl.Err, tmpl.Source)
+			continue
+		}
+		if tmpl.Source == templates.SourceBuiltin {
+			fmt.Fprintf(env.Stdout, "%s - %s\n", tmpl.Name, tmpl.Description)
+			continue
+		}
+		fmt.Fprintf(env.Stdout, "%s - %s (%s)\n", tmpl.Name, tmpl.Description, tmpl.Source)
+	}
+
+	return nil
+}
+
+// ShowTemplate prints a template's details followed by its plan.
+func ShowTemplate(env *Env, name string) error {
+
+	tmpl, err := templates.Find(env.ProjectDir, name)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(env.Stdout, "Name:        %s\n", tmpl.Name)
+	fmt.Fprintf(env.Stdout, "Description: %s\n", tmpl.Description)
+	fmt.Fprintf(env.Stdout, "Source:      %s\n", tmpl.Source)
+	if tmpl.Path != "" {
+		fmt.Fprintf(env.Stdout, "Path:        %s\n", tmpl.Path)
+	}
+	if len(tmpl.Params) > 0 {
+		fmt.Fprintln(env.Stdout, "Parameters:")
+		for _, param := range tmpl.Params {
+			attrs := param.Type
+			if param.Required {
+				attrs += ", required"
+			}
+			if param.HasDefault {
+				attrs += ", default=" + param.Default
+			}
+			fmt.Fprintf(env.Stdout, "  %s (%s): %s\n", param.Name, attrs, param.Description)
+		}
 	}
+	fmt.Fprintln(env.Stdout)
+	fmt.Fprint(env.Stdout, strings.TrimRight(tmpl.Plan, "\n")+"\n")
+	return nil
+}
+
+// AddTemplate installs a template plan into the project (or user) template directory.
+// This is a thin CLI wrapper around core.AddTemplate.
+func AddTemplate(env *Env, path string, user bool) error {
+
+	tmpl, err := core.AddTemplate(env.ProjectDir, path, user)
+	if err != nil {
+		return err
+	}
+
+	env.wrote(tmpl.Path)
+	env.Printf("Added %s template %s: created %s\n", tmpl.Source, tmpl.Name, tmpl.Path)
+	if _, err := templates.Get(tmpl.Name); err == nil {
+		env.Printf("Note: %s shadows the built-in template of the same name\n", tmpl.Name)
+	}
+	return nil
+}
 
+// VerifyTemplates lints, compiles and validates every available template.
+// This is a thin CLI wrapper around core.VerifyTemplates.
+func VerifyTemplates(env *Env) error {
+
+	checks, err := core.VerifyTemplates(env.ProjectDir)
+	if err != nil {
+		return err
+	}
+
+	results := make([]templateCheck, len(checks))
+	for i, check := range checks {
+		results[i] = templateCheck{Name: check.Template.Name, Source: check.Template.Source, OK: check.Err == nil, Diagnostics: check.Diagnostics}
+		if check.Err != nil {
+			results[i].Error = check.Err.Error()
+		}
+	}
+	env.setData(results)
+
+	var failed int
+	for _, check := range checks {
+		name := check.Template.Name
+		if check.Template.Source != templates.SourceBuiltin {
+			name += " (" + check.Template.Source + ")"
+		}
+		if check.Err != nil {
+			failed++
+			fmt.Fprintf(env.Stdout, "FAIL %s: %v\n", name, check.Err)
+		} else {
+			fmt.Fprintf(env.Stdout, "ok   %s\n", name)
+		}
+		for _, diag := range check.Diagnostics {
+			fmt.Fprintf(env.Stdout, "     %d:%d: %s %s %s\n", diag.Line, diag.Column, diag.Severity, diag.Code, diag.Message)
+		}
+	}
+
+	if failed > 0 {
+		return promptforge.Errorf(promptforge.CodeFindings, "%d of %d template(s) failed verification", failed, len(checks))
+	}
 	return nil
 }
@@ -0,0 +1,67 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/promptforge/promptforge"
+	"github.com/promptforge/promptforge/internal/core"
+)
+
+// Test checks the recorded cases in promptforge/tests against prompt.ir.json and
+// prints one line per case. With junitPath set, it also writes a JUnit XML report;
+// junitPath is relative to the project directory unless absolute, and "-" prints
+// the report to standard output and the case lines to standard error.
+func Test(env *Env, junitPath string) error {
+	stdout := env.Stdout
+	if junitPath == Stdio {
+		env, stdout = env.piped()
+	}
+
+	report, err := core.RunTests(env.ProjectDir)
+	if err != nil {
+		return err
+	}
+
+	env.setData(report)
+	for _, result := range report.Results {
+		if result.Passed {
+			fmt.Fprintf(env.Stdout, "PASS %s (%s)\n", result.Name, result.File)
+			continue
+		}
+		fmt.Fprintf(env.Stdout, "FAIL %s (%s): %s\n", result.Name, result.File, result.Summary())
+		for _, violation := range result.Violations {
+			fmt.Fprintf(env.Stdout, "  %s\n", violation)
+		}
+	}
+
+	failed := report.Failed()
+	fmt.Fprintf(env.Stdout, "%d passed, %d failed\n", len(report.Results)-failed, failed)
+
+	if junitPath != "" {
+		data, err := core.JUnitXML(report)
+		if err != nil {
+			return err
+		}
+		if junitPath == Stdio {
+			if _, err := stdout.Write(data); err != nil {
+				return err
+			}
+		} else {
+			junitPath = env.Path(junitPath)
+			if err := os.WriteFile(junitPath, data, 0644); err != nil {
+				if os.IsPermission(err) {
+					return promptforge.Errorf(promptforge.CodePermissionDenied, "permission denied: cannot write %s", junitPath)
+				}
+				return promptforge.Wrap(err, promptforge.IOCode(err), "failed to write %s", junitPath)
+			}
+			env.wrote(junitPath)
+			env.Printf("Wrote %s\n", junitPath)
+		}
+	}
+
+	if failed > 0 {
+		return promptforge.Errorf(promptforge.CodeFindings, "test failed: %d of %d case(s) failed", failed, len(report.Results))
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package commands
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+
+	"github.com/promptforge/promptforge/internal/server"
+	"github.com/promptforge/promptforge/ui"
+)
+
+// UI serves the PromptForge Lens UI embedded in the binary together with the JSON
+// API, so Plan Builder edits are linted and compiled live. Unless noOpen is set,
+// the UI is opened in the default browser.
+func UI(env *Env, addr string, noOpen bool) error {
+	return serve(env, addr, server.Options{UI: ui.FS}, func(url string) {
+		env.Printf("PromptForge UI at %s (press Ctrl+C to stop)\n", url)
+		if noOpen {
+			return
+		}
+		if err := openBrowser(url); err != nil {
+			fmt.Fprintf(env.Stderr, "Could not open a browser (%v); open the URL above instead\n", err)
+		}
+	})
+}
+
+// openBrowser opens url in the default browser without waiting for it.
+func openBrowser(url string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	case "darwin":
+		cmd = exec.Command("open", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait()
+	return nil
+}
@@ -0,0 +1,109 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/promptforge/promptforge"
+	"github.com/promptforge/promptforge/internal/core"
+	"github.com/promptforge/promptforge/internal/server"
+)
+
+// ValidateOutput checks the model response in responsePath against prompt.ir.json.
+// With repair set, safe repairs are applied first; the repaired response is
+// printed, or written to outPath when it is set. Paths are relative to the
+// project directory unless absolute. A responsePath of "-" reads the response
+// from standard input; an outPath of "-" prints only the repaired response to
+// standard output and everything else to standard error.
+func ValidateOutput(env *Env, responsePath string, repair bool, outPath string) error {
+
+	if responsePath == "" {
+		return promptforge.Errorf(promptforge.CodeUsage, "usage: promptforge validate-output <response-file|-> [--repair] [--out <file|->]")
+	}
+	stdout := env.Stdout
+	if outPath == Stdio {
+		env, stdout = env.piped()
+	}
+
+	response, err := readResponse(env, responsePath)
+	if err != nil {
+		return err
+	}
+
+	report, err := core.ValidateOutput(env.ProjectDir, response, repair)
+	if err != nil {
+		return err
+	}
+
+	env.setData(server.OutputResponse{
+		Output:      string(report.Output),
+		Applied:     report.Applied,
+		Refused:     report.Refused,
+		FailureMode: report.FailureMode,
+		Violations:  report.Violations,
+	})
+
+	for _, change := range report.Applied {
+		fmt.Fprintf(env.Stdout, "repaired %s\n", change)
+	}
+	for _, change := range report.Refused {
+		fmt.Fprintf(env.Stdout, "refused %s\n", change)
+	}
+	if len(report.Applied) > 0 {
+		switch outPath {
+		case "":
+			fmt.Fprintln(env.Stdout, string(report.Output))
+		case Stdio:
+			fmt.Fprintln(stdout, string(report.Output))
+		default:
+			outPath = env.Path(outPath)
+			if err := os.WriteFile(outPath, append(report.Output, '\n'), 0644); err != nil {
+				if os.IsPermission(err) {
+					return promptforge.Errorf(promptforge.CodePermissionDenied, "permission denied: cannot write %s", outPath)
+				}
+				return promptforge.Wrap(err, promptforge.IOCode(err), "failed to write %s", outPath)
+			}
+			env.wrote(outPath)
+			env.Printf("Wrote %s\n", outPath)
+		}
+	} else if outPath == Stdio {
+		// Pass the response through unchanged so the pipeline keeps its output
+		fmt.Fprintln(stdout, strings.TrimRight(string(response), "\n"))
+	}
+
+	for _, violation := range report.Violations {
+		fmt.Fprintf(env.Stdout, "violation %s\n", violation)
+	}
+	if len(report.Violations) > 0 {
+		return promptforge.Errorf(promptforge.CodeContractViolation, "output validation failed with %d violation(s)", len(report.Violations))
+	}
+
+	if report.FailureMode != "" {
+		fmt.Fprintf(env.Stdout, "Response reports failure mode %s\n", report.FailureMode)
+	} else {
+		env.Printf("Response satisfies the contract\n")
+	}
+	return nil
+}
+
+// readResponse reads the model response at responsePath, or from standard input
+// when it is "-".
+func readResponse(env *Env, responsePath string) ([]byte, error) {
+	if responsePath == Stdio {
+		return env.readStdin("response")
+	}
+
+	responsePath = env.Path(responsePath)
+	response, err := os.ReadFile(responsePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, promptforge.Errorf(promptforge.CodeNotFound, "response file not found at %s", responsePath)
+		}
+		if os.IsPermission(err) {
+			return nil, promptforge.Errorf(promptforge.CodePermissionDenied, "permission denied: cannot read response file at %s", responsePath)
+		}
+		return nil, promptforge.Wrap(err, promptforge.IOCode(err), "failed to read response file at %s", responsePath)
+	}
+	return response, nil
+}
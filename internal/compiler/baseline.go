@@ -0,0 +1,71 @@
+package compiler
+
+import "github.com/promptforge/promptforge/internal/ir"
+
+// baselineRules returns the rules every compiled IR starts with.
+func baselineRules() []ir.Rule {
+	return []ir.Rule{
+		{
+			ID:          "output-json",
+			Description: "Output must be valid JSON",
+		},
+		{
+			ID:          "no-explanations",
+			Description: "Do not include explanations unless explicitly requested",
+		},
+		{
+			ID:          "no-inference",
+			Description: "Do not infer missing values - fail if required data is missing",
+		},
+		{
+			ID:          "fail-ambiguity",
+			Description: "Fail on ambiguity - request clarification if intent is unclear",
+		},
+	}
+}
+
+// baselineFailureModes returns the failure modes every compiled IR starts with.
+func baselineFailureModes() []ir.FailureMode {
+	return []ir.FailureMode{
+		{
+			ID:        "invalid-input",
+			Condition: "Input does not match input_schema",
+			Response:  "Return error indicating schema validation failure",
+		},
+		{
+			ID:        "ambiguous-request",
+			Condition: "Request cannot be unambiguously interpreted",
+			Response:  "Return error indicating ambiguity and request clarification",
+		},
+		{
+			ID:        "missing-required",
+			Condition: "Required fields are missing from input",
+			Response:  "Return error listing missing required fields",
+		},
+	}
+}
+
+// ItemIDs returns the IDs Compile assigns to the items of a Constraints or
+// Out of Scope section, in order. It satisfies parser.ItemIDFunc.
+func ItemIDs(section string, items []string) []string {
+	ids := make([]string, 0, len(items))
+	switch section {
+	case "Constraints":
+		existing := make(map[string]bool)
+		for _, rule := range baselineRules() {
+			existing[rule.ID] = true
+		}
+		for _, rule := range generateRules(items, existing) {
+			ids = append(ids, rule.ID)
+		}
+	case "Out of Scope":
+		existing := make(map[string]bool)
+		for _, fm := range baselineFailureModes() {
+			existing[fm.ID] = true
+		}
+		for _, fm := range generateFailureModes(items, existing) {
+			ids = append(ids, fm.ID)
+		}
+	}
+	return ids
+}
@@ -4,11 +4,12 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
-	"os"
 	"regexp"
 	"strings"
 	"sync"
 
+	"github.com/promptforge/promptforge"
+	"github.com/promptforge/promptforge/internal/atomicfile"
 	"github.com/promptforge/promptforge/internal/ir"
 	"github.com/promptforge/promptforge/internal/parser"
 	"github.com/santhosh-tekuri/jsonschema/v5"
@@ -22,7 +23,7 @@ import (
 func Compile(planContent []byte) (*ir.PromptIR, error) {
 	// Verify planContent is not empty
 	if len(planContent) == 0 {
-		return nil, fmt.Errorf("plan.md is empty")
+		return nil, promptforge.Errorf(promptforge.CodePlanEmpty, "plan.md is empty")
 	}
 
 	// Parse the plan.md content
@@ -35,24 +36,7 @@ func Compile(planContent []byte) (*ir.PromptIR, error) {
 	systemRole := generateSystemRole(plan.Goal)
 
 	// Always include baseline rules to ensure deterministic behavior
-	baselineRules := []ir.Rule{
-		{
-			ID:          "output-json",
-			Description: "Output must be valid JSON",
-		},
-		{
-			ID:          "no-explanations",
-			Description: "Do not include explanations unless explicitly requested",
-		},
-		{
-			ID:          "no-inference",
-			Description: "Do not infer missing values - fail if required data is missing",
-		},
-		{
-			ID:          "fail-ambiguity",
-			Description: "Fail on ambiguity - request clarification if intent is unclear",
-		},
-	}
+	baselineRules := baselineRules()
 
 	// Track existing rule IDs to prevent collisions
 	existingRuleIDs := make(map[string]bool)
@@ -67,23 +51,7 @@ func Compile(planContent []byte) (*ir.PromptIR, error) {
 	allRules := append(baselineRules, rules...)
 
 	// Always include baseline failure modes
-	baselineFailureModes := []ir.FailureMode{
-		{
-			ID:        "invalid-input",
-			Condition: "Input does not match input_schema",
-			Response:  "Return error indicating schema validation failure",
-		},
-		{
-			ID:        "ambiguous-request",
-			Condition: "Request cannot be unambiguously interpreted",
-			Response:  "Return error indicating ambiguity and request clarification",
-		},
-		{
-			ID:        "missing-required",
-			Condition: "Required fields are missing from input",
-			Response:  "Return error listing missing required fields",
-		},
-	}
+	baselineFailureModes := baselineFailureModes()
 
 	// Track existing failure mode IDs to prevent collisions
 	existingFailureModeIDs := make(map[string]bool)
@@ -99,6 +67,10 @@ func Compile(planContent []byte) (*ir.PromptIR, error) {
 	// Generate schemas (basic structure for now, can be enhanced)
 	inputSchema, outputSchema := generateSchemas(plan)
 
+	// Compile tool declarations and link rules/failure modes that mention them
+	tools := generateTools(plan.Tools)
+	linkToolReferences(allRules, allFailureModes, tools)
+
 	return &ir.PromptIR{
 		Version:      ir.CurrentVersion,
 		SystemRole:   systemRole,
@@ -106,6 +78,8 @@ func Compile(planContent []byte) (*ir.PromptIR, error) {
 		InputSchema:  inputSchema,
 		OutputSchema: outputSchema,
 		FailureModes: allFailureModes,
+		Tools:        tools,
+		Variables:    generateVariables(plan.Variables),
 	}, nil
 }
 
@@ -342,13 +316,13 @@ func compiledPromptIRSchema() (*jsonschema.Schema, error) {
 	promptIRSchemaOnce.Do(func() {
 		schemaJSON, err := ir.PromptIRSchemaJSON()
 		if err != nil {
-			promptIRSchemaErr = fmt.Errorf("failed to marshal IR schema: %w", err)
+			promptIRSchemaErr = promptforge.Wrap(err, promptforge.CodeInternal, "failed to marshal IR schema")
 			return
 		}
 
 		compiler := jsonschema.NewCompiler()
 		if err := compiler.AddResource("prompt-ir.schema.json", bytes.NewReader(schemaJSON)); err != nil {
-			promptIRSchemaErr = fmt.Errorf("failed to load IR schema: %w", err)
+			promptIRSchemaErr = promptforge.Wrap(err, promptforge.CodeInternal, "failed to load IR schema")
 			return
 		}
 
@@ -366,136 +340,128 @@ func compiledPromptIRSchema() (*jsonschema.Schema, error) {
 // Fails compilation if validation fails.
 func ValidateIR(promptIR *ir.PromptIR) error {
 	if promptIR == nil {
-		return fmt.Errorf("prompt IR is nil")
+		return promptforge.Errorf(promptforge.CodeInvalidArgument, "prompt IR is nil")
 	}
 
 	// Validate system_role is present and non-empty
 	if promptIR.Version == "" {
-		return fmt.Errorf("version is required and cannot be empty")
+		return invalidIR("version is required and cannot be empty")
 	}
 
 	if promptIR.SystemRole == "" {
-		return fmt.Errorf("system_role is required and cannot be empty")
+		return invalidIR("system_role is required and cannot be empty")
 	}
 
 	// Validate rules array is not empty
 	if len(promptIR.Rules) == 0 {
-		return fmt.Errorf("rules array cannot be empty")
+		return invalidIR("rules array cannot be empty")
 	}
 
 	// Validate each rule has required fields
 	for i, rule := range promptIR.Rules {
 		if rule.ID == "" {
-			return fmt.Errorf("rules[%d].id is required and cannot be empty", i)
+			return invalidIR("rules[%d].id is required and cannot be empty", i)
 		}
 		if rule.Description == "" {
-			return fmt.Errorf("rules[%d].description is required and cannot be empty", i)
+			return invalidIR("rules[%d].description is required and cannot be empty", i)
 		}
 	}
 
 	// Validate input_schema has type
 	if promptIR.InputSchema.Type == "" {
-		return fmt.Errorf("input_schema.type is required and cannot be empty")
+		return invalidIR("input_schema.type is required and cannot be empty")
 	}
 
 	// Validate output_schema has type
 	if promptIR.OutputSchema.Type == "" {
-		return fmt.Errorf("output_schema.type is required and cannot be empty")
+		return invalidIR("output_schema.type is required and cannot be empty")
 	}
 
 	// Validate failure_modes array is not empty
 	if len(promptIR.FailureModes) == 0 {
-		return fmt.Errorf("failure_modes array cannot be empty")
+		return invalidIR("failure_modes array cannot be empty")
 	}
 
 	// Validate each failure mode has required fields
 	for i, fm := range promptIR.FailureModes {
 		if fm.ID == "" {
-			return fmt.Errorf("failure_modes[%d].id is required and cannot be empty", i)
+			return invalidIR("failure_modes[%d].id is required and cannot be empty", i)
 		}
 		if fm.Condition == "" {
-			return fmt.Errorf("failure_modes[%d].condition is required and cannot be empty", i)
+			return invalidIR("failure_modes[%d].condition is required and cannot be empty", i)
 		}
 		if fm.Response == "" {
-			return fmt.Errorf("failure_modes[%d].response is required and cannot be empty", i)
+			return invalidIR("failure_modes[%d].response is required and cannot be empty", i)
 		}
 	}
 
 	schema, err := compiledPromptIRSchema()
 	if err != nil {
-		return fmt.Errorf("failed to compile IR schema: %w", err)
+		return promptforge.Wrap(err, promptforge.CodeInternal, "failed to compile IR schema")
 	}
 
 	data, err := json.Marshal(promptIR)
 	if err != nil {
-		return fmt.Errorf("failed to marshal IR for schema validation: %w", err)
+		return promptforge.Wrap(err, promptforge.CodeInternal, "failed to marshal IR for schema validation")
 	}
 
 	var payload interface{}
 	if err := json.Unmarshal(data, &payload); err != nil {
-		return fmt.Errorf("failed to unmarshal IR for schema validation: %w", err)
+		return promptforge.Wrap(err, promptforge.CodeInternal, "failed to unmarshal IR for schema validation")
 	}
 
 	if err := schema.Validate(payload); err != nil {
-		return fmt.Errorf("schema validation failed: %w", err)
+		return promptforge.Wrap(err, promptforge.CodeIRInvalid, "schema validation failed")
 	}
 
 	return nil
 }
 
+// invalidIR reports an IR that fails ValidateIR.
+func invalidIR(format string, args ...interface{}) error {
+	return promptforge.Errorf(promptforge.CodeIRInvalid, format, args...)
+}
+
 // WriteIR writes the PromptIR to a JSON file.
-// Returns detailed error messages for file system issues.
+// The file is replaced atomically; errors carry the permission-denied and
+// disk-full codes.
 func WriteIR(promptIR *ir.PromptIR, outputPath string) error {
-	// Validate IR before writing - fail compilation if validation fails
-	if err := ValidateIR(promptIR); err != nil {
-		return fmt.Errorf("IR validation failed: %w", err)
-	}
-
 	// Validate output path
 	if outputPath == "" {
-		return fmt.Errorf("output path cannot be empty")
+		return promptforge.Errorf(promptforge.CodeInvalidArgument, "output path cannot be empty")
 	}
 
-	data, err := json.MarshalIndent(promptIR, "", "  ")
+	data, err := MarshalIR(promptIR)
 	if err != nil {
-		return fmt.Errorf("failed to marshal IR to JSON: %w", err)
+		return err
 	}
+	return atomicfile.WriteFile(outputPath, "prompt.ir.json", data, 0644)
+}
 
-	// Write file with better error handling
-	if err := os.WriteFile(outputPath, data, 0644); err != nil {
-		if os.IsPermission(err) {
-			return fmt.Errorf("permission denied: cannot write to %s", outputPath)
-		}
-		// Check for disk full (platform-specific)
-		if err.Error() == "no space left on device" || err.Error() == "not enough space" {
-			return fmt.Errorf("disk full: cannot write to %s", outputPath)
-		}
-		return fmt.Errorf("failed to write IR file to %s: %w", outputPath, err)
+// MarshalIR validates the PromptIR and encodes it as written to prompt.ir.json.
+func MarshalIR(promptIR *ir.PromptIR) ([]byte, error) {
+	// Validate IR before writing - fail compilation if validation fails
+	if err := ValidateIR(promptIR); err != nil {
+		return nil, fmt.Errorf("IR validation failed: %w", err)
 	}
 
-	return nil
+	data, err := json.MarshalIndent(promptIR, "", "  ")
+	if err != nil {
+		return nil, promptforge.Wrap(err, promptforge.CodeInternal, "failed to marshal IR to JSON")
+	}
+	return data, nil
 }
 
 // WriteIRSchema writes the PromptIR JSON Schema to disk.
 func WriteIRSchema(outputPath string) error {
 	if outputPath == "" {
-		return fmt.Errorf("output path cannot be empty")
+		return promptforge.Errorf(promptforge.CodeInvalidArgument, "output path cannot be empty")
 	}
 
 	data, err := ir.PromptIRSchemaJSON()
 	if err != nil {
-		return fmt.Errorf("failed to marshal IR schema: %w", err)
-	}
-
-	if err := os.WriteFile(outputPath, data, 0644); err != nil {
-		if os.IsPermission(err) {
-			return fmt.Errorf("permission denied: cannot write to %s", outputPath)
-		}
-		if err.Error() == "no space left on device" || err.Error() == "not enough space" {
-			return fmt.Errorf("disk full: cannot write to %s", outputPath)
-		}
-		return fmt.Errorf("failed to write IR schema to %s: %w", outputPath, err)
+		return promptforge.Wrap(err, promptforge.CodeInternal, "failed to marshal IR schema")
 	}
 
-	return nil
+	return atomicfile.WriteFile(outputPath, "prompt.ir.schema.json", data, 0644)
 }
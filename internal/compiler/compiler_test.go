@@ -546,3 +546,158 @@ Build a deterministic assistant.
 		t.Error("Expected failure modes in explain report")
 	}
 }
+
+// TestCompile_Tools tests that tool declarations compile into IR tools with parameter schemas.
+func TestCompile_Tools(t *testing.T) {
+	planContent := []byte(`# Prompt Plan
+
+## Goal
+Help customers track their orders
+
+## Tools
+### search_orders
+Look up recent orders for a customer.
+- email (string, required): Customer email address
+- tags (string[]): Filter by tags
+
+## Constraints
+- Always call search_orders before answering
+
+## Out of Scope
+- Refunds without search_orders results
+`)
+
+	compiled, err := Compile(planContent)
+	if err != nil {
+		t.Fatalf("Compile() failed: %v", err)
+	}
+	if err := ValidateIR(compiled); err != nil {
+		t.Fatalf("Compiled IR failed validation: %v", err)
+	}
+
+	if len(compiled.Tools) != 1 {
+		t.Fatalf("Expected 1 tool, got %d", len(compiled.Tools))
+	}
+	tool := compiled.Tools[0]
+	if tool.Name != "search_orders" || tool.Parameters.Type != "object" {
+		t.Errorf("Unexpected tool: %+v", tool)
+	}
+	if len(tool.Parameters.Required) != 1 || tool.Parameters.Required[0] != "email" {
+		t.Errorf("Expected email to be required, got %v", tool.Parameters.Required)
+	}
+	tags := tool.Parameters.Properties["tags"]
+	if tags.Type != "array" || tags.Items == nil || tags.Items.Type != "string" {
+		t.Errorf("Expected tags to be an array of strings, got %+v", tags)
+	}
+
+	last := compiled.Rules[len(compiled.Rules)-1]
+	if len(last.Tools) != 1 || last.Tools[0] != "search_orders" {
+		t.Errorf("Expected rule to reference search_orders, got %v", last.Tools)
+	}
+	lastFM := compiled.FailureModes[len(compiled.FailureModes)-1]
+	if len(lastFM.Tools) != 1 {
+		t.Errorf("Expected failure mode to reference search_orders, got %v", lastFM.Tools)
+	}
+	if len(compiled.Rules[0].Tools) != 0 {
+		t.Errorf("Baseline rules should not reference tools")
+	}
+}
+
+// TestCompile_WrappedListItems tests that an indented line without a marker
+// continues the item above it, as written by fmt. Before continuation lines were
+// joined, "under 120 words" compiled to a rule of its own.
+func TestCompile_WrappedListItems(t *testing.T) {
+	planContent := []byte(`## Goal
+Answer billing questions
+
+## Constraints
+- Keep answers short
+  under 120 words
+Escalate refunds above 500 EUR
+`)
+
+	for name, compile := range map[string]func([]byte) (*ir.PromptIR, error){
+		"Compile": Compile,
+		"CompileWithExplain": func(content []byte) (*ir.PromptIR, error) {
+			compiled, _, err := CompileWithExplain(content)
+			return compiled, err
+		},
+	} {
+		compiled, err := compile(planContent)
+		if err != nil {
+			t.Fatalf("%s() failed: %v", name, err)
+		}
+		var got []string
+		for _, rule := range compiled.Rules[len(baselineRules()):] {
+			got = append(got, rule.Description)
+		}
+		want := []string{"Keep answers short under 120 words", "Escalate refunds above 500 EUR"}
+		if strings.Join(got, "|") != strings.Join(want, "|") {
+			t.Errorf("%s(): expected rules %q, got %q", name, want, got)
+		}
+	}
+}
+
+// TestCompileWithExplain_MatchesCompile tests that both compile paths produce the same IR.
+func TestCompileWithExplain_MatchesCompile(t *testing.T) {
+	planContent, err := os.ReadFile(filepath.Join("testdata", "simple_plan.md"))
+	if err != nil {
+		t.Fatalf("Failed to read plan fixture: %v", err)
+	}
+
+	compiled, err := Compile(planContent)
+	if err != nil {
+		t.Fatalf("Compile() failed: %v", err)
+	}
+	explained, report, err := CompileWithExplain(planContent)
+	if err != nil {
+		t.Fatalf("CompileWithExplain() failed: %v", err)
+	}
+
+	compiledJSON, _ := json.Marshal(compiled)
+	explainedJSON, _ := json.Marshal(explained)
+	if string(compiledJSON) != string(explainedJSON) {
+		t.Fatalf("CompileWithExplain() IR differs from Compile():\n%s\n%s", compiledJSON, explainedJSON)
+	}
+
+	if report.SystemRole.Source.Line != 4 {
+		t.Errorf("Expected Goal source on line 4, got %d", report.SystemRole.Source.Line)
+	}
+}
+
+// TestCompile_Variables tests that declared variables are stored in the IR and slots are preserved.
+func TestCompile_Variables(t *testing.T) {
+	planContent := []byte(`# Prompt Plan
+
+## Goal
+Support customers of {{company_name}}
+
+## Constraints
+- Use a {{tone}} tone
+
+## Variables
+- company_name (string, required): Company display name
+- tone (string, default=friendly): Voice of replies
+`)
+
+	compiled, err := Compile(planContent)
+	if err != nil {
+		t.Fatalf("Compile() failed: %v", err)
+	}
+	if err := ValidateIR(compiled); err != nil {
+		t.Fatalf("Compiled IR failed validation: %v", err)
+	}
+
+	if len(compiled.Variables) != 2 {
+		t.Fatalf("Expected 2 variables, got %d", len(compiled.Variables))
+	}
+	if !compiled.Variables[0].Required || compiled.Variables[0].Default != nil {
+		t.Errorf("Unexpected company_name variable: %+v", compiled.Variables[0])
+	}
+	if compiled.Variables[1].Default == nil || *compiled.Variables[1].Default != "friendly" {
+		t.Errorf("Unexpected tone variable: %+v", compiled.Variables[1])
+	}
+	if !strings.Contains(compiled.SystemRole, "{{company_name}}") {
+		t.Errorf("SystemRole should keep the slot for render time: %q", compiled.SystemRole)
+	}
+}
@@ -3,9 +3,10 @@ package compiler
 import (
 	"encoding/json"
 	"fmt"
-	"os"
 	"strings"
 
+	"github.com/promptforge/promptforge"
+	"github.com/promptforge/promptforge/internal/atomicfile"
 	"github.com/promptforge/promptforge/internal/ir"
 	"github.com/promptforge/promptforge/internal/parser"
 )
@@ -16,12 +17,18 @@ type ExplainReport struct {
 	InputSchema  ExplainSchema        `json:"input_schema"`
 	OutputSchema ExplainSchema        `json:"output_schema"`
 	FailureModes []ExplainFailureMode `json:"failure_modes"`
+	Tools        []ExplainTool        `json:"tools,omitempty"`
+	Variables    []ExplainVariable    `json:"variables,omitempty"`
 }
 
 type ExplainSource struct {
 	Type    string `json:"type"`
 	Section string `json:"section,omitempty"`
+	File    string `json:"file,omitempty"`
 	Line    int    `json:"line,omitempty"`
+
+	// Origin is "base" or "child" when the plan extends a base plan.
+	Origin string `json:"origin,omitempty"`
 }
 
 type ExplainSystemRole struct {
@@ -46,10 +53,20 @@ type ExplainFailureMode struct {
 	Source    ExplainSource `json:"source"`
 }
 
+type ExplainTool struct {
+	Name   string        `json:"name"`
+	Source ExplainSource `json:"source"`
+}
+
+type ExplainVariable struct {
+	Name   string        `json:"name"`
+	Source ExplainSource `json:"source"`
+}
+
 // CompileWithExplain compiles plan content and returns an explain report.
 func CompileWithExplain(planContent []byte) (*ir.PromptIR, *ExplainReport, error) {
 	if len(planContent) == 0 {
-		return nil, nil, fmt.Errorf("plan.md is empty")
+		return nil, nil, promptforge.Errorf(promptforge.CodePlanEmpty, "plan.md is empty")
 	}
 
 	plan, err := parser.ParsePlanWithLines(planContent)
@@ -58,12 +75,7 @@ func CompileWithExplain(planContent []byte) (*ir.PromptIR, *ExplainReport, error
 	}
 
 	systemRole := generateSystemRole(plan.Plan.Goal)
-	baselineRules := []ir.Rule{
-		{ID: "output-json", Description: "Output must be valid JSON"},
-		{ID: "no-explanations", Description: "Do not include explanations unless explicitly requested"},
-		{ID: "no-inference", Description: "Do not infer missing values - fail if required data is missing"},
-		{ID: "fail-ambiguity", Description: "Fail on ambiguity - request clarification if intent is unclear"},
-	}
+	baselineRules := baselineRules()
 
 	existingRuleIDs := make(map[string]bool)
 	for _, rule := range baselineRules {
@@ -73,23 +85,7 @@ func CompileWithExplain(planContent []byte) (*ir.PromptIR, *ExplainReport, error
 	rules, rulesExplain := generateRulesWithExplain(plan.Constraints, existingRuleIDs)
 	allRules := append(baselineRules, rules...)
 
-	baselineFailureModes := []ir.FailureMode{
-		{
-			ID:        "invalid-input",
-			Condition: "Input does not match input_schema",
-			Response:  "Return error indicating schema validation failure",
-		},
-		{
-			ID:        "ambiguous-request",
-			Condition: "Request cannot be unambiguously interpreted",
-			Response:  "Return error indicating ambiguity and request clarification",
-		},
-		{
-			ID:        "missing-required",
-			Condition: "Required fields are missing from input",
-			Response:  "Return error listing missing required fields",
-		},
-	}
+	baselineFailureModes := baselineFailureModes()
 
 	existingFailureModeIDs := make(map[string]bool)
 	for _, fm := range baselineFailureModes {
@@ -101,6 +97,10 @@ func CompileWithExplain(planContent []byte) (*ir.PromptIR, *ExplainReport, error
 
 	inputSchema, outputSchema := generateSchemas(plan.Plan)
 
+	tools := generateTools(plan.Tools)
+	linkToolReferences(allRules, allFailureModes, tools)
+	variables := generateVariables(plan.Variables)
+
 	report := &ExplainReport{
 		SystemRole: ExplainSystemRole{
 			Value: systemRole,
@@ -158,6 +158,8 @@ func CompileWithExplain(planContent []byte) (*ir.PromptIR, *ExplainReport, error
 				Source:    ExplainSource{Type: "baseline"},
 			},
 		}, failureExplain...),
+		Tools:     explainTools(plan.Tools, tools),
+		Variables: explainVariables(plan.Variables, variables),
 	}
 
 	return &ir.PromptIR{
@@ -167,9 +169,36 @@ func CompileWithExplain(planContent []byte) (*ir.PromptIR, *ExplainReport, error
 		InputSchema:  inputSchema,
 		OutputSchema: outputSchema,
 		FailureModes: allFailureModes,
+		Tools:        tools,
+		Variables:    variables,
 	}, report, nil
 }
 
+// explainTools maps each compiled tool back to the heading that declared it.
+func explainTools(declared []parser.Tool, compiled []ir.Tool) []ExplainTool {
+	if len(compiled) == 0 {
+		return nil
+	}
+
+	explain := make([]ExplainTool, 0, len(compiled))
+	for _, tool := range compiled {
+		for _, decl := range declared {
+			if decl.Name == tool.Name {
+				explain = append(explain, ExplainTool{
+					Name: tool.Name,
+					Source: ExplainSource{
+						Type:    "plan",
+						Section: "Tools",
+						Line:    decl.Line,
+					},
+				})
+				break
+			}
+		}
+	}
+	return explain
+}
+
 func generateRulesWithExplain(constraints []parser.PlanItem, existingIDs map[string]bool) ([]ir.Rule, []ExplainRule) {
 	if len(constraints) == 0 {
 		return []ir.Rule{}, []ExplainRule{}
@@ -247,29 +276,80 @@ func generateFailureModesWithExplain(items []parser.PlanItem, existingIDs map[st
 	return failureModes, explain
 }
 
-// WriteExplainReport writes the explain report to a JSON file.
-func WriteExplainReport(report *ExplainReport, outputPath string) error {
-	if report == nil {
-		return fmt.Errorf("explain report is nil")
+// RemapSources calls remap for every source that points at a plan line, so callers
+// can attribute expanded plan lines to the file and plan they came from.
+func (r *ExplainReport) RemapSources(remap func(source *ExplainSource)) {
+	remapPlan := func(source *ExplainSource) {
+		if source.Type != "plan" || source.Line == 0 {
+			return
+		}
+		remap(source)
+	}
+
+	remapPlan(&r.SystemRole.Source)
+	for i := range r.Rules {
+		remapPlan(&r.Rules[i].Source)
+	}
+	for i := range r.FailureModes {
+		remapPlan(&r.FailureModes[i].Source)
+	}
+	for i := range r.Tools {
+		remapPlan(&r.Tools[i].Source)
+	}
+	for i := range r.Variables {
+		remapPlan(&r.Variables[i].Source)
 	}
+}
+
+// WriteExplainReport writes the explain report to a JSON file, replacing it
+// atomically.
+func WriteExplainReport(report *ExplainReport, outputPath string) error {
 	if outputPath == "" {
-		return fmt.Errorf("output path cannot be empty")
+		return promptforge.Errorf(promptforge.CodeInvalidArgument, "output path cannot be empty")
+	}
+
+	data, err := MarshalExplainReport(report)
+	if err != nil {
+		return err
+	}
+	return atomicfile.WriteFile(outputPath, "prompt.ir.explain.json", data, 0644)
+}
+
+// MarshalExplainReport encodes the explain report as written to
+// prompt.ir.explain.json.
+func MarshalExplainReport(report *ExplainReport) ([]byte, error) {
+	if report == nil {
+		return nil, promptforge.Errorf(promptforge.CodeInvalidArgument, "explain report is nil")
 	}
 
 	data, err := json.MarshalIndent(report, "", "  ")
 	if err != nil {
-		return fmt.Errorf("failed to marshal explain report: %w", err)
+		return nil, promptforge.Wrap(err, promptforge.CodeInternal, "failed to marshal explain report")
 	}
+	return data, nil
+}
 
-	if err := os.WriteFile(outputPath, data, 0644); err != nil {
-		if os.IsPermission(err) {
-			return fmt.Errorf("permission denied: cannot write to %s", outputPath)
-		}
-		if err.Error() == "no space left on device" || err.Error() == "not enough space" {
-			return fmt.Errorf("disk full: cannot write to %s", outputPath)
-		}
-		return fmt.Errorf("failed to write explain report to %s: %w", outputPath, err)
+// explainVariables maps each compiled variable back to its declaration.
+func explainVariables(declared []parser.Parameter, compiled []ir.Variable) []ExplainVariable {
+	if len(compiled) == 0 {
+		return nil
 	}
 
-	return nil
+	explain := make([]ExplainVariable, 0, len(compiled))
+	for _, variable := range compiled {
+		for _, decl := range declared {
+			if decl.Name == variable.Name {
+				explain = append(explain, ExplainVariable{
+					Name: variable.Name,
+					Source: ExplainSource{
+						Type:    "plan",
+						Section: "Variables",
+						Line:    decl.Line,
+					},
+				})
+				break
+			}
+		}
+	}
+	return explain
 }
@@ -0,0 +1,96 @@
+package compiler
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/promptforge/promptforge/internal/ir"
+	"github.com/promptforge/promptforge/internal/parser"
+)
+
+// generateTools converts tool declarations into IR tools with a JSON Schema per tool.
+// A declaration reusing an earlier tool name is dropped; the linter reports it as PF104.
+func generateTools(tools []parser.Tool) []ir.Tool {
+	if len(tools) == 0 {
+		return nil
+	}
+
+	result := make([]ir.Tool, 0, len(tools))
+	seen := make(map[string]bool)
+	for _, tool := range tools {
+		name := strings.TrimSpace(tool.Name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		result = append(result, ir.Tool{
+			Name:        name,
+			Description: strings.TrimSpace(tool.Description),
+			Parameters:  toolParameterSchema(tool.Parameters),
+		})
+	}
+
+	return result
+}
+
+// toolParameterSchema builds the object schema describing a tool's arguments.
+func toolParameterSchema(params []parser.Parameter) ir.Schema {
+	schema := ir.Schema{
+		Type:       "object",
+		Properties: map[string]ir.Property{},
+		Required:   []string{},
+	}
+
+	for _, param := range params {
+		if _, exists := schema.Properties[param.Name]; exists {
+			continue
+		}
+
+		property := ir.Property{
+			Type:        param.Type,
+			Description: param.Description,
+		}
+		if elem, ok := parser.IsArrayType(param.Type); ok {
+			property.Type = "array"
+			property.Items = &ir.Schema{Type: elem}
+		}
+		schema.Properties[param.Name] = property
+
+		if param.Required {
+			schema.Required = append(schema.Required, param.Name)
+		}
+	}
+
+	return schema
+}
+
+// linkToolReferences records which declared tools each rule and failure mode mentions by name.
+// A failure mode refers to a tool named in its condition or its response.
+func linkToolReferences(rules []ir.Rule, failureModes []ir.FailureMode, tools []ir.Tool) {
+	if len(tools) == 0 {
+		return
+	}
+
+	patterns := make([]*regexp.Regexp, len(tools))
+	for i, tool := range tools {
+		patterns[i] = regexp.MustCompile(`(^|[^A-Za-z0-9_-])` + regexp.QuoteMeta(tool.Name) + `($|[^A-Za-z0-9_-])`)
+	}
+
+	referenced := func(text string) []string {
+		var names []string
+		for i, tool := range tools {
+			if patterns[i].MatchString(text) {
+				names = append(names, tool.Name)
+			}
+		}
+		return names
+	}
+
+	for i := range rules {
+		rules[i].Tools = referenced(rules[i].Description)
+	}
+	for i := range failureModes {
+		failureModes[i].Tools = referenced(failureModes[i].Condition + "\n" + failureModes[i].Response)
+	}
+}
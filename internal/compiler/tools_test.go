@@ -0,0 +1,40 @@
+package compiler
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/promptforge/promptforge/internal/ir"
+)
+
+func TestLinkToolReferences(t *testing.T) {
+	tools := []ir.Tool{{Name: "search_orders"}, {Name: "refund"}}
+	rules := []ir.Rule{
+		{ID: "constraint-lookup", Description: "Call search_orders before answering"},
+		{ID: "constraint-refunds", Description: "Never promise refunds"},
+	}
+	failureModes := []ir.FailureMode{
+		{ID: "order-missing", Condition: "The order cannot be found", Response: "Ask for the order number and retry search_orders"},
+		{ID: "refund-request", Condition: "Customer asks for a refund", Response: "Escalate to a human agent"},
+		{ID: "invalid-input", Condition: "Input does not match input_schema", Response: "Return error"},
+	}
+
+	linkToolReferences(rules, failureModes, tools)
+
+	tests := []struct {
+		id   string
+		got  []string
+		want string
+	}{
+		{"constraint-lookup", rules[0].Tools, "search_orders"},
+		{"constraint-refunds", rules[1].Tools, ""},
+		{"order-missing", failureModes[0].Tools, "search_orders"},
+		{"refund-request", failureModes[1].Tools, "refund"},
+		{"invalid-input", failureModes[2].Tools, ""},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(tt.got, ","); got != tt.want {
+			t.Errorf("%s: expected tools %q, got %q", tt.id, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package compiler
+
+import (
+	"strings"
+
+	"github.com/promptforge/promptforge/internal/ir"
+	"github.com/promptforge/promptforge/internal/parser"
+)
+
+// generateVariables converts variable declarations into IR variables.
+// A declaration reusing an earlier name is dropped; the linter reports it as PF108.
+func generateVariables(declared []parser.Parameter) []ir.Variable {
+	if len(declared) == 0 {
+		return nil
+	}
+
+	variables := make([]ir.Variable, 0, len(declared))
+	seen := make(map[string]bool)
+	for _, decl := range declared {
+		if seen[decl.Name] {
+			continue
+		}
+		seen[decl.Name] = true
+
+		variable := ir.Variable{
+			Name:        decl.Name,
+			Type:        decl.Type,
+			Required:    decl.Required,
+			Description: strings.TrimSpace(decl.Description),
+		}
+		if decl.HasDefault {
+			value := decl.Default
+			variable.Default = &value
+		}
+		variables = append(variables, variable)
+	}
+
+	return variables
+}
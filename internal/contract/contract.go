@@ -0,0 +1,318 @@
+// Package contract checks payloads against a compiled prompt contract: inputs
+// against input_schema, and model responses against output_schema and the rules
+// that can be checked mechanically.
+package contract
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/promptforge/promptforge/internal/ir"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// IDs reported by the validator. Rule and failure mode IDs match the baseline
+// ones every compiled IR starts with.
+const (
+	RuleOutputJSON         = "output-json"
+	RuleNoExplanations     = "no-explanations"
+	OutputSchemaID         = "output-schema"
+	UndeclaredFailureID    = "undeclared-failure-mode"
+	FailureInvalidInput    = "invalid-input"
+	FailureMissingRequired = "missing-required"
+)
+
+// Violation is one way a payload breaks the contract.
+type Violation struct {
+	// ID is the rule, failure mode or check the violation maps to, such as
+	// "output-json", "output-schema" or "missing-required".
+	ID string `json:"id"`
+
+	// Path is the JSON pointer of the offending value; empty means the whole document.
+	Path string `json:"path,omitempty"`
+
+	Message string `json:"message"`
+}
+
+func (v Violation) String() string {
+	if v.Path == "" {
+		return fmt.Sprintf("%s: %s", v.ID, v.Message)
+	}
+	return fmt.Sprintf("%s %s: %s", v.ID, v.Path, v.Message)
+}
+
+// Output is the result of checking a model response.
+type Output struct {
+	// Value is the decoded JSON response, or nil when it is not valid JSON.
+	Value interface{}
+
+	// FailureMode is set when the response is an error response naming a failure
+	// mode: {"error": "<id>"} or {"error": {"code": "<id>", ...}}.
+	FailureMode string
+
+	Violations []Violation
+}
+
+// Validator checks payloads against one prompt contract. It is safe for
+// concurrent use.
+type Validator struct {
+	input        *jsonschema.Schema
+	output       *jsonschema.Schema
+	rules        map[string]bool
+	failureModes map[string]bool
+
+	fastInput  *fastNode
+	fastOutput *fastNode
+
+	// errorConvention is false when output_schema declares its own "error"
+	// property, so that an error-shaped response is validated like any other.
+	errorConvention bool
+}
+
+// NewValidator compiles the input and output schemas of promptIR.
+func NewValidator(promptIR *ir.PromptIR) (*Validator, error) {
+	if promptIR == nil {
+		return nil, fmt.Errorf("prompt IR is nil")
+	}
+
+	input, err := compileSchema("prompt.input.schema.json", promptIR.InputSchema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile input_schema: %w", err)
+	}
+	output, err := compileSchema("prompt.output.schema.json", promptIR.OutputSchema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile output_schema: %w", err)
+	}
+
+	v := &Validator{
+		input:           input,
+		output:          output,
+		rules:           make(map[string]bool, len(promptIR.Rules)),
+		failureModes:    make(map[string]bool, len(promptIR.FailureModes)),
+		errorConvention: true,
+		fastInput:       newFastSchema(promptIR.InputSchema),
+		fastOutput:      newFastSchema(promptIR.OutputSchema),
+	}
+	for _, rule := range promptIR.Rules {
+		v.rules[rule.ID] = true
+	}
+	for _, fm := range promptIR.FailureModes {
+		v.failureModes[fm.ID] = true
+	}
+	if _, ok := promptIR.OutputSchema.Properties["error"]; ok {
+		v.errorConvention = false
+	}
+	return v, nil
+}
+
+func compileSchema(url string, schema ir.Schema) (*jsonschema.Schema, error) {
+	data, err := json.Marshal(ir.ContractJSONSchema(schema, "", ""))
+	if err != nil {
+		return nil, err
+	}
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource(url, bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+	return compiler.Compile(url)
+}
+
+// HasFailureMode reports whether the contract declares the failure mode id.
+func (v *Validator) HasFailureMode(id string) bool {
+	return v.failureModes[id]
+}
+
+// ValidateInput checks an input payload against input_schema. Missing required
+// fields are reported as "missing-required" when the contract declares that
+// failure mode; every other mismatch is reported as "invalid-input".
+func (v *Validator) ValidateInput(input interface{}) []Violation {
+	value, err := normalize(input)
+	if err != nil {
+		return []Violation{{ID: FailureInvalidInput, Message: err.Error()}}
+	}
+
+	var violations []Violation
+	for _, e := range schemaErrors(v.input, value) {
+		id := FailureInvalidInput
+		if strings.HasSuffix(e.keyword, "/required") && v.failureModes[FailureMissingRequired] {
+			id = FailureMissingRequired
+		}
+		violations = append(violations, Violation{ID: id, Path: e.instance, Message: e.message})
+	}
+	return violations
+}
+
+// ValidateInputJSON is ValidateInput for a raw JSON input payload.
+func (v *Validator) ValidateInputJSON(input []byte) []Violation {
+	decoder := json.NewDecoder(bytes.NewReader(input))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return []Violation{{ID: FailureInvalidInput, Message: fmt.Sprintf("input is not valid JSON: %v", err)}}
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return []Violation{{ID: FailureInvalidInput, Message: "input has data after the JSON value"}}
+	}
+	return v.ValidateInput(value)
+}
+
+// InputValid reports whether a raw JSON input certainly satisfies input_schema.
+// It does not decode or allocate; when it returns false, ValidateInputJSON gives
+// the authoritative answer.
+func (v *Validator) InputValid(input []byte) bool {
+	s := fastScanner{data: input}
+	return s.document(v.fastInput)
+}
+
+// OutputValid reports whether a raw response certainly satisfies the contract:
+// a single JSON value matching output_schema that is not an error response. It
+// does not decode or allocate; when it returns false, ValidateOutput gives the
+// authoritative answer.
+func (v *Validator) OutputValid(response []byte) bool {
+	s := fastScanner{data: response, rootErrorKey: v.errorConvention}
+	return s.document(v.fastOutput)
+}
+
+// ValidateOutput checks a raw model response: it must be a single JSON value
+// (output-json) with no surrounding prose (no-explanations, when the contract has
+// that rule) that matches output_schema, unless it is an error response naming a
+// declared failure mode.
+func (v *Validator) ValidateOutput(response []byte) Output {
+	var out Output
+
+	trimmed := bytes.TrimSpace(response)
+	if len(trimmed) == 0 {
+		out.Violations = append(out.Violations, Violation{ID: RuleOutputJSON, Message: "response is empty"})
+		return out
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(trimmed))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		message := fmt.Sprintf("response is not valid JSON: %v", err)
+		if bytes.HasPrefix(trimmed, []byte("```")) {
+			message = "response is wrapped in a Markdown code fence"
+		}
+		out.Violations = append(out.Violations, Violation{ID: RuleOutputJSON, Message: message})
+		return out
+	}
+	out.Value = value
+
+	if _, err := decoder.Token(); err != io.EOF && v.rules[RuleNoExplanations] {
+		rest := strings.TrimSpace(string(trimmed[decoder.InputOffset():]))
+		out.Violations = append(out.Violations, Violation{
+			ID:      RuleNoExplanations,
+			Message: fmt.Sprintf("response has text after the JSON value: %q", truncate(rest, 40)),
+		})
+	}
+
+	if id, ok := v.failureModeOf(value); ok {
+		out.FailureMode = id
+		if !v.failureModes[id] {
+			out.Violations = append(out.Violations, Violation{
+				ID:      UndeclaredFailureID,
+				Path:    "/error",
+				Message: fmt.Sprintf("response reports failure mode %q, which the contract does not declare", id),
+			})
+		}
+		return out
+	}
+
+	for _, e := range schemaErrors(v.output, value) {
+		out.Violations = append(out.Violations, Violation{ID: OutputSchemaID, Path: e.instance, Message: e.message})
+	}
+	return out
+}
+
+// failureModeOf returns the failure mode named by an error response.
+func (v *Validator) failureModeOf(value interface{}) (string, bool) {
+	if !v.errorConvention {
+		return "", false
+	}
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return "", false
+	}
+	switch e := object["error"].(type) {
+	case string:
+		return e, e != ""
+	case map[string]interface{}:
+		for _, key := range []string{"code", "id"} {
+			if id, ok := e[key].(string); ok && id != "" {
+				return id, true
+			}
+		}
+	}
+	return "", false
+}
+
+// schemaError is a leaf JSON Schema validation error.
+type schemaError struct {
+	keyword  string
+	instance string
+	message  string
+}
+
+// schemaErrors validates value and returns the leaf errors, ordered by location.
+func schemaErrors(schema *jsonschema.Schema, value interface{}) []schemaError {
+	err := schema.Validate(value)
+	if err == nil {
+		return nil
+	}
+	var validationErr *jsonschema.ValidationError
+	if !errors.As(err, &validationErr) {
+		return []schemaError{{message: err.Error()}}
+	}
+
+	var leaves []schemaError
+	var walk func(e *jsonschema.ValidationError)
+	walk = func(e *jsonschema.ValidationError) {
+		if len(e.Causes) == 0 {
+			leaves = append(leaves, schemaError{
+				keyword:  e.KeywordLocation,
+				instance: e.InstanceLocation,
+				message:  e.Message,
+			})
+			return
+		}
+		for _, cause := range e.Causes {
+			walk(cause)
+		}
+	}
+	walk(validationErr)
+
+	sort.SliceStable(leaves, func(i, j int) bool {
+		return leaves[i].instance < leaves[j].instance
+	})
+	return leaves
+}
+
+// normalize converts a Go value (for example one decoded from YAML) into the
+// generic JSON form the schema validator expects.
+func normalize(value interface{}) (interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("input is not representable as JSON: %w", err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var out interface{}
+	if err := decoder.Decode(&out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n]) + "..."
+}
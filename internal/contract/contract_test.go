@@ -0,0 +1,130 @@
+package contract
+
+import (
+	"testing"
+
+	"github.com/promptforge/promptforge/internal/ir"
+)
+
+func testIR() *ir.PromptIR {
+	return &ir.PromptIR{
+		Version:    ir.CurrentVersion,
+		SystemRole: "You triage refund requests.",
+		Rules: []ir.Rule{
+			{ID: RuleOutputJSON, Description: "Output must be valid JSON"},
+			{ID: RuleNoExplanations, Description: "Do not include explanations unless explicitly requested"},
+		},
+		InputSchema: ir.Schema{
+			Type: "object",
+			Properties: map[string]ir.Property{
+				"order_id": {Type: "string"},
+				"amount":   {Type: "number"},
+			},
+			Required: []string{"order_id"},
+		},
+		OutputSchema: ir.Schema{
+			Type: "object",
+			Properties: map[string]ir.Property{
+				"decision": {Type: "string", Enum: []interface{}{"refund", "deny"}},
+			},
+			Required: []string{"decision"},
+		},
+		FailureModes: []ir.FailureMode{
+			{ID: FailureInvalidInput, Condition: "Input does not match input_schema", Response: "Return error"},
+			{ID: FailureMissingRequired, Condition: "Required fields are missing", Response: "Return error"},
+		},
+	}
+}
+
+func newTestValidator(t *testing.T) *Validator {
+	t.Helper()
+	v, err := NewValidator(testIR())
+	if err != nil {
+		t.Fatalf("NewValidator() failed: %v", err)
+	}
+	return v
+}
+
+func TestValidateInput(t *testing.T) {
+	v := newTestValidator(t)
+
+	tests := []struct {
+		name   string
+		input  interface{}
+		wantID string
+	}{
+		{name: "valid", input: map[string]interface{}{"order_id": "A-1", "amount": 12}},
+		{name: "missing required", input: map[string]interface{}{"amount": 12}, wantID: FailureMissingRequired},
+		{name: "wrong type", input: map[string]interface{}{"order_id": "A-1", "amount": "twelve"}, wantID: FailureInvalidInput},
+		{name: "not an object", input: []interface{}{"A-1"}, wantID: FailureInvalidInput},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations := v.ValidateInput(tt.input)
+			if tt.wantID == "" {
+				if len(violations) != 0 {
+					t.Fatalf("Expected no violations, got %v", violations)
+				}
+				return
+			}
+			if len(violations) == 0 || violations[0].ID != tt.wantID {
+				t.Fatalf("Expected a %s violation, got %v", tt.wantID, violations)
+			}
+		})
+	}
+}
+
+func TestValidateOutput(t *testing.T) {
+	v := newTestValidator(t)
+
+	tests := []struct {
+		name            string
+		response        string
+		wantFailureMode string
+		wantIDs         []string
+	}{
+		{name: "valid", response: `{"decision": "refund"}`},
+		{name: "not JSON", response: "Sure, I will refund it.", wantIDs: []string{RuleOutputJSON}},
+		{name: "code fence", response: "```json\n{\"decision\": \"refund\"}\n```", wantIDs: []string{RuleOutputJSON}},
+		{name: "trailing explanation", response: `{"decision": "deny"} Hope this helps!`, wantIDs: []string{RuleNoExplanations}},
+		{name: "enum mismatch", response: `{"decision": "maybe"}`, wantIDs: []string{OutputSchemaID}},
+		{name: "missing field", response: `{}`, wantIDs: []string{OutputSchemaID}},
+		{name: "error response", response: `{"error": "missing-required"}`, wantFailureMode: FailureMissingRequired},
+		{name: "error object", response: `{"error": {"code": "invalid-input", "message": "amount must be a number"}}`, wantFailureMode: FailureInvalidInput},
+		{name: "undeclared failure mode", response: `{"error": "rate-limited"}`, wantFailureMode: "rate-limited", wantIDs: []string{UndeclaredFailureID}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := v.ValidateOutput([]byte(tt.response))
+			if out.FailureMode != tt.wantFailureMode {
+				t.Errorf("FailureMode = %q, want %q", out.FailureMode, tt.wantFailureMode)
+			}
+			if len(out.Violations) != len(tt.wantIDs) {
+				t.Fatalf("Expected violations %v, got %v", tt.wantIDs, out.Violations)
+			}
+			for i, id := range tt.wantIDs {
+				if out.Violations[i].ID != id {
+					t.Errorf("Violations[%d].ID = %q, want %q", i, out.Violations[i].ID, id)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateOutput_SchemaWithErrorProperty(t *testing.T) {
+	promptIR := testIR()
+	promptIR.OutputSchema.Properties["error"] = ir.Property{Type: "boolean"}
+
+	v, err := NewValidator(promptIR)
+	if err != nil {
+		t.Fatalf("NewValidator() failed: %v", err)
+	}
+
+	out := v.ValidateOutput([]byte(`{"error": "missing-required"}`))
+	if out.FailureMode != "" {
+		t.Errorf("Expected no failure mode when output_schema declares error, got %q", out.FailureMode)
+	}
+	if len(out.Violations) == 0 {
+		t.Error("Expected output_schema violations for a string error field")
+	}
+}
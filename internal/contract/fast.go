@@ -0,0 +1,351 @@
+package contract
+
+import (
+	"github.com/promptforge/promptforge/internal/ir"
+)
+
+// The fast path checks raw JSON against a contract schema without decoding it or
+// allocating. It only ever answers "certainly valid"; anything it cannot decide
+// (strings with escapes, non-string enums, fractional integers, very deep
+// nesting) reports false, and callers fall back to the full validator
+// for the authoritative answer and its violations.
+
+// maxFastDepth bounds recursion on deeply nested documents.
+const maxFastDepth = 64
+
+type fastNode struct {
+	kind string
+
+	properties map[string]*fastNode
+
+	// closed is set when additional properties are not allowed.
+	closed bool
+
+	// required maps required property names to their bit in a seen-mask; objects
+	// with more than 64 required properties are left to the full validator.
+	required     map[string]uint
+	requiredMask uint64
+
+	items *fastNode
+
+	// enum holds the allowed string values. unsupported is set when the node has
+	// an enum the fast path cannot compare.
+	enum        []string
+	unsupported bool
+}
+
+func newFastSchema(schema ir.Schema) *fastNode {
+	node := &fastNode{kind: schema.Type, closed: isFalse(schema.AdditionalProperties)}
+	node.setProperties(schema.Properties)
+	node.setRequired(schema.Required)
+	if schema.Items != nil {
+		node.items = newFastSchema(*schema.Items)
+	}
+	return node
+}
+
+func newFastProperty(property ir.Property) *fastNode {
+	node := &fastNode{kind: property.Type, closed: isFalse(property.AdditionalProperties)}
+	node.setProperties(property.Properties)
+	node.setRequired(property.Required)
+	if property.Items != nil {
+		node.items = newFastSchema(*property.Items)
+	}
+	for _, value := range property.Enum {
+		s, ok := value.(string)
+		if !ok {
+			node.unsupported = true
+			break
+		}
+		node.enum = append(node.enum, s)
+	}
+	return node
+}
+
+func isFalse(b *bool) bool {
+	return b != nil && !*b
+}
+
+func (n *fastNode) setProperties(properties map[string]ir.Property) {
+	if len(properties) == 0 {
+		return
+	}
+	n.properties = make(map[string]*fastNode, len(properties))
+	for name, property := range properties {
+		n.properties[name] = newFastProperty(property)
+	}
+}
+
+func (n *fastNode) setRequired(required []string) {
+	if len(required) > 64 {
+		n.unsupported = true
+		return
+	}
+	if len(required) == 0 {
+		return
+	}
+	n.required = make(map[string]uint, len(required))
+	for _, name := range required {
+		if _, ok := n.required[name]; ok {
+			continue
+		}
+		bit := uint(len(n.required))
+		n.required[name] = bit
+		n.requiredMask |= 1 << bit
+	}
+}
+
+// fastScanner walks a JSON document against a fastNode tree.
+type fastScanner struct {
+	data []byte
+
+	// rootErrorKey is set when an "error" key at the top level must send the
+	// document to the full validator (see Validator.errorConvention).
+	rootErrorKey bool
+}
+
+func (s *fastScanner) skipSpace(pos int) int {
+	for pos < len(s.data) {
+		switch s.data[pos] {
+		case ' ', '\t', '\n', '\r':
+			pos++
+		default:
+			return pos
+		}
+	}
+	return pos
+}
+
+// document reports whether data is exactly one JSON value satisfying node.
+func (s *fastScanner) document(node *fastNode) bool {
+	pos, ok := s.value(node, s.skipSpace(0), 0)
+	return ok && s.skipSpace(pos) == len(s.data)
+}
+
+// value checks the value at pos against node (nil accepts any value) and returns
+// the position after it.
+func (s *fastScanner) value(node *fastNode, pos int, depth int) (int, bool) {
+	if pos >= len(s.data) || depth > maxFastDepth {
+		return pos, false
+	}
+	if node != nil && node.unsupported {
+		return pos, false
+	}
+
+	kind := ""
+	if node != nil {
+		kind = node.kind
+	}
+
+	switch c := s.data[pos]; {
+	case c == '{':
+		if kind != "" && kind != "object" {
+			return pos, false
+		}
+		return s.object(node, pos, depth)
+	case c == '[':
+		if kind != "" && kind != "array" {
+			return pos, false
+		}
+		var items *fastNode
+		if node != nil {
+			items = node.items
+		}
+		return s.array(items, pos, depth)
+	case c == '"':
+		if kind != "" && kind != "string" {
+			return pos, false
+		}
+		start, end, next, ok := s.str(pos)
+		if !ok {
+			return pos, false
+		}
+		if node != nil && len(node.enum) > 0 && !node.hasEnum(s.data[start:end]) {
+			return pos, false
+		}
+		return next, true
+	case c == 't' || c == 'f':
+		if kind != "" && kind != "boolean" {
+			return pos, false
+		}
+		if node != nil && len(node.enum) > 0 {
+			return pos, false
+		}
+		if c == 't' {
+			return s.literal(pos, "true")
+		}
+		return s.literal(pos, "false")
+	case c == 'n':
+		if kind != "" && kind != "null" {
+			return pos, false
+		}
+		return s.literal(pos, "null")
+	case c == '-' || (c >= '0' && c <= '9'):
+		if kind != "" && kind != "number" && kind != "integer" {
+			return pos, false
+		}
+		if node != nil && len(node.enum) > 0 {
+			return pos, false
+		}
+		next, fractional, ok := s.number(pos)
+		if !ok || (kind == "integer" && fractional) {
+			return pos, false
+		}
+		return next, true
+	}
+	return pos, false
+}
+
+func (n *fastNode) hasEnum(value []byte) bool {
+	for _, allowed := range n.enum {
+		if string(value) == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *fastScanner) object(node *fastNode, pos int, depth int) (int, bool) {
+	var seen uint64
+	pos = s.skipSpace(pos + 1)
+	if pos < len(s.data) && s.data[pos] == '}' {
+		return pos + 1, node == nil || node.requiredMask == 0
+	}
+	for {
+		start, end, next, ok := s.str(pos)
+		if !ok {
+			return pos, false
+		}
+		key := s.data[start:end]
+		if depth == 0 && s.rootErrorKey && string(key) == "error" {
+			return pos, false
+		}
+
+		pos = s.skipSpace(next)
+		if pos >= len(s.data) || s.data[pos] != ':' {
+			return pos, false
+		}
+
+		var child *fastNode
+		if node != nil {
+			child = node.properties[string(key)]
+			if child == nil && node.closed {
+				return pos, false
+			}
+			if bit, ok := node.required[string(key)]; ok {
+				seen |= 1 << bit
+			}
+		}
+		pos, ok = s.value(child, s.skipSpace(pos+1), depth+1)
+		if !ok {
+			return pos, false
+		}
+
+		pos = s.skipSpace(pos)
+		if pos >= len(s.data) {
+			return pos, false
+		}
+		switch s.data[pos] {
+		case ',':
+			pos = s.skipSpace(pos + 1)
+		case '}':
+			return pos + 1, node == nil || seen == node.requiredMask
+		default:
+			return pos, false
+		}
+	}
+}
+
+func (s *fastScanner) array(items *fastNode, pos int, depth int) (int, bool) {
+	pos = s.skipSpace(pos + 1)
+	if pos < len(s.data) && s.data[pos] == ']' {
+		return pos + 1, true
+	}
+	for {
+		var ok bool
+		pos, ok = s.value(items, pos, depth+1)
+		if !ok {
+			return pos, false
+		}
+		pos = s.skipSpace(pos)
+		if pos >= len(s.data) {
+			return pos, false
+		}
+		switch s.data[pos] {
+		case ',':
+			pos = s.skipSpace(pos + 1)
+		case ']':
+			return pos + 1, true
+		default:
+			return pos, false
+		}
+	}
+}
+
+// str scans the string starting at pos and returns the bounds of its contents
+// and the position after it. Strings with escapes are left to the full validator.
+func (s *fastScanner) str(pos int) (start, end, next int, ok bool) {
+	if pos >= len(s.data) || s.data[pos] != '"' {
+		return 0, 0, pos, false
+	}
+	start = pos + 1
+	for i := start; i < len(s.data); i++ {
+		switch c := s.data[i]; {
+		case c == '"':
+			return start, i, i + 1, true
+		case c == '\\' || c < 0x20:
+			return 0, 0, pos, false
+		}
+	}
+	return 0, 0, pos, false
+}
+
+func (s *fastScanner) literal(pos int, word string) (int, bool) {
+	if len(s.data)-pos < len(word) || string(s.data[pos:pos+len(word)]) != word {
+		return pos, false
+	}
+	return pos + len(word), true
+}
+
+// number scans a JSON number and reports whether it has a fraction or exponent.
+func (s *fastScanner) number(pos int) (int, bool, bool) {
+	i := pos
+	if s.data[i] == '-' {
+		i++
+	}
+
+	var ok bool
+	if i < len(s.data) && s.data[i] == '0' {
+		i++
+	} else if i, ok = s.digits(i); !ok {
+		return pos, false, false
+	}
+
+	fractional := false
+	if i < len(s.data) && s.data[i] == '.' {
+		fractional = true
+		if i, ok = s.digits(i + 1); !ok {
+			return pos, false, false
+		}
+	}
+	if i < len(s.data) && (s.data[i] == 'e' || s.data[i] == 'E') {
+		fractional = true
+		i++
+		if i < len(s.data) && (s.data[i] == '+' || s.data[i] == '-') {
+			i++
+		}
+		if i, ok = s.digits(i); !ok {
+			return pos, false, false
+		}
+	}
+	return i, fractional, true
+}
+
+// digits scans one or more decimal digits.
+func (s *fastScanner) digits(pos int) (int, bool) {
+	i := pos
+	for i < len(s.data) && s.data[i] >= '0' && s.data[i] <= '9' {
+		i++
+	}
+	return i, i > pos
+}
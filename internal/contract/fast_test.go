@@ -0,0 +1,145 @@
+package contract
+
+import (
+	"testing"
+
+	"github.com/promptforge/promptforge/internal/ir"
+)
+
+func TestOutputValid_AgreesWithValidateOutput(t *testing.T) {
+	v := newTestValidator(t)
+
+	tests := []struct {
+		response string
+		fast     bool
+	}{
+		{response: `{"decision": "refund"}`, fast: true},
+		{response: ` {"decision":"deny","note":{"a":[1,2.5,-3e2,true,null,"x"]}} `, fast: true},
+		{response: `{"decision": "maybe"}`},
+		{response: `{}`},
+		{response: `[]`},
+		{response: `{"decision": "refund"} thanks`},
+		{response: `{"decision": "refund"`},
+		{response: `{"decision": "refund",}`},
+		{response: "```json\n{\"decision\": \"refund\"}\n```"},
+		{response: `{"decision": "refund\u0021"}`},
+		{response: `{"error": "missing-required", "decision": "deny"}`},
+		{response: `{"decision": "deny", "note": 01}`},
+		{response: `{"decision": "deny", "note": tru}`},
+		{response: ""},
+	}
+	for _, tt := range tests {
+		got := v.OutputValid([]byte(tt.response))
+		if got != tt.fast {
+			t.Errorf("OutputValid(%s) = %v, want %v", tt.response, got, tt.fast)
+		}
+		if got {
+			out := v.ValidateOutput([]byte(tt.response))
+			if len(out.Violations) > 0 || out.FailureMode != "" {
+				t.Errorf("OutputValid(%s) accepted a response ValidateOutput rejects: %v", tt.response, out.Violations)
+			}
+		}
+	}
+}
+
+func TestInputValid_AgreesWithValidateInputJSON(t *testing.T) {
+	v := newTestValidator(t)
+
+	tests := []struct {
+		input string
+		fast  bool
+	}{
+		{input: `{"order_id": "A-1", "amount": 12.5}`, fast: true},
+		{input: `{"order_id": "A-1"}`, fast: true},
+		{input: `{"amount": 12}`},
+		{input: `{"order_id": 7}`},
+		{input: `{"order_id": "A-1", "amount": "12"}`},
+		{input: `"A-1"`},
+	}
+	for _, tt := range tests {
+		got := v.InputValid([]byte(tt.input))
+		if got != tt.fast {
+			t.Errorf("InputValid(%s) = %v, want %v", tt.input, got, tt.fast)
+		}
+		violations := v.ValidateInputJSON([]byte(tt.input))
+		if got && len(violations) > 0 {
+			t.Errorf("InputValid(%s) accepted an input ValidateInputJSON rejects: %v", tt.input, violations)
+		}
+		if !got && len(violations) == 0 {
+			t.Errorf("ValidateInputJSON(%s) found no violations", tt.input)
+		}
+	}
+}
+
+func TestFastPath_DoesNotAllocate(t *testing.T) {
+	v := newTestValidator(t)
+	input := []byte(`{"order_id": "A-1", "amount": 12.5}`)
+	response := []byte(`{"decision": "refund", "items": [{"sku": "X", "qty": 2}]}`)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		if !v.InputValid(input) || !v.OutputValid(response) {
+			t.Fatal("expected valid payloads")
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("fast path allocated %.0f times per run, want 0", allocs)
+	}
+}
+
+func BenchmarkOutputValid(b *testing.B) {
+	v, err := NewValidator(testIR())
+	if err != nil {
+		b.Fatalf("NewValidator() failed: %v", err)
+	}
+	response := []byte(`{"decision": "refund", "items": [{"sku": "X", "qty": 2}]}`)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		v.OutputValid(response)
+	}
+}
+
+func TestOutputValid_ClosedObject(t *testing.T) {
+	closed := false
+	promptIR := testIR()
+	promptIR.OutputSchema.AdditionalProperties = &closed
+	v, err := NewValidator(promptIR)
+	if err != nil {
+		t.Fatalf("NewValidator() failed: %v", err)
+	}
+
+	if !v.OutputValid([]byte(`{"decision": "deny"}`)) {
+		t.Error("Expected a response with only declared properties to pass the fast path")
+	}
+	response := []byte(`{"decision": "deny", "reason": "late"}`)
+	if v.OutputValid(response) {
+		t.Error("Expected an undeclared property to fail the fast path")
+	}
+	if out := v.ValidateOutput(response); len(out.Violations) == 0 || out.Violations[0].ID != OutputSchemaID {
+		t.Errorf("Expected an output-schema violation, got %v", out.Violations)
+	}
+}
+
+func TestOutputValid_NestedRequired(t *testing.T) {
+	promptIR := testIR()
+	promptIR.OutputSchema.Properties["refund"] = ir.Property{
+		Type:       "object",
+		Properties: map[string]ir.Property{"amount": {Type: "number"}},
+		Required:   []string{"amount"},
+	}
+	v, err := NewValidator(promptIR)
+	if err != nil {
+		t.Fatalf("NewValidator() failed: %v", err)
+	}
+
+	if !v.OutputValid([]byte(`{"decision": "refund", "refund": {"amount": 12.5}}`)) {
+		t.Error("Expected a nested object with its required property to pass the fast path")
+	}
+	response := []byte(`{"decision": "refund", "refund": {}}`)
+	if v.OutputValid(response) {
+		t.Error("Expected a nested object missing a required property to fail the fast path")
+	}
+	if out := v.ValidateOutput(response); len(out.Violations) == 0 {
+		t.Error("Expected ValidateOutput to reject a nested object missing a required property")
+	}
+}
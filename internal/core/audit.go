@@ -2,85 +2,178 @@ package core
 
 import (
 	"bytes"
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 
+	"github.com/promptforge/promptforge"
 	"github.com/promptforge/promptforge/internal/compiler"
 	"github.com/promptforge/promptforge/internal/ir"
+	"github.com/promptforge/promptforge/internal/policy"
+	"github.com/promptforge/promptforge/internal/scan"
 )
 
+// Audit issue codes. Findings of the prompt text scan keep their PF3xx codes.
+const (
+	AuditIRInvalid         = "PF400"
+	AuditIRVersion         = "PF401"
+	AuditSchemaMissing     = "PF402"
+	AuditSchemaDrift       = "PF403"
+	AuditIncludeMissing    = "PF404"
+	AuditIncludeUnreadable = "PF405"
+	AuditIncludeChanged    = "PF406"
+)
+
+// Artifacts audit issues refer to, relative to the project directory.
+const (
+	irArtifact     = "prompt.ir.json"
+	schemaArtifact = "prompt.ir.schema.json"
+)
+
+// AuditIssue is a problem found by the audit. Artifact is the file it concerns,
+// relative to the project directory, and Pointer the JSON pointer of the value
+// in that file; an empty Pointer refers to the whole file.
 type AuditIssue struct {
-	Severity string
-	Message  string
+	Code     string `json:"code"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	Artifact string `json:"artifact"`
+	Pointer  string `json:"pointer,omitempty"`
+
+	// Policy is the ID of the violated policy for PF5xx issues.
+	Policy string `json:"policy,omitempty"`
 }
 
-// AuditProject validates prompt.ir.json and schema integrity.
-func AuditProject(projectDir string) ([]AuditIssue, error) {
-	if projectDir == "" {
-		return nil, fmt.Errorf("project directory cannot be empty")
+// AuditProject validates prompt.ir.json and schema integrity, scans the prompt
+// text for secrets, personal data and prompt-injection phrasing, and checks the
+// IR against the given policy packs.
+func AuditProject(projectDir string, packs ...*policy.Pack) ([]AuditIssue, error) {
+	if err := checkProjectDir(projectDir); err != nil {
+		return nil, err
 	}
 
-	if _, err := os.Stat(projectDir); os.IsNotExist(err) {
-		return nil, fmt.Errorf("project directory does not exist: %s", projectDir)
+	promptIR, err := readIRFile(filepath.Join(projectDir, "prompt.ir.json"))
+	if err != nil {
+		return nil, err
 	}
 
-	var issues []AuditIssue
+	issues := auditContract(promptIR)
 
-	irPath := filepath.Join(projectDir, "prompt.ir.json")
-	data, err := os.ReadFile(irPath)
+	schemaPath := filepath.Join(projectDir, "prompt.ir.schema.json")
+	schemaOnDisk, err := os.ReadFile(schemaPath)
 	if err != nil {
-		if os.IsPermission(err) {
-			return nil, fmt.Errorf("permission denied: cannot read prompt.ir.json at %s", irPath)
+		if !os.IsNotExist(err) {
+			return nil, promptforge.ReadError(err, "prompt.ir.schema.json", schemaPath)
+		}
+		issues = append(issues, AuditIssue{
+			Code:     AuditSchemaMissing,
+			Severity: "warn",
+			Message:  "prompt.ir.schema.json is missing",
+			Artifact: schemaArtifact,
+		})
+	} else {
+		currentSchema, err := ir.PromptIRSchemaJSON()
+		if err != nil {
+			return nil, promptforge.Wrap(err, promptforge.CodeInternal, "failed to build current schema")
+		}
+		if !bytes.Equal(schemaOnDisk, currentSchema) {
+			issues = append(issues, AuditIssue{
+				Code:     AuditSchemaDrift,
+				Severity: "warn",
+				Message:  "prompt.ir.schema.json does not match the current schema. Run 'promptforge compile' to refresh.",
+				Artifact: schemaArtifact,
+			})
 		}
-		return nil, fmt.Errorf("failed to read prompt.ir.json at %s: %w", irPath, err)
 	}
 
-	var promptIR ir.PromptIR
-	if err := json.Unmarshal(data, &promptIR); err != nil {
-		return nil, fmt.Errorf("failed to parse prompt.ir.json: %w", err)
+	issues = append(issues, auditIncludes(projectDir, promptIR.Includes)...)
+
+	config, err := LoadLintConfig(projectDir)
+	if err != nil {
+		return nil, err
 	}
+	scanIssues, err := auditScan(promptIR, config.Scan)
+	if err != nil {
+		return nil, err
+	}
+	issues = append(issues, scanIssues...)
+	issues = append(issues, auditPolicies(promptIR, packs)...)
+
+	return issues, nil
+}
+
+// AuditIR runs the audit checks that only need the IR itself: IR validation, the
+// IR version, and the secret, PII and prompt-injection scan. Checks against files
+// in a project (schema sync, included files) are left to AuditProject.
+func AuditIR(promptIR *ir.PromptIR, config scan.Config) ([]AuditIssue, error) {
+	issues := auditContract(promptIR)
+	scanIssues, err := auditScan(promptIR, config)
+	if err != nil {
+		return nil, err
+	}
+	return append(issues, scanIssues...), nil
+}
 
-	if err := compiler.ValidateIR(&promptIR); err != nil {
+// auditContract checks that promptIR is valid and at the current IR version.
+func auditContract(promptIR *ir.PromptIR) []AuditIssue {
+	var issues []AuditIssue
+	if err := compiler.ValidateIR(promptIR); err != nil {
 		issues = append(issues, AuditIssue{
+			Code:     AuditIRInvalid,
 			Severity: "error",
 			Message:  fmt.Sprintf("IR validation failed: %s", err.Error()),
+			Artifact: irArtifact,
 		})
 	}
 
 	if promptIR.Version != ir.CurrentVersion {
 		issues = append(issues, AuditIssue{
+			Code:     AuditIRVersion,
 			Severity: "error",
 			Message:  fmt.Sprintf("IR version %s does not match current %s. Run 'promptforge migrate'.", promptIR.Version, ir.CurrentVersion),
+			Artifact: irArtifact,
+			Pointer:  "/version",
 		})
 	}
+	return issues
+}
 
-	schemaPath := filepath.Join(projectDir, "prompt.ir.schema.json")
-	schemaOnDisk, err := os.ReadFile(schemaPath)
-	if err != nil {
-		if os.IsNotExist(err) {
+// auditIncludes checks that files included into plan.md are unchanged since compile.
+func auditIncludes(projectDir string, includes []ir.IncludedFile) []AuditIssue {
+	var issues []AuditIssue
+	for i, include := range includes {
+		path := filepath.Join(projectDir, filepath.FromSlash(include.Path))
+		pointer := fmt.Sprintf("/includes/%d", i)
+		digest, err := fileDigest(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				issues = append(issues, AuditIssue{
+					Code:     AuditIncludeMissing,
+					Severity: "warn",
+					Message:  fmt.Sprintf("included file %s no longer exists. Run 'promptforge compile' to refresh.", include.Path),
+					Artifact: irArtifact,
+					Pointer:  pointer,
+				})
+				continue
+			}
 			issues = append(issues, AuditIssue{
+				Code:     AuditIncludeUnreadable,
 				Severity: "warn",
-				Message:  "prompt.ir.schema.json is missing",
+				Message:  fmt.Sprintf("cannot read included file %s: %v", include.Path, err),
+				Artifact: irArtifact,
+				Pointer:  pointer,
 			})
-		} else if os.IsPermission(err) {
-			return nil, fmt.Errorf("permission denied: cannot read prompt.ir.schema.json at %s", schemaPath)
-		} else {
-			return nil, fmt.Errorf("failed to read prompt.ir.schema.json at %s: %w", schemaPath, err)
+			continue
 		}
-	} else {
-		currentSchema, err := ir.PromptIRSchemaJSON()
-		if err != nil {
-			return nil, fmt.Errorf("failed to build current schema: %w", err)
-		}
-		if !bytes.Equal(schemaOnDisk, currentSchema) {
+		if digest != include.SHA256 {
 			issues = append(issues, AuditIssue{
+				Code:     AuditIncludeChanged,
 				Severity: "warn",
-				Message:  "prompt.ir.schema.json does not match the current schema. Run 'promptforge compile' to refresh.",
+				Message:  fmt.Sprintf("included file %s has changed since compile. Run 'promptforge compile' to refresh.", include.Path),
+				Artifact: irArtifact,
+				Pointer:  pointer,
 			})
 		}
 	}
-
-	return issues, nil
+	return issues
 }
@@ -5,8 +5,11 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/promptforge/promptforge"
+	"github.com/promptforge/promptforge/internal/atomicfile"
 	"github.com/promptforge/promptforge/internal/compiler"
 	"github.com/promptforge/promptforge/internal/ir"
+	"github.com/promptforge/promptforge/internal/parser"
 )
 
 // InitializeProject creates a new PromptForge project by creating a plan.md file.
@@ -28,62 +31,71 @@ func InitializeProject(projectDir string, description string) error {
 
 // InitializeProjectWithTemplate creates a new PromptForge project with an optional template.
 func InitializeProjectWithTemplate(projectDir string, description string, templateName string) error {
+	return InitializeProjectWithParams(projectDir, description, templateName, nil)
+}
+
+// InitializeProjectWithParams creates a new PromptForge project from a template,
+// filling the template's {{parameters}} from params. It fails with a
+// *templates.MissingParamsError when required parameters are not provided.
+func InitializeProjectWithParams(projectDir string, description string, templateName string, params map[string]string) error {
+	return InitializePlan(projectDir, PlanPath(projectDir), description, templateName, params)
+}
+
+// InitializePlan is InitializeProjectWithParams for a plan at planPath instead of
+// promptforge/plan.md. The plan's directory is created if needed.
+func InitializePlan(projectDir, planPath string, description string, templateName string, params map[string]string) error {
+	if templateName == "" && len(params) > 0 {
+		return promptforge.Errorf(promptforge.CodeInvalidArgument, "template parameters require a template (use --template <name>)")
+	}
+
 	// Validate project directory exists and is accessible
 	if projectDir == "" {
-		return fmt.Errorf("project directory cannot be empty")
+		return promptforge.Errorf(promptforge.CodeInvalidArgument, "project directory cannot be empty")
 	}
 
 	// Check if project directory exists and is writable
 	info, err := os.Stat(projectDir)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return fmt.Errorf("project directory does not exist: %s", projectDir)
+			return promptforge.Errorf(promptforge.CodeNotFound, "project directory does not exist: %s", projectDir)
 		}
-		return fmt.Errorf("cannot access project directory %s: %w", projectDir, err)
+		return promptforge.Wrap(err, promptforge.IOCode(err), "cannot access project directory %s", projectDir)
 	}
 	if !info.IsDir() {
-		return fmt.Errorf("project path is not a directory: %s", projectDir)
+		return promptforge.Errorf(promptforge.CodeInvalidArgument, "project path is not a directory: %s", projectDir)
 	}
 
 	// Check write permissions
 	testFile := filepath.Join(projectDir, ".promptforge_test")
 	if err := os.WriteFile(testFile, []byte("test"), 0644); err != nil {
 		if os.IsPermission(err) {
-			return fmt.Errorf("permission denied: cannot write to project directory %s", projectDir)
+			return promptforge.Errorf(promptforge.CodePermissionDenied, "permission denied: cannot write to project directory %s", projectDir)
 		}
-		return fmt.Errorf("cannot write to project directory %s: %w", projectDir, err)
+		return promptforge.Wrap(err, promptforge.IOCode(err), "cannot write to project directory %s", projectDir)
 	}
 	os.Remove(testFile) // Clean up test file
 
-	// Create the promptforge directory if it doesn't exist
-	promptforgeDir := filepath.Join(projectDir, "promptforge")
-	if err := os.MkdirAll(promptforgeDir, 0755); err != nil {
+	// Create the plan directory (promptforge/ by default) if it doesn't exist
+	planDir := filepath.Dir(planPath)
+	if err := os.MkdirAll(planDir, 0755); err != nil {
 		if os.IsPermission(err) {
-			return fmt.Errorf("permission denied: cannot create promptforge directory in %s", projectDir)
+			return promptforge.Errorf(promptforge.CodePermissionDenied, "permission denied: cannot create directory %s", planDir)
 		}
-		return fmt.Errorf("failed to create promptforge directory: %w", err)
+		return promptforge.Wrap(err, promptforge.IOCode(err), "failed to create directory %s", planDir)
 	}
 
 	// Check if plan.md already exists
-	planPath := filepath.Join(promptforgeDir, "plan.md")
 	if _, err := os.Stat(planPath); err == nil {
-		return fmt.Errorf("plan.md already exists at %s", planPath)
+		return promptforge.Errorf(promptforge.CodeAlreadyExists, "plan.md already exists at %s", planPath)
 	}
 
-	planContent, err := buildPlanContent(description, templateName)
+	planContent, err := buildPlanContent(projectDir, description, templateName, params)
 	if err != nil {
 		return err
 	}
 
 	if err := os.WriteFile(planPath, []byte(planContent), 0644); err != nil {
-		if os.IsPermission(err) {
-			return fmt.Errorf("permission denied: cannot write plan.md to %s", planPath)
-		}
-		// Check for disk full error (ENOSPC on Unix, but Windows may return different errors)
-		if err.Error() == "no space left on device" || err.Error() == "not enough space" {
-			return fmt.Errorf("disk full: cannot write plan.md. Please free up disk space")
-		}
-		return fmt.Errorf("failed to create plan.md at %s: %w", planPath, err)
+		return promptforge.WriteError(err, "plan.md", planPath)
 	}
 
 	return nil
@@ -106,126 +118,153 @@ func InitializeProjectWithTemplate(projectDir string, description string, templa
 //   - IR validation fails
 //   - output file cannot be written
 func CompileProject(projectDir, outputPath string) (*ir.PromptIR, error) {
-	return compileProject(projectDir, outputPath, "")
+	return CompilePlan(projectDir, PlanPath(projectDir), outputPath, "")
 }
 
 // CompileProjectWithExplain compiles plan.md and writes explain output alongside IR.
 func CompileProjectWithExplain(projectDir, outputPath, explainPath string) (*ir.PromptIR, error) {
 	if explainPath == "" {
-		return nil, fmt.Errorf("explain output path cannot be empty")
+		return nil, promptforge.Errorf(promptforge.CodeInvalidArgument, "explain output path cannot be empty")
 	}
-	return compileProject(projectDir, outputPath, explainPath)
+	return CompilePlan(projectDir, PlanPath(projectDir), outputPath, explainPath)
 }
 
-func compileProject(projectDir, outputPath, explainPath string) (*ir.PromptIR, error) {
+// CompilePlan compiles the plan at planPath instead of promptforge/plan.md. The
+// explain report is written to explainPath unless it is empty.
+func CompilePlan(projectDir, planPath, outputPath, explainPath string) (*ir.PromptIR, error) {
 	// Validate project directory
-	if projectDir == "" {
-		return nil, fmt.Errorf("project directory cannot be empty")
+	if err := checkProjectDir(projectDir); err != nil {
+		return nil, err
 	}
 
-	// Check if project directory exists
-	if _, err := os.Stat(projectDir); os.IsNotExist(err) {
-		return nil, fmt.Errorf("project directory does not exist: %s", projectDir)
+	// Serialize with other compiles of this project, such as compile on save
+	lock, err := lockProject(projectDir)
+	if err != nil {
+		return nil, err
 	}
+	defer lock.Release()
 
 	// Check if plan.md exists - fail loudly if it doesn't
-	planPath := filepath.Join(projectDir, "promptforge", "plan.md")
 	planInfo, err := os.Stat(planPath)
 	if os.IsNotExist(err) {
-		return nil, fmt.Errorf("plan.md not found at %s. Run 'promptforge init' first", planPath)
+		return nil, errPlanNotFound(planPath)
 	}
 	if err != nil {
-		return nil, fmt.Errorf("cannot access plan.md at %s: %w", planPath, err)
+		return nil, promptforge.ReadError(err, "plan.md", planPath)
 	}
 
 	// Check if plan.md is readable
 	if planInfo.Size() == 0 {
-		return nil, fmt.Errorf("plan.md is empty at %s", planPath)
+		return nil, errPlanEmpty(planPath)
 	}
 
-	// Read plan.md to verify it exists and is readable
-	planContent, err := os.ReadFile(planPath)
+	// Read plan.md, expand include directives and merge any base plan
+	source, err := loadPlanSource(planPath)
 	if err != nil {
-		if os.IsPermission(err) {
-			return nil, fmt.Errorf("permission denied: cannot read plan.md at %s", planPath)
-		}
-		return nil, fmt.Errorf("failed to read plan.md at %s: %w", planPath, err)
+		return nil, err
 	}
-
-	if len(planContent) == 0 {
-		return nil, fmt.Errorf("plan.md is empty at %s", planPath)
+	if err := source.Err(); err != nil {
+		return nil, err
 	}
 
-	// Compile to IR using hardcoded, conservative mapping
-	// planContent is read but not interpreted - compiler generates deterministic IR
-	var (
-		irResult *ir.PromptIR
-		report   *compiler.ExplainReport
-	)
-	if explainPath != "" {
-		irResult, report, err = compiler.CompileWithExplain(planContent)
-		if err != nil {
-			return nil, fmt.Errorf("compilation failed: %w", err)
-		}
-	} else {
-		irResult, err = compiler.Compile(planContent)
-		if err != nil {
-			return nil, fmt.Errorf("compilation failed: %w", err)
-		}
+	irResult, report, err := compileSource(projectDir, planPath, source, explainPath != "")
+	if err != nil {
+		return nil, err
 	}
 
 	// Validate output directory exists and is writable
 	outputDir := filepath.Dir(outputPath)
 	if outputDir != "." && outputDir != "" {
 		if _, err := os.Stat(outputDir); os.IsNotExist(err) {
-			return nil, fmt.Errorf("output directory does not exist: %s", outputDir)
+			return nil, promptforge.Errorf(promptforge.CodeNotFound, "output directory does not exist: %s", outputDir)
 		}
 	}
 
-	// Check write permissions for output directory
-	testFile := filepath.Join(outputDir, ".promptforge_test")
-	if err := os.WriteFile(testFile, []byte("test"), 0644); err != nil {
-		if os.IsPermission(err) {
-			return nil, fmt.Errorf("permission denied: cannot write to output directory %s", outputDir)
-		}
-		return nil, fmt.Errorf("cannot write to output directory %s: %w", outputDir, err)
+	// Write all artifacts together: a failure leaves the previous ones in place
+	// instead of an IR that disagrees with its schema or explain report
+	irData, err := compiler.MarshalIR(irResult)
+	if err != nil {
+		return nil, err
+	}
+	schemaData, err := ir.PromptIRSchemaJSON()
+	if err != nil {
+		return nil, promptforge.Wrap(err, promptforge.CodeInternal, "failed to marshal IR schema")
 	}
-	os.Remove(testFile) // Clean up test file
 
-	// Write IR to JSON
-	if err := compiler.WriteIR(irResult, outputPath); err != nil {
-		if os.IsPermission(err) {
-			return nil, fmt.Errorf("permission denied: cannot write prompt.ir.json to %s", outputPath)
-		}
-		// Check for disk full error
-		if err.Error() == "no space left on device" || err.Error() == "not enough space" {
-			return nil, fmt.Errorf("disk full: cannot write prompt.ir.json. Please free up disk space")
+	var txn atomicfile.Transaction
+	txn.Add(outputPath, "prompt.ir.json", irData, 0644)
+	txn.Add(filepath.Join(outputDir, "prompt.ir.schema.json"), "prompt.ir.schema.json", schemaData, 0644)
+	if explainPath != "" {
+		explainData, err := compiler.MarshalExplainReport(report)
+		if err != nil {
+			return nil, err
 		}
-		return nil, fmt.Errorf("failed to write prompt.ir.json to %s: %w", outputPath, err)
+		txn.Add(explainPath, "prompt.ir.explain.json", explainData, 0644)
+	}
+	if err := txn.Commit(); err != nil {
+		return nil, err
 	}
 
-	schemaPath := filepath.Join(outputDir, "prompt.ir.schema.json")
-	if err := compiler.WriteIRSchema(schemaPath); err != nil {
-		if os.IsPermission(err) {
-			return nil, fmt.Errorf("permission denied: cannot write prompt.ir.schema.json to %s", schemaPath)
-		}
-		if err.Error() == "no space left on device" || err.Error() == "not enough space" {
-			return nil, fmt.Errorf("disk full: cannot write prompt.ir.schema.json. Please free up disk space")
-		}
-		return nil, fmt.Errorf("failed to write prompt.ir.schema.json to %s: %w", schemaPath, err)
+	return irResult, nil
+}
+
+// CompilePlanContent compiles plan content that is not read from planPath, such
+// as standard input, without writing any artifacts. A relative planPath is taken
+// relative to projectDir; it need not exist, but includes and the base plan
+// resolve against its directory and errors in the plan point at it. The explain
+// report is nil unless explain is set.
+func CompilePlanContent(projectDir, planPath string, content []byte, explain bool) (*ir.PromptIR, *compiler.ExplainReport, error) {
+	if err := checkProjectDir(projectDir); err != nil {
+		return nil, nil, err
+	}
+	if len(content) == 0 {
+		return nil, nil, errPlanEmpty(planPath)
 	}
 
-	if explainPath != "" {
-		if err := compiler.WriteExplainReport(report, explainPath); err != nil {
-			if os.IsPermission(err) {
-				return nil, fmt.Errorf("permission denied: cannot write prompt.ir.explain.json to %s", explainPath)
-			}
-			if err.Error() == "no space left on device" || err.Error() == "not enough space" {
-				return nil, fmt.Errorf("disk full: cannot write prompt.ir.explain.json. Please free up disk space")
-			}
-			return nil, fmt.Errorf("failed to write prompt.ir.explain.json to %s: %w", explainPath, err)
+	path := contentPath(projectDir, planPath)
+	source := parser.LoadPlanContent(path, content, compiler.ItemIDs)
+	if err := source.Err(); err != nil {
+		return nil, nil, relabelError(err, path, planPath)
+	}
+
+	irResult, report, err := compileSource(projectDir, path, source, explain)
+	if err != nil {
+		return nil, nil, relabelError(err, path, planPath)
+	}
+	return irResult, report, nil
+}
+
+// compileSource compiles a loaded plan and records the files it included.
+func compileSource(projectDir, planPath string, source *parser.Source, explain bool) (*ir.PromptIR, *compiler.ExplainReport, error) {
+	planContent := source.Content
+	if len(planContent) == 0 {
+		return nil, nil, errPlanEmpty(planPath)
+	}
+
+	// Compile to IR using hardcoded, conservative mapping
+	// planContent is read but not interpreted - compiler generates deterministic IR
+	var (
+		irResult *ir.PromptIR
+		report   *compiler.ExplainReport
+		err      error
+	)
+	if explain {
+		irResult, report, err = compiler.CompileWithExplain(planContent)
+		if err != nil {
+			return nil, nil, fmt.Errorf("compilation failed: %w", locateError(err, source))
+		}
+		remapExplainSources(report, projectDir, source)
+	} else {
+		irResult, err = compiler.Compile(planContent)
+		if err != nil {
+			return nil, nil, fmt.Errorf("compilation failed: %w", locateError(err, source))
 		}
 	}
 
-	return irResult, nil
+	// Record included files so audit can detect changes after compilation
+	irResult.Includes, err = includedFiles(projectDir, source)
+	if err != nil {
+		return nil, nil, err
+	}
+	return irResult, report, nil
 }
@@ -165,7 +165,7 @@ func TestLintProject_Success(t *testing.T) {
 A clear goal statement for linting tests.
 
 ## Constraints
-- Be strict
+- Must reply in English
 
 ## Out of Scope
 - Handle payments
@@ -0,0 +1,65 @@
+package core
+
+import (
+	"path/filepath"
+
+	"github.com/promptforge/promptforge"
+	"github.com/promptforge/promptforge/internal/codegen"
+	"github.com/promptforge/promptforge/internal/ir"
+	"github.com/promptforge/promptforge/internal/render"
+)
+
+// EmitProject produces the artifact for target from prompt.ir.json in projectDir.
+// See Emit.
+func EmitProject(projectDir, target string, opts codegen.Options, values map[string]string) ([]byte, error) {
+	if err := checkProjectDir(projectDir); err != nil {
+		return nil, err
+	}
+
+	return EmitFile(filepath.Join(projectDir, "prompt.ir.json"), target, opts, values)
+}
+
+// EmitFile produces the artifact for target from the IR file at irPath.
+func EmitFile(irPath, target string, opts codegen.Options, values map[string]string) ([]byte, error) {
+	promptIR, err := readIRFile(irPath)
+	if err != nil {
+		return nil, err
+	}
+	return emit(promptIR, target, opts, values)
+}
+
+// Emit produces the artifact for target from IR data read from name, such as
+// standard input. With values set, variables are rendered into the prompt
+// first; otherwise its {{slots}} are left for the caller to fill.
+func Emit(name string, data []byte, target string, opts codegen.Options, values map[string]string) ([]byte, error) {
+	if len(data) == 0 {
+		return nil, promptforge.Errorf(promptforge.CodeIRParse, "%s is empty", name).
+			WithHint("Pipe the output of 'promptforge compile' into emit")
+	}
+
+	promptIR, err := ParseIR(name, data)
+	if err != nil {
+		return nil, err
+	}
+	return emit(promptIR, target, opts, values)
+}
+
+func emit(promptIR *ir.PromptIR, target string, opts codegen.Options, values map[string]string) ([]byte, error) {
+	if _, err := migrateToCurrent(promptIR); err != nil {
+		return nil, err
+	}
+
+	if len(values) > 0 {
+		rendered, err := render.Render(promptIR, values)
+		if err != nil {
+			return nil, promptforge.Wrap(err, promptforge.CodeVariable, "render failed")
+		}
+		promptIR = rendered
+	}
+
+	data, err := codegen.Emit(promptIR, target, opts)
+	if err != nil {
+		return nil, promptforge.Wrap(err, promptforge.CodeInvalidArgument, "emit failed")
+	}
+	return data, nil
+}
@@ -0,0 +1,103 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/promptforge/promptforge"
+	"github.com/promptforge/promptforge/internal/ir"
+	"github.com/promptforge/promptforge/internal/parser"
+)
+
+// checkProjectDir validates the project directory passed to a core entry point.
+func checkProjectDir(projectDir string) error {
+	if projectDir == "" {
+		return promptforge.Errorf(promptforge.CodeInvalidArgument, "project directory cannot be empty")
+	}
+	if _, err := os.Stat(projectDir); os.IsNotExist(err) {
+		return promptforge.Errorf(promptforge.CodeNotFound, "project directory does not exist: %s", projectDir)
+	}
+	return nil
+}
+
+func errPlanNotFound(planPath string) error {
+	e := promptforge.Errorf(promptforge.CodeNotFound, "plan.md not found at %s", planPath).WithHint("Run 'promptforge init' first")
+	e.File = planPath
+	return e
+}
+
+func errPlanEmpty(planPath string) error {
+	e := promptforge.Errorf(promptforge.CodePlanEmpty, "plan.md is empty at %s", planPath)
+	e.File = planPath
+	return e
+}
+
+// readIRFile reads and decodes prompt.ir.json at irPath. A syntax error is
+// reported at its line and column.
+func readIRFile(irPath string) (*ir.PromptIR, error) {
+	data, err := os.ReadFile(irPath)
+	if err != nil {
+		e := promptforge.ReadError(err, "prompt.ir.json", irPath)
+		if e.Code == promptforge.CodeNotFound {
+			e.Hint = "Run 'promptforge compile' first"
+		}
+		return nil, e
+	}
+
+	return ParseIR(irPath, data)
+}
+
+// ParseIR decodes prompt IR read from name, which may be a file that does not
+// exist such as standard input. A syntax error is reported at its line and column.
+func ParseIR(name string, data []byte) (*ir.PromptIR, error) {
+	var promptIR ir.PromptIR
+	if err := json.Unmarshal(data, &promptIR); err != nil {
+		e := promptforge.Wrap(err, promptforge.CodeIRParse, "failed to parse prompt.ir.json")
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			// Offset counts the offending byte.
+			line, column := position(data, syntaxErr.Offset-1)
+			e.At(name, line, column)
+		}
+		return nil, e
+	}
+	return &promptIR, nil
+}
+
+// position converts a byte offset in data to a 1-based line and column.
+func position(data []byte, offset int64) (int, int) {
+	line, column := 1, 1
+	for i := int64(0); i < offset && i < int64(len(data)); i++ {
+		if data[i] == '\n' {
+			line++
+			column = 1
+		} else {
+			column++
+		}
+	}
+	return line, column
+}
+
+// locateError maps the line of a plan error, which refers to the expanded plan,
+// back to the file and line it came from.
+func locateError(err error, source *parser.Source) error {
+	var e *promptforge.Error
+	if errors.As(err, &e) && e.File == "" && e.Line > 0 {
+		origin := source.Origin(e.Line)
+		e.File = filepath.Clean(origin.File)
+		e.Line = origin.Line
+	}
+	return err
+}
+
+// relabelError names a plan error that points at path by name instead, so
+// errors in content that was not read from a file keep the name it was given.
+func relabelError(err error, path, name string) error {
+	var e *promptforge.Error
+	if errors.As(err, &e) && e.File == path {
+		e.File = name
+	}
+	return err
+}
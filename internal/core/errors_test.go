@@ -0,0 +1,44 @@
+package core
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/promptforge/promptforge"
+)
+
+func TestCompileProject_MissingPlanError(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	_, err := CompileProject(tmpDir, filepath.Join(tmpDir, "prompt.ir.json"))
+	if !errors.Is(err, &promptforge.Error{Code: promptforge.CodeNotFound}) {
+		t.Fatalf("Expected a not-found error, got %v", err)
+	}
+	var pfErr *promptforge.Error
+	if errors.As(err, &pfErr); pfErr.Hint != "Run 'promptforge init' first" {
+		t.Errorf("Expected the init hint, got %q", pfErr.Hint)
+	}
+}
+
+func TestReadIRFile_SyntaxErrorPosition(t *testing.T) {
+	irPath := filepath.Join(t.TempDir(), "prompt.ir.json")
+	if err := os.WriteFile(irPath, []byte("{\n  \"version\": \"1.0\",\n  oops\n}\n"), 0644); err != nil {
+		t.Fatalf("Failed to write prompt.ir.json: %v", err)
+	}
+
+	_, err := readIRFile(irPath)
+	var pfErr *promptforge.Error
+	if !errors.As(err, &pfErr) || pfErr.Code != promptforge.CodeIRParse {
+		t.Fatalf("Expected an ir-parse error, got %v", err)
+	}
+	if pfErr.File != irPath || pfErr.Line != 3 || pfErr.Column != 3 {
+		t.Errorf("Expected the error at %s:3:3, got %s:%d:%d", irPath, pfErr.File, pfErr.Line, pfErr.Column)
+	}
+
+	_, err = readIRFile(filepath.Join(t.TempDir(), "prompt.ir.json"))
+	if promptforge.CodeOf(err) != promptforge.CodeNotFound || !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected a not-found error wrapping os.ErrNotExist, got %v", err)
+	}
+}
@@ -0,0 +1,76 @@
+package core
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/promptforge/promptforge"
+	"github.com/promptforge/promptforge/internal/atomicfile"
+	"github.com/promptforge/promptforge/internal/codegen"
+	"github.com/promptforge/promptforge/internal/compiler"
+	"github.com/promptforge/promptforge/internal/ir"
+)
+
+// ExportSchema reads prompt.ir.json and writes standalone JSON Schemas, TypeScript
+// interfaces and Go types for its input and output contracts into outputDir.
+// It returns the paths of the files written.
+func ExportSchema(projectDir, outputDir, goPackage string) ([]string, error) {
+	if projectDir == "" {
+		return nil, promptforge.Errorf(promptforge.CodeInvalidArgument, "project directory cannot be empty")
+	}
+	if outputDir == "" {
+		return nil, promptforge.Errorf(promptforge.CodeInvalidArgument, "output directory cannot be empty")
+	}
+
+	if _, err := os.Stat(projectDir); os.IsNotExist(err) {
+		return nil, promptforge.Errorf(promptforge.CodeNotFound, "project directory does not exist: %s", projectDir)
+	}
+
+	irPath := filepath.Join(projectDir, "prompt.ir.json")
+	data, err := os.ReadFile(irPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, promptforge.Errorf(promptforge.CodeNotFound, "prompt.ir.json not found at %s", irPath).WithHint("Run 'promptforge compile' first")
+		}
+		if os.IsPermission(err) {
+			return nil, promptforge.Errorf(promptforge.CodePermissionDenied, "permission denied: cannot read prompt.ir.json at %s", irPath)
+		}
+		return nil, promptforge.Wrap(err, promptforge.IOCode(err), "failed to read prompt.ir.json at %s", irPath)
+	}
+
+	var promptIR ir.PromptIR
+	if err := json.Unmarshal(data, &promptIR); err != nil {
+		return nil, promptforge.Wrap(err, promptforge.CodeIRParse, "failed to parse prompt.ir.json")
+	}
+
+	if err := compiler.ValidateIR(&promptIR); err != nil {
+		return nil, promptforge.Wrap(err, promptforge.CodeIRInvalid, "IR validation failed")
+	}
+
+	artifacts, err := codegen.Generate(&promptIR, codegen.Options{GoPackage: goPackage})
+	if err != nil {
+		return nil, promptforge.Wrap(err, promptforge.CodeInvalidArgument, "code generation failed")
+	}
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		if os.IsPermission(err) {
+			return nil, promptforge.Errorf(promptforge.CodePermissionDenied, "permission denied: cannot create output directory %s", outputDir)
+		}
+		return nil, promptforge.Wrap(err, promptforge.IOCode(err), "failed to create output directory %s", outputDir)
+	}
+
+	// Write all artifacts together so the contract types never disagree
+	var txn atomicfile.Transaction
+	written := make([]string, 0, len(artifacts))
+	for _, artifact := range artifacts {
+		path := filepath.Join(outputDir, artifact.Name)
+		txn.Add(path, artifact.Name, artifact.Content, 0644)
+		written = append(written, path)
+	}
+	if err := txn.Commit(); err != nil {
+		return nil, err
+	}
+
+	return written, nil
+}
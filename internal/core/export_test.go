@@ -0,0 +1,45 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExportSchema_WritesArtifacts(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	promptforgeDir := filepath.Join(tmpDir, "promptforge")
+	if err := os.MkdirAll(promptforgeDir, 0755); err != nil {
+		t.Fatalf("Failed to create promptforge directory: %v", err)
+	}
+	planPath := filepath.Join(promptforgeDir, "plan.md")
+	if err := os.WriteFile(planPath, []byte("# Prompt Plan\n\n## Goal\nTest goal\n"), 0644); err != nil {
+		t.Fatalf("Failed to create plan.md: %v", err)
+	}
+	if _, err := CompileProject(tmpDir, filepath.Join(tmpDir, "prompt.ir.json")); err != nil {
+		t.Fatalf("CompileProject() failed: %v", err)
+	}
+
+	outputDir := filepath.Join(tmpDir, "generated")
+	written, err := ExportSchema(tmpDir, outputDir, "contract")
+	if err != nil {
+		t.Fatalf("ExportSchema() failed: %v", err)
+	}
+	if len(written) != 4 {
+		t.Fatalf("Expected 4 files written, got %d", len(written))
+	}
+	for _, path := range written {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("Expected %s to exist: %v", path, err)
+		}
+	}
+}
+
+func TestExportSchema_MissingIR(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	if _, err := ExportSchema(tmpDir, filepath.Join(tmpDir, "generated"), ""); err == nil {
+		t.Fatal("ExportSchema() should fail when prompt.ir.json does not exist")
+	}
+}
@@ -0,0 +1,96 @@
+package core
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/promptforge/promptforge/internal/compiler"
+)
+
+func writeExtendsProject(t *testing.T, child string) string {
+	t.Helper()
+	tmpDir := t.TempDir()
+
+	promptforgeDir := filepath.Join(tmpDir, "promptforge")
+	if err := os.MkdirAll(promptforgeDir, 0755); err != nil {
+		t.Fatalf("Failed to create promptforge directory: %v", err)
+	}
+	base := "# Base Support Plan\n\n## Goal\nAnswer customer support questions accurately.\n\n## Constraints\n- Must reply in English\n- Must cite the help center article used\n\n## Out of Scope\n- Refunds\n"
+	if err := os.WriteFile(filepath.Join(promptforgeDir, "base-support.plan.md"), []byte(base), 0644); err != nil {
+		t.Fatalf("Failed to write base plan: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(promptforgeDir, "plan.md"), []byte(child), 0644); err != nil {
+		t.Fatalf("Failed to write plan.md: %v", err)
+	}
+	return tmpDir
+}
+
+func TestCompileProjectWithExplain_Extends(t *testing.T) {
+	tmpDir := writeExtendsProject(t, "---\nextends: base-support.plan.md\n---\n# Billing Plan\n\n## Constraints\n- ~remove constraint-reply-in-english\n- Must include the invoice number\n")
+
+	outputPath := filepath.Join(tmpDir, "prompt.ir.json")
+	explainPath := filepath.Join(tmpDir, "prompt.ir.explain.json")
+	compiled, err := CompileProjectWithExplain(tmpDir, outputPath, explainPath)
+	if err != nil {
+		t.Fatalf("CompileProjectWithExplain() failed: %v", err)
+	}
+
+	var ruleIDs []string
+	for _, rule := range compiled.Rules[4:] {
+		ruleIDs = append(ruleIDs, rule.ID)
+	}
+	if len(ruleIDs) != 2 || ruleIDs[0] != "constraint-cite-help-center" || ruleIDs[1] != "constraint-include-invoice-number" {
+		t.Fatalf("Unexpected merged rules: %v", ruleIDs)
+	}
+	if len(compiled.FailureModes) != 4 {
+		t.Errorf("Out of Scope should be inherited, got %+v", compiled.FailureModes)
+	}
+
+	data, err := os.ReadFile(explainPath)
+	if err != nil {
+		t.Fatalf("Failed to read explain report: %v", err)
+	}
+	var report compiler.ExplainReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("Failed to parse explain report: %v", err)
+	}
+
+	if report.SystemRole.Source.Origin != "base" || report.SystemRole.Source.File != "promptforge/base-support.plan.md" {
+		t.Errorf("Goal should come from the base plan, got %+v", report.SystemRole.Source)
+	}
+	inherited, added := report.Rules[4].Source, report.Rules[5].Source
+	if inherited.Origin != "base" || inherited.Line != 8 {
+		t.Errorf("Unexpected source for inherited rule: %+v", inherited)
+	}
+	if added.Origin != "child" || added.File != "" || added.Line != 8 {
+		t.Errorf("Unexpected source for child rule: %+v", added)
+	}
+
+	if issues, err := AuditProject(tmpDir); err != nil || len(issues) != 0 {
+		t.Fatalf("Expected a clean audit, got %+v, %v", issues, err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "promptforge", "base-support.plan.md"), []byte("## Goal\nChanged\n"), 0644); err != nil {
+		t.Fatalf("Failed to modify base plan: %v", err)
+	}
+	if issues, err := AuditProject(tmpDir); err != nil || len(issues) != 1 {
+		t.Fatalf("Expected audit to flag the changed base plan, got %+v, %v", issues, err)
+	}
+}
+
+func TestLintProject_ExtendsUnknownTarget(t *testing.T) {
+	tmpDir := writeExtendsProject(t, "---\nextends: base-support.plan.md\n---\n## Constraints\n- ~remove constraint-nope\n")
+
+	diags, err := LintProject(tmpDir)
+	if err != nil {
+		t.Fatalf("LintProject() failed: %v", err)
+	}
+	if len(diags) != 1 || diags[0].Code != "PF111" || diags[0].Line != 5 || diags[0].File != "" {
+		t.Fatalf("Expected a single PF111 on plan.md line 5, got %+v", diags)
+	}
+
+	if _, err := CompileProject(tmpDir, filepath.Join(tmpDir, "prompt.ir.json")); err == nil {
+		t.Fatal("CompileProject() should fail on an unresolved override")
+	}
+}
@@ -0,0 +1,78 @@
+package core
+
+import (
+	"os"
+
+	"github.com/promptforge/promptforge"
+	"github.com/promptforge/promptforge/internal/formatter"
+)
+
+// FormatResult describes the outcome of formatting plan.md.
+type FormatResult struct {
+	// Path is the plan.md that was formatted.
+	Path string
+
+	// Changed reports whether plan.md was not already in canonical form.
+	Changed bool
+
+	// Diff is a unified diff from the current plan.md to its canonical form.
+	Diff string
+
+	// Formatted is plan.md in canonical form.
+	Formatted []byte
+}
+
+// FormatProject rewrites promptforge/plan.md in canonical form. With check set,
+// plan.md is left untouched and the result only reports what would change.
+func FormatProject(projectDir string, check bool) (*FormatResult, error) {
+	return FormatPlan(projectDir, PlanPath(projectDir), check)
+}
+
+// FormatPlan formats the plan at planPath instead of promptforge/plan.md.
+func FormatPlan(projectDir, planPath string, check bool) (*FormatResult, error) {
+	if projectDir == "" {
+		return nil, promptforge.Errorf(promptforge.CodeInvalidArgument, "project directory cannot be empty")
+	}
+
+	if _, err := os.Stat(projectDir); os.IsNotExist(err) {
+		return nil, promptforge.Errorf(promptforge.CodeNotFound, "project directory does not exist: %s", projectDir)
+	}
+
+	content, err := os.ReadFile(planPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errPlanNotFound(planPath)
+		}
+		if os.IsPermission(err) {
+			return nil, promptforge.Errorf(promptforge.CodePermissionDenied, "permission denied: cannot read plan.md at %s", planPath)
+		}
+		return nil, promptforge.Wrap(err, promptforge.IOCode(err), "failed to read plan.md at %s", planPath)
+	}
+
+	result := FormatContent(projectRelative(projectDir, planPath), content)
+	result.Path = planPath
+	if !result.Changed || check {
+		return result, nil
+	}
+
+	if err := os.WriteFile(planPath, result.Formatted, 0644); err != nil {
+		if os.IsPermission(err) {
+			return nil, promptforge.Errorf(promptforge.CodePermissionDenied, "permission denied: cannot write plan.md at %s", planPath)
+		}
+		return nil, promptforge.Wrap(err, promptforge.IOCode(err), "failed to write plan.md at %s", planPath)
+	}
+
+	return result, nil
+}
+
+// FormatContent formats plan content that is not read from a project file, such
+// as standard input. The diff names the content name.
+func FormatContent(name string, content []byte) *FormatResult {
+	formatted := formatter.Format(content)
+	result := &FormatResult{Path: name, Formatted: formatted}
+	if string(formatted) != string(content) {
+		result.Changed = true
+		result.Diff = formatter.Diff("a/"+name, "b/"+name, content, formatted)
+	}
+	return result
+}
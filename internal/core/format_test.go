@@ -0,0 +1,57 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFormatProject_CheckThenWrite(t *testing.T) {
+	tmpDir := t.TempDir()
+	promptforgeDir := filepath.Join(tmpDir, "promptforge")
+	if err := os.MkdirAll(promptforgeDir, 0755); err != nil {
+		t.Fatalf("Failed to create promptforge directory: %v", err)
+	}
+	planPath := filepath.Join(promptforgeDir, "plan.md")
+	planContent := "## constraints\n* Be strict\n\n## Goal\nA clear goal statement.\n"
+	if err := os.WriteFile(planPath, []byte(planContent), 0644); err != nil {
+		t.Fatalf("Failed to create plan.md: %v", err)
+	}
+
+	result, err := FormatProject(tmpDir, true)
+	if err != nil {
+		t.Fatalf("FormatProject(check) failed: %v", err)
+	}
+	if !result.Changed {
+		t.Fatal("expected check to report changes")
+	}
+	if !strings.Contains(result.Diff, "+## Constraints") || !strings.Contains(result.Diff, "-* Be strict") {
+		t.Errorf("unexpected diff:\n%s", result.Diff)
+	}
+	if data, _ := os.ReadFile(planPath); string(data) != planContent {
+		t.Fatal("check must not modify plan.md")
+	}
+
+	if _, err := FormatProject(tmpDir, false); err != nil {
+		t.Fatalf("FormatProject() failed: %v", err)
+	}
+	want := "## Goal\nA clear goal statement.\n\n## Constraints\n- Be strict\n"
+	if data, _ := os.ReadFile(planPath); string(data) != want {
+		t.Fatalf("unexpected plan.md:\n%s", data)
+	}
+
+	result, err = FormatProject(tmpDir, true)
+	if err != nil {
+		t.Fatalf("FormatProject(check) failed: %v", err)
+	}
+	if result.Changed || result.Diff != "" {
+		t.Fatalf("formatted plan should pass check, got diff:\n%s", result.Diff)
+	}
+}
+
+func TestFormatProject_MissingPlan(t *testing.T) {
+	if _, err := FormatProject(t.TempDir(), true); err == nil || !strings.Contains(err.Error(), "plan.md not found") {
+		t.Fatalf("expected plan.md not found error, got %v", err)
+	}
+}
@@ -0,0 +1,118 @@
+package core
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/promptforge/promptforge"
+)
+
+func writeIncludeProject(t *testing.T) string {
+	t.Helper()
+	tmpDir := t.TempDir()
+
+	if err := os.MkdirAll(filepath.Join(tmpDir, "promptforge"), 0755); err != nil {
+		t.Fatalf("Failed to create promptforge directory: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(tmpDir, "shared"), 0755); err != nil {
+		t.Fatalf("Failed to create shared directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "shared", "pii.md"), []byte("- Never store personal data\n- Use various masking\n"), 0644); err != nil {
+		t.Fatalf("Failed to write pii.md: %v", err)
+	}
+	plan := "# Prompt Plan\n\n## Goal\nA clear goal statement for include tests.\n\n## Constraints\n- @include ../shared/pii\n\n## Out of Scope\n- Payments\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, "promptforge", "plan.md"), []byte(plan), 0644); err != nil {
+		t.Fatalf("Failed to write plan.md: %v", err)
+	}
+	return tmpDir
+}
+
+func TestCompileProject_RecordsIncludes(t *testing.T) {
+	tmpDir := writeIncludeProject(t)
+
+	outputPath := filepath.Join(tmpDir, "prompt.ir.json")
+	explainPath := filepath.Join(tmpDir, "prompt.ir.explain.json")
+	compiled, err := CompileProjectWithExplain(tmpDir, outputPath, explainPath)
+	if err != nil {
+		t.Fatalf("CompileProjectWithExplain() failed: %v", err)
+	}
+
+	if len(compiled.Includes) != 1 || compiled.Includes[0].Path != "shared/pii.md" {
+		t.Fatalf("Expected shared/pii.md to be recorded, got %+v", compiled.Includes)
+	}
+
+	explain, err := os.ReadFile(explainPath)
+	if err != nil {
+		t.Fatalf("Failed to read explain report: %v", err)
+	}
+	if !strings.Contains(string(explain), `"file": "shared/pii.md"`) {
+		t.Errorf("Explain report should reference the included file:\n%s", explain)
+	}
+
+	issues, err := AuditProject(tmpDir)
+	if err != nil {
+		t.Fatalf("AuditProject() failed: %v", err)
+	}
+	if len(issues) != 0 {
+		t.Fatalf("Expected no audit issues, got %+v", issues)
+	}
+
+	if err := os.WriteFile(filepath.Join(tmpDir, "shared", "pii.md"), []byte("- Changed\n"), 0644); err != nil {
+		t.Fatalf("Failed to modify pii.md: %v", err)
+	}
+	issues, err = AuditProject(tmpDir)
+	if err != nil {
+		t.Fatalf("AuditProject() failed: %v", err)
+	}
+	if len(issues) != 1 || !strings.Contains(issues[0].Message, "shared/pii.md") {
+		t.Fatalf("Expected a changed-include warning, got %+v", issues)
+	}
+}
+
+func TestLintProject_MapsIncludedLines(t *testing.T) {
+	tmpDir := writeIncludeProject(t)
+
+	diags, err := LintProject(tmpDir)
+	if err != nil {
+		t.Fatalf("LintProject() failed: %v", err)
+	}
+	if len(diags) != 1 || diags[0].Code != "PF203" {
+		t.Fatalf("Expected one PF203 diagnostic, got %+v", diags)
+	}
+	if filepath.Base(diags[0].File) != "pii.md" || diags[0].Line != 2 {
+		t.Errorf("Expected diagnostic at pii.md:2, got %s:%d", diags[0].File, diags[0].Line)
+	}
+}
+
+func TestLintProject_ReportsUnresolvedInclude(t *testing.T) {
+	tmpDir := writeIncludeProject(t)
+	if err := os.Remove(filepath.Join(tmpDir, "shared", "pii.md")); err != nil {
+		t.Fatalf("Failed to remove pii.md: %v", err)
+	}
+
+	diags, err := LintProject(tmpDir)
+	if err != nil {
+		t.Fatalf("LintProject() failed: %v", err)
+	}
+	found := false
+	for _, diag := range diags {
+		if diag.Code == "PF110" && diag.Line == 7 && diag.File == "" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("Expected PF110 on plan.md line 7, got %+v", diags)
+	}
+
+	_, err = CompileProject(tmpDir, filepath.Join(tmpDir, "prompt.ir.json"))
+	if err == nil {
+		t.Fatal("CompileProject() should fail on an unresolved include")
+	}
+	var pfErr *promptforge.Error
+	if !errors.As(err, &pfErr) || pfErr.Code != promptforge.CodePlanInclude || pfErr.Line != 7 || filepath.Base(pfErr.File) != "plan.md" {
+		t.Errorf("Expected a plan-include error at plan.md:7, got %v", err)
+	}
+}
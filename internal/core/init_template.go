@@ -8,7 +8,10 @@ import (
 	"github.com/promptforge/promptforge/internal/templates"
 )
 
-func buildPlanContent(description string, templateName string) (string, error) {
+// buildPlanContent returns the initial plan.md content. Named templates are looked
+// up in the project and user template directories before the built-ins, and their
+// parameters are filled in from params.
+func buildPlanContent(projectDir string, description string, templateName string, params map[string]string) (string, error) {
 	if templateName == "" {
 		goalSection := "<!-- Describe the intended purpose and outcome of this prompt -->"
 		if description != "" {
@@ -33,12 +36,15 @@ The authoritative artifact is prompt.ir.json, generated by 'promptforge compile'
 `, goalSection), nil
 	}
 
-	tmpl, err := templates.Get(templateName)
+	tmpl, err := templates.Find(projectDir, templateName)
 	if err != nil {
 		return "", err
 	}
 
-	content := tmpl.Plan
+	content, err := tmpl.Render(params)
+	if err != nil {
+		return "", err
+	}
 	if description != "" {
 		content = overrideGoalSection(content, description)
 	}
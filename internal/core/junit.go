@@ -0,0 +1,86 @@
+package core
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/promptforge/promptforge"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	File      string        `xml:"file,attr,omitempty"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// JUnitXML renders a test report as JUnit XML for CI systems.
+func JUnitXML(report *TestReport) ([]byte, error) {
+	suite := junitTestSuite{
+		Name:     "promptforge",
+		Tests:    len(report.Results),
+		Failures: report.Failed(),
+	}
+	for _, result := range report.Results {
+		testCase := junitTestCase{
+			Name:      result.Name,
+			ClassName: "promptforge.tests",
+			File:      result.File,
+		}
+		if !result.Passed {
+			lines := make([]string, 0, len(result.Violations))
+			for _, violation := range result.Violations {
+				lines = append(lines, violation.String())
+			}
+			testCase.Failure = &junitFailure{
+				Message: result.Summary(),
+				Type:    "contract",
+				Text:    strings.Join(lines, "\n"),
+			}
+		}
+		suite.Cases = append(suite.Cases, testCase)
+	}
+
+	doc := junitTestSuites{
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Suites:   []junitTestSuite{suite},
+	}
+	data, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, promptforge.Wrap(err, promptforge.CodeInternal, "failed to encode JUnit report")
+	}
+	return append([]byte(xml.Header), append(data, '\n')...), nil
+}
+
+// Summary describes the outcome of a test case in one line.
+func (r TestResult) Summary() string {
+	if r.Outcome != r.Expect {
+		return fmt.Sprintf("expected %s, got %s", r.Expect, r.Outcome)
+	}
+	if len(r.Violations) > 0 {
+		return fmt.Sprintf("%s with %d contract violation(s)", r.Outcome, len(r.Violations))
+	}
+	return r.Outcome
+}
@@ -1,35 +1,148 @@
 package core
 
 import (
-	"fmt"
+	"bytes"
+	"encoding/json"
 	"os"
 	"path/filepath"
 
+	"github.com/promptforge/promptforge"
+	"github.com/promptforge/promptforge/internal/compiler"
 	"github.com/promptforge/promptforge/internal/linter"
+	"github.com/promptforge/promptforge/internal/parser"
 )
 
 // LintProject reads plan.md and returns lint diagnostics.
 func LintProject(projectDir string) ([]linter.Diagnostic, error) {
+	return LintPlan(projectDir, PlanPath(projectDir))
+}
+
+// LintPlan lints the plan at planPath instead of promptforge/plan.md.
+func LintPlan(projectDir, planPath string) ([]linter.Diagnostic, error) {
 	if projectDir == "" {
-		return nil, fmt.Errorf("project directory cannot be empty")
+		return nil, promptforge.Errorf(promptforge.CodeInvalidArgument, "project directory cannot be empty")
 	}
 
 	if _, err := os.Stat(projectDir); os.IsNotExist(err) {
-		return nil, fmt.Errorf("project directory does not exist: %s", projectDir)
+		return nil, promptforge.Errorf(promptforge.CodeNotFound, "project directory does not exist: %s", projectDir)
 	}
 
-	planPath := filepath.Join(projectDir, "promptforge", "plan.md")
 	if _, err := os.Stat(planPath); os.IsNotExist(err) {
-		return nil, fmt.Errorf("plan.md not found at %s. Run 'promptforge init' first", planPath)
+		return nil, errPlanNotFound(planPath)
+	}
+
+	config, err := LoadLintConfig(projectDir)
+	if err != nil {
+		return nil, err
+	}
+
+	source, err := loadPlanSource(planPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Lint the expanded plan, then map lines back to the files they came from
+	return remapDiagnostics(linter.LintPlanWithConfig(source.Content, config), source), nil
+}
+
+// LintPlanContent lints plan content that is not read from planPath, such as
+// standard input. planPath is resolved as in CompilePlanContent; diagnostics in
+// the plan itself leave File empty.
+func LintPlanContent(projectDir, planPath string, content []byte) ([]linter.Diagnostic, error) {
+	if err := checkProjectDir(projectDir); err != nil {
+		return nil, err
+	}
+
+	config, err := LoadLintConfig(projectDir)
+	if err != nil {
+		return nil, err
 	}
 
-	planContent, err := os.ReadFile(planPath)
+	source := parser.LoadPlanContent(contentPath(projectDir, planPath), content, compiler.ItemIDs)
+	return remapDiagnostics(linter.LintPlanWithConfig(source.Content, config), source), nil
+}
+
+// LoadLintConfig reads the project lint config from .promptforge/lint.json. Settings
+// missing from the file, or the whole file, fall back to linter.DefaultConfig.
+func LoadLintConfig(projectDir string) (linter.Config, error) {
+	config := linter.DefaultConfig()
+
+	configPath := filepath.Join(projectDir, ".promptforge", "lint.json")
+	data, err := os.ReadFile(configPath)
 	if err != nil {
+		if os.IsNotExist(err) {
+			return config, nil
+		}
 		if os.IsPermission(err) {
-			return nil, fmt.Errorf("permission denied: cannot read plan.md at %s", planPath)
+			return config, promptforge.Errorf(promptforge.CodePermissionDenied, "permission denied: cannot read lint config at %s", configPath)
+		}
+		return config, promptforge.Wrap(err, promptforge.IOCode(err), "failed to read lint config at %s", configPath)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&config); err != nil {
+		return config, promptforge.Wrap(err, promptforge.CodeInvalidArgument, "failed to parse lint config at %s", configPath)
+	}
+	if err := config.Scan.Validate(); err != nil {
+		return config, promptforge.Wrap(err, promptforge.CodeInvalidArgument, "invalid lint config at %s", configPath)
+	}
+
+	return config, nil
+}
+
+// maxFixPasses bounds how often FixProject re-lints after applying fixes. Fixes that
+// overlap are applied over several passes.
+const maxFixPasses = 10
+
+// FixProject applies the fixes attached to plan.md diagnostics and writes the result
+// back to plan.md. It returns the number of fixes applied and the diagnostics that
+// remain.
+func FixProject(projectDir string) (int, []linter.Diagnostic, error) {
+	return FixPlan(projectDir, PlanPath(projectDir))
+}
+
+// FixPlan applies lint fixes to the plan at planPath instead of promptforge/plan.md.
+func FixPlan(projectDir, planPath string) (int, []linter.Diagnostic, error) {
+	diagnostics, err := LintPlan(projectDir, planPath)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	total := 0
+	for pass := 0; pass < maxFixPasses; pass++ {
+		content, err := os.ReadFile(planPath)
+		if err != nil {
+			if os.IsPermission(err) {
+				return total, nil, promptforge.Errorf(promptforge.CodePermissionDenied, "permission denied: cannot read plan.md at %s", planPath)
+			}
+			return total, nil, promptforge.Wrap(err, promptforge.IOCode(err), "failed to read plan.md at %s", planPath)
+		}
+
+		var planDiagnostics []linter.Diagnostic
+		for _, diag := range diagnostics {
+			if diag.File == "" {
+				planDiagnostics = append(planDiagnostics, diag)
+			}
+		}
+
+		fixed, applied := linter.ApplyFixes(content, planDiagnostics)
+		if applied == 0 {
+			break
+		}
+		if err := os.WriteFile(planPath, fixed, 0644); err != nil {
+			if os.IsPermission(err) {
+				return total, nil, promptforge.Errorf(promptforge.CodePermissionDenied, "permission denied: cannot write plan.md at %s", planPath)
+			}
+			return total, nil, promptforge.Wrap(err, promptforge.IOCode(err), "failed to write plan.md at %s", planPath)
+		}
+		total += applied
+
+		diagnostics, err = LintPlan(projectDir, planPath)
+		if err != nil {
+			return total, nil, err
 		}
-		return nil, fmt.Errorf("failed to read plan.md at %s: %w", planPath, err)
 	}
 
-	return linter.LintPlan(planContent), nil
+	return total, diagnostics, nil
 }
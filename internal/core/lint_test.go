@@ -0,0 +1,86 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFixProject_FixesPlanButNotIncludedFiles(t *testing.T) {
+	tmpDir := writeIncludeProject(t)
+	planPath := filepath.Join(tmpDir, "promptforge", "plan.md")
+	sharedPath := filepath.Join(tmpDir, "shared", "pii.md")
+
+	// The included file repeats a constraint and plan.md misspells a heading
+	shared := "- Never store personal data\n- never store personal data\n"
+	if err := os.WriteFile(sharedPath, []byte(shared), 0644); err != nil {
+		t.Fatalf("Failed to write pii.md: %v", err)
+	}
+	plan := "# Prompt Plan\n\n## Goal\nA clear goal statement for fix tests.\n\n## Constraints\n- @include ../shared/pii\n- Reply in English\n- reply in English\n\n## Out of scop\n- Payments\n"
+	if err := os.WriteFile(planPath, []byte(plan), 0644); err != nil {
+		t.Fatalf("Failed to write plan.md: %v", err)
+	}
+
+	applied, diagnostics, err := FixProject(tmpDir)
+	if err != nil {
+		t.Fatalf("FixProject() failed: %v", err)
+	}
+	if applied != 2 {
+		t.Errorf("Expected 2 fixes, got %d", applied)
+	}
+
+	got, _ := os.ReadFile(planPath)
+	want := "# Prompt Plan\n\n## Goal\nA clear goal statement for fix tests.\n\n## Constraints\n- @include ../shared/pii\n- Reply in English\n\n## Out of Scope\n- Payments\n"
+	if string(got) != want {
+		t.Fatalf("unexpected plan.md:\n%s", got)
+	}
+	if data, _ := os.ReadFile(sharedPath); string(data) != shared {
+		t.Fatalf("included file must not be modified:\n%s", data)
+	}
+
+	if len(diagnostics) != 1 || diagnostics[0].Code != "PF207" || diagnostics[0].File != sharedPath || diagnostics[0].Fix != nil {
+		t.Fatalf("Expected only the PF207 in the included file to remain, got %+v", diagnostics)
+	}
+}
+
+func TestLintProject_UsesLintConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(tmpDir, "promptforge"), 0755); err != nil {
+		t.Fatalf("Failed to create promptforge directory: %v", err)
+	}
+	plan := "## Goal\nA clear goal statement for config tests.\n\n## Constraints\n- Be helpful\n\n## Out of Scope\n- Payments\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, "promptforge", "plan.md"), []byte(plan), 0644); err != nil {
+		t.Fatalf("Failed to write plan.md: %v", err)
+	}
+
+	diagnostics, err := LintProject(tmpDir)
+	if err != nil {
+		t.Fatalf("LintProject() failed: %v", err)
+	}
+	if len(diagnostics) != 1 || diagnostics[0].Code != "PF208" {
+		t.Fatalf("Expected PF208 with the default config, got %+v", diagnostics)
+	}
+
+	configDir := filepath.Join(tmpDir, ".promptforge")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatalf("Failed to create config directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(configDir, "lint.json"), []byte(`{"verifiability": {"warn_below": 0}}`), 0644); err != nil {
+		t.Fatalf("Failed to write lint.json: %v", err)
+	}
+	diagnostics, err = LintProject(tmpDir)
+	if err != nil {
+		t.Fatalf("LintProject() failed: %v", err)
+	}
+	if len(diagnostics) != 0 {
+		t.Fatalf("Expected no diagnostics with verifiability disabled, got %+v", diagnostics)
+	}
+
+	if err := os.WriteFile(filepath.Join(configDir, "lint.json"), []byte(`{"verifiability": {"warn_under": 10}}`), 0644); err != nil {
+		t.Fatalf("Failed to write lint.json: %v", err)
+	}
+	if _, err := LintProject(tmpDir); err == nil || !strings.Contains(err.Error(), "failed to parse lint config") {
+		t.Fatalf("Expected a lint config error, got %v", err)
+	}
+}
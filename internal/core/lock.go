@@ -0,0 +1,18 @@
+package core
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/promptforge/promptforge/internal/atomicfile"
+)
+
+// lockWait is how long a compile or migrate waits for another one on the same
+// project before giving up.
+const lockWait = 10 * time.Second
+
+// lockProject acquires the project lock, .promptforge/compile.lock, that
+// serializes commands writing the project's artifacts.
+func lockProject(projectDir string) (*atomicfile.Lock, error) {
+	return atomicfile.Acquire(filepath.Join(projectDir, ".promptforge", "compile.lock"), lockWait)
+}
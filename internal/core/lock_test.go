@@ -0,0 +1,49 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// TestCompileProject_Concurrent tests that concurrent compiles of one project are
+// serialized and leave complete artifacts and no lock file behind.
+func TestCompileProject_Concurrent(t *testing.T) {
+	tmpDir := t.TempDir()
+	promptforgeDir := filepath.Join(tmpDir, "promptforge")
+	if err := os.MkdirAll(promptforgeDir, 0755); err != nil {
+		t.Fatalf("Failed to create promptforge directory: %v", err)
+	}
+	planContent := []byte("# Prompt Plan\n\n## Goal\nTest goal\n\n## Constraints\n- Be brief\n")
+	if err := os.WriteFile(filepath.Join(promptforgeDir, "plan.md"), planContent, 0644); err != nil {
+		t.Fatalf("Failed to create plan.md: %v", err)
+	}
+
+	outputPath := filepath.Join(tmpDir, "prompt.ir.json")
+	explainPath := filepath.Join(tmpDir, "prompt.ir.explain.json")
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := CompileProjectWithExplain(tmpDir, outputPath, explainPath)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("CompileProjectWithExplain() failed: %v", err)
+		}
+	}
+
+	if _, err := readIRFile(outputPath); err != nil {
+		t.Errorf("prompt.ir.json is not a complete IR: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, ".promptforge", "compile.lock")); !os.IsNotExist(err) {
+		t.Errorf("Expected the lock file to be removed, got %v", err)
+	}
+}
@@ -1,61 +1,69 @@
 package core
 
 import (
-	"encoding/json"
 	"fmt"
-	"os"
 	"path/filepath"
 
+	"github.com/promptforge/promptforge"
+	"github.com/promptforge/promptforge/internal/atomicfile"
 	"github.com/promptforge/promptforge/internal/compiler"
 	"github.com/promptforge/promptforge/internal/ir"
 )
 
-// MigrateIR upgrades prompt.ir.json to the current IR version.
-func MigrateIR(projectDir string) error {
-	if projectDir == "" {
-		return fmt.Errorf("project directory cannot be empty")
+// MigrateIR upgrades prompt.ir.json to the current IR version and rewrites its
+// schema. It returns the files it wrote.
+func MigrateIR(projectDir string) ([]string, error) {
+	if err := checkProjectDir(projectDir); err != nil {
+		return nil, err
 	}
 
-	if _, err := os.Stat(projectDir); os.IsNotExist(err) {
-		return fmt.Errorf("project directory does not exist: %s", projectDir)
+	lock, err := lockProject(projectDir)
+	if err != nil {
+		return nil, err
 	}
+	defer lock.Release()
 
 	irPath := filepath.Join(projectDir, "prompt.ir.json")
-	data, err := os.ReadFile(irPath)
+	promptIR, err := readIRFile(irPath)
 	if err != nil {
-		if os.IsPermission(err) {
-			return fmt.Errorf("permission denied: cannot read prompt.ir.json at %s", irPath)
-		}
-		return fmt.Errorf("failed to read prompt.ir.json at %s: %w", irPath, err)
+		return nil, err
 	}
 
-	var promptIR ir.PromptIR
-	if err := json.Unmarshal(data, &promptIR); err != nil {
-		return fmt.Errorf("failed to parse prompt.ir.json: %w", err)
+	migrated, err := migrateToCurrent(promptIR)
+	if err != nil {
+		return nil, err
 	}
 
-	migrated, err := migrateToCurrent(&promptIR)
+	// Rewrite the IR and its schema together
+	schemaData, err := ir.PromptIRSchemaJSON()
 	if err != nil {
-		return err
+		return nil, promptforge.Wrap(err, promptforge.CodeInternal, "failed to marshal IR schema")
 	}
-
+	schemaPath := filepath.Join(projectDir, "prompt.ir.schema.json")
+	var (
+		txn     atomicfile.Transaction
+		written []string
+	)
 	if migrated {
-		if err := compiler.WriteIR(&promptIR, irPath); err != nil {
-			return err
+		irData, err := compiler.MarshalIR(promptIR)
+		if err != nil {
+			return nil, err
 		}
+		txn.Add(irPath, "prompt.ir.json", irData, 0644)
+		written = append(written, irPath)
 	}
-
-	schemaPath := filepath.Join(projectDir, "prompt.ir.schema.json")
-	if err := compiler.WriteIRSchema(schemaPath); err != nil {
-		return err
+	txn.Add(schemaPath, "prompt.ir.schema.json", schemaData, 0644)
+	written = append(written, schemaPath)
+	if err := txn.Commit(); err != nil {
+		return nil, err
 	}
 
-	return nil
+	return written, nil
 }
 
 func migrateToCurrent(promptIR *ir.PromptIR) (bool, error) {
 	if promptIR == nil {
-		return false, fmt.Errorf("prompt IR is nil")
+		return false, promptforge.Errorf(promptforge.CodeInvalidArgument, "prompt IR is nil")
 	}
 
 	if promptIR.Version == "" || promptIR.Version == "0" {
@@ -64,7 +72,8 @@ func migrateToCurrent(promptIR *ir.PromptIR) (bool, error) {
 	}
 
 	if promptIR.Version != ir.CurrentVersion {
-		return false, fmt.Errorf("unsupported IR version: %s", promptIR.Version)
+		return false, promptforge.Errorf(promptforge.CodeIRVersion, "unsupported IR version: %s", promptIR.Version).
+			WithHint(fmt.Sprintf("This build supports IR version %s", ir.CurrentVersion))
 	}
 
 	return false, nil
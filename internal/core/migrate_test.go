@@ -36,7 +36,7 @@ func TestMigrateIR_AddsVersion(t *testing.T) {
 		t.Fatalf("Failed to write prompt.ir.json: %v", err)
 	}
 
-	if err := MigrateIR(tmpDir); err != nil {
+	if _, err := MigrateIR(tmpDir); err != nil {
 		t.Fatalf("MigrateIR() failed: %v", err)
 	}
 
@@ -79,7 +79,7 @@ func TestMigrateIR_UnsupportedVersion(t *testing.T) {
 		t.Fatalf("Failed to write prompt.ir.json: %v", err)
 	}
 
-	if err := MigrateIR(tmpDir); err == nil {
+	if _, err := MigrateIR(tmpDir); err == nil {
 		t.Fatal("Expected MigrateIR() to fail for unsupported version")
 	}
 }
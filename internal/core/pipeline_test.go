@@ -0,0 +1,82 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/promptforge/promptforge"
+	"github.com/promptforge/promptforge/internal/codegen"
+)
+
+func TestCompilePlanContent_ResolvesIncludesAgainstProject(t *testing.T) {
+	tmpDir := writeIncludeProject(t)
+
+	plan := "# Prompt Plan\n\n## Goal\nA clear goal statement for include tests.\n\n## Constraints\n- @include shared/pii\n\n## Out of Scope\n- Payments\n"
+	compiled, _, err := CompilePlanContent(tmpDir, "<stdin>", []byte(plan), false)
+	if err != nil {
+		t.Fatalf("CompilePlanContent() failed: %v", err)
+	}
+	if len(compiled.Includes) != 1 || compiled.Includes[0].Path != "shared/pii.md" {
+		t.Fatalf("Expected shared/pii.md to be recorded, got %+v", compiled.Includes)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "prompt.ir.json")); !os.IsNotExist(err) {
+		t.Errorf("CompilePlanContent() should not write prompt.ir.json, stat: %v", err)
+	}
+
+	_, _, err = CompilePlanContent(tmpDir, "<stdin>", []byte("# Prompt Plan\n\n## Constraints\n- @include missing\n"), false)
+	var e *promptforge.Error
+	if !errors.As(err, &e) || e.Code != promptforge.CodePlanInclude || e.File != "<stdin>" || e.Line != 4 {
+		t.Fatalf("Expected a plan-include error at <stdin>:4, got %#v", err)
+	}
+}
+
+func TestLintPlanContent(t *testing.T) {
+	tmpDir := writeIncludeProject(t)
+
+	diagnostics, err := LintPlanContent(tmpDir, "<stdin>", []byte("# Prompt Plan\n"))
+	if err != nil {
+		t.Fatalf("LintPlanContent() failed: %v", err)
+	}
+	if len(diagnostics) == 0 || diagnostics[0].Code != "PF100" || diagnostics[0].File != "" {
+		t.Fatalf("Expected PF100 for the missing Goal in the plan itself, got %+v", diagnostics)
+	}
+}
+
+func TestEmit_FromIRData(t *testing.T) {
+	tmpDir := writeIncludeProject(t)
+
+	compiled, err := CompileProject(tmpDir, filepath.Join(tmpDir, "prompt.ir.json"))
+	if err != nil {
+		t.Fatalf("CompileProject() failed: %v", err)
+	}
+	data, err := json.Marshal(compiled)
+	if err != nil {
+		t.Fatalf("Failed to marshal IR: %v", err)
+	}
+
+	fromData, err := Emit("<stdin>", data, codegen.TargetOpenAI, codegen.Options{Model: "gpt-test"}, nil)
+	if err != nil {
+		t.Fatalf("Emit() failed: %v", err)
+	}
+	fromProject, err := EmitProject(tmpDir, codegen.TargetOpenAI, codegen.Options{Model: "gpt-test"}, nil)
+	if err != nil {
+		t.Fatalf("EmitProject() failed: %v", err)
+	}
+	if string(fromData) != string(fromProject) {
+		t.Errorf("Emit() and EmitProject() differ:\n%s\n%s", fromData, fromProject)
+	}
+
+	_, err = Emit("<stdin>", []byte("{\n  \"version\": \n}"), codegen.TargetOpenAI, codegen.Options{}, nil)
+	var e *promptforge.Error
+	if !errors.As(err, &e) || e.Code != promptforge.CodeIRParse || e.File != "<stdin>" || e.Line != 3 {
+		t.Fatalf("Expected an ir-parse error at <stdin>:3, got %#v", err)
+	}
+
+	if _, err := Emit("<stdin>", data, "bogus", codegen.Options{}, nil); err == nil || !strings.Contains(err.Error(), "unknown emit target") {
+		t.Errorf("Expected an unknown target error, got %v", err)
+	}
+}
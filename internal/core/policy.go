@@ -0,0 +1,73 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/promptforge/promptforge"
+	"github.com/promptforge/promptforge/internal/ir"
+	"github.com/promptforge/promptforge/internal/policy"
+)
+
+// LoadPolicy reads a policy pack from a .json, .yaml or .yml file and validates
+// it.
+func LoadPolicy(path string) (*policy.Pack, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsPermission(err) {
+			return nil, promptforge.Errorf(promptforge.CodePermissionDenied, "permission denied: cannot read policy at %s", path)
+		}
+		return nil, promptforge.Wrap(err, promptforge.IOCode(err), "failed to read policy at %s", path)
+	}
+
+	var pack policy.Pack
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&pack)
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err = decoder.Decode(&pack); errors.Is(err, io.EOF) {
+			err = nil
+		}
+	default:
+		return nil, promptforge.Errorf(promptforge.CodeInvalidArgument, "unsupported policy file %s", path).
+			WithHint("Use a .json, .yaml or .yml file")
+	}
+	if err != nil {
+		return nil, promptforge.Wrap(err, promptforge.CodeInvalidArgument, "failed to parse policy at %s", path)
+	}
+	if err := pack.Validate(); err != nil {
+		return nil, promptforge.Wrap(err, promptforge.CodeInvalidArgument, "invalid policy at %s", path)
+	}
+	return &pack, nil
+}
+
+// auditPolicies checks promptIR against each policy pack, reporting every
+// violated policy with its PF5xx code.
+func auditPolicies(promptIR *ir.PromptIR, packs []*policy.Pack) []AuditIssue {
+	var issues []AuditIssue
+	for _, pack := range packs {
+		for _, violation := range pack.Evaluate(promptIR) {
+			issues = append(issues, AuditIssue{
+				Code:     violation.Code,
+				Severity: violation.Severity,
+				Message:  fmt.Sprintf("policy %s: %s", violation.Policy, violation.Message),
+				Artifact: irArtifact,
+				Pointer:  violation.Pointer,
+				Policy:   violation.Policy,
+			})
+		}
+	}
+	return issues
+}
@@ -0,0 +1,46 @@
+package core
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/promptforge/promptforge"
+	"github.com/promptforge/promptforge/internal/ir"
+	"github.com/promptforge/promptforge/internal/render"
+)
+
+// RenderProject reads prompt.ir.json and substitutes variable values into it.
+// It fails if a required variable has no value or a value does not match its declared type.
+func RenderProject(projectDir string, values map[string]string) (*ir.PromptIR, error) {
+	if projectDir == "" {
+		return nil, promptforge.Errorf(promptforge.CodeInvalidArgument, "project directory cannot be empty")
+	}
+
+	if _, err := os.Stat(projectDir); os.IsNotExist(err) {
+		return nil, promptforge.Errorf(promptforge.CodeNotFound, "project directory does not exist: %s", projectDir)
+	}
+
+	irPath := filepath.Join(projectDir, "prompt.ir.json")
+	data, err := os.ReadFile(irPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, promptforge.Errorf(promptforge.CodeNotFound, "prompt.ir.json not found at %s", irPath).WithHint("Run 'promptforge compile' first")
+		}
+		if os.IsPermission(err) {
+			return nil, promptforge.Errorf(promptforge.CodePermissionDenied, "permission denied: cannot read prompt.ir.json at %s", irPath)
+		}
+		return nil, promptforge.Wrap(err, promptforge.IOCode(err), "failed to read prompt.ir.json at %s", irPath)
+	}
+
+	var promptIR ir.PromptIR
+	if err := json.Unmarshal(data, &promptIR); err != nil {
+		return nil, promptforge.Wrap(err, promptforge.CodeIRParse, "failed to parse prompt.ir.json")
+	}
+
+	rendered, err := render.Render(&promptIR, values)
+	if err != nil {
+		return nil, promptforge.Wrap(err, promptforge.CodeVariable, "render failed")
+	}
+	return rendered, nil
+}
@@ -0,0 +1,77 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"github.com/promptforge/promptforge"
+	"github.com/promptforge/promptforge/internal/compiler"
+	"github.com/promptforge/promptforge/internal/contract"
+	"github.com/promptforge/promptforge/internal/provider"
+	"github.com/promptforge/promptforge/internal/render"
+)
+
+// RunResult is the outcome of one model call made by RunProject.
+type RunResult struct {
+	Provider string `json:"provider"`
+	Model    string `json:"model,omitempty"`
+
+	// Response is the raw model reply.
+	Response string `json:"response"`
+
+	// Outcome is "pass", "fail", or the failure mode the response reported.
+	Outcome string `json:"outcome"`
+
+	// Violations lists how the input or response breaks the contract.
+	Violations []contract.Violation `json:"violations"`
+}
+
+// RunProject renders prompt.ir.json with the given variable values, sends the
+// system prompt and the JSON input payload to the provider, and validates the
+// response against the contract the same way RunTests checks recorded responses.
+func RunProject(ctx context.Context, projectDir string, p provider.Provider, input []byte, values map[string]string) (*RunResult, error) {
+	if p == nil {
+		return nil, promptforge.Errorf(promptforge.CodeInvalidArgument, "provider cannot be nil")
+	}
+
+	rendered, err := RenderProject(projectDir, values)
+	if err != nil {
+		return nil, err
+	}
+	if err := compiler.ValidateIR(rendered); err != nil {
+		return nil, promptforge.Wrap(err, promptforge.CodeIRInvalid, "IR validation failed")
+	}
+
+	validator, err := contract.NewValidator(rendered)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(input))
+	decoder.UseNumber()
+	var payload interface{}
+	if err := decoder.Decode(&payload); err != nil {
+		return nil, promptforge.Wrap(err, promptforge.CodeContractViolation, "input is not valid JSON")
+	}
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, input); err != nil {
+		return nil, promptforge.Wrap(err, promptforge.CodeContractViolation, "input is not valid JSON")
+	}
+
+	resp, err := p.Complete(ctx, provider.Request{
+		SystemPrompt: render.SystemPrompt(rendered),
+		Input:        compact.Bytes(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RunResult{
+		Provider: p.Name(),
+		Model:    resp.Model,
+		Response: resp.Text,
+	}
+	result.Outcome, result.Violations = checkResponse(validator, payload, []byte(resp.Text))
+	return result, nil
+}
@@ -0,0 +1,66 @@
+package core
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/promptforge/promptforge/internal/provider"
+)
+
+func TestRunProject_MockProvider(t *testing.T) {
+	tmpDir := writeTestProject(t, nil)
+	fixture := filepath.Join(tmpDir, "promptforge", "mock.yaml")
+	content := `responses:
+  - input: {order_id: A-1}
+    response: '{"decision": "refund"}'
+  - input: {order_id: A-2}
+    response: 'I would refund this order.'
+  - response: '{"error": "missing-required"}'
+`
+	if err := os.WriteFile(fixture, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write fixture: %v", err)
+	}
+	mock, err := provider.NewMock(fixture)
+	if err != nil {
+		t.Fatalf("NewMock() failed: %v", err)
+	}
+
+	tests := []struct {
+		input          string
+		wantOutcome    string
+		wantViolations int
+	}{
+		{input: `{"order_id": "A-1"}`, wantOutcome: "pass"},
+		{input: `{"order_id": "A-2"}`, wantOutcome: "fail", wantViolations: 1},
+		{input: `{}`, wantOutcome: "missing-required"},
+	}
+	for _, tt := range tests {
+		result, err := RunProject(context.Background(), tmpDir, mock, []byte(tt.input), nil)
+		if err != nil {
+			t.Fatalf("RunProject(%s) failed: %v", tt.input, err)
+		}
+		if result.Provider != "mock" || result.Outcome != tt.wantOutcome || len(result.Violations) != tt.wantViolations {
+			t.Errorf("RunProject(%s) = %s with %v, want %s with %d violation(s)", tt.input, result.Outcome, result.Violations, tt.wantOutcome, tt.wantViolations)
+		}
+	}
+}
+
+func TestRunProject_InvalidInputJSON(t *testing.T) {
+	tmpDir := writeTestProject(t, nil)
+	fixture := filepath.Join(tmpDir, "mock.yaml")
+	if err := os.WriteFile(fixture, []byte("responses:\n  - response: '{}'\n"), 0644); err != nil {
+		t.Fatalf("Failed to write fixture: %v", err)
+	}
+	mock, err := provider.NewMock(fixture)
+	if err != nil {
+		t.Fatalf("NewMock() failed: %v", err)
+	}
+
+	_, err = RunProject(context.Background(), tmpDir, mock, []byte(`{"order_id": `), nil)
+	if err == nil || !strings.Contains(err.Error(), "input is not valid JSON") {
+		t.Fatalf("Expected an invalid input error, got %v", err)
+	}
+}
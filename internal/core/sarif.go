@@ -0,0 +1,146 @@
+package core
+
+import (
+	"encoding/json"
+
+	"github.com/promptforge/promptforge"
+	"github.com/promptforge/promptforge/internal/policy"
+	"github.com/promptforge/promptforge/internal/scan"
+)
+
+// auditRuleDescriptions describes the audit checks' own issue codes for SARIF
+// rule metadata. Scan and policy codes are described by their packages.
+var auditRuleDescriptions = map[string]string{
+	AuditIRInvalid:         "prompt.ir.json fails validation",
+	AuditIRVersion:         "prompt.ir.json is not at the current IR version",
+	AuditSchemaMissing:     "prompt.ir.schema.json is missing",
+	AuditSchemaDrift:       "prompt.ir.schema.json does not match the current schema",
+	AuditIncludeMissing:    "An included file no longer exists",
+	AuditIncludeUnreadable: "An included file cannot be read",
+	AuditIncludeChanged:    "An included file has changed since compile",
+}
+
+// ruleDescription describes an audit issue code, reporting false for an unknown
+// code.
+func ruleDescription(code string) (string, bool) {
+	for _, descriptions := range []map[string]string{auditRuleDescriptions, scan.Descriptions, policy.Descriptions} {
+		if description, ok := descriptions[code]; ok {
+			return description, true
+		}
+	}
+	return "", false
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string        `json:"id"`
+	ShortDescription *sarifMessage `json:"shortDescription,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation  `json:"physicalLocation"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// sarifLogicalLocation holds the JSON pointer of an issue within its artifact.
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// AuditSARIF renders audit issues as a SARIF 2.1.0 log for code scanning tools.
+// Artifact paths stay relative to the project directory, and each issue's JSON
+// pointer is given as a logical location.
+func AuditSARIF(issues []AuditIssue) ([]byte, error) {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "promptforge",
+			InformationURI: "https://github.com/promptforge/promptforge",
+			Rules:          []sarifRule{},
+		}},
+		Results: []sarifResult{},
+	}
+
+	ruleIndex := make(map[string]int)
+	for _, issue := range issues {
+		index, ok := ruleIndex[issue.Code]
+		if !ok {
+			index = len(run.Tool.Driver.Rules)
+			ruleIndex[issue.Code] = index
+			rule := sarifRule{ID: issue.Code}
+			if description, ok := ruleDescription(issue.Code); ok {
+				rule.ShortDescription = &sarifMessage{Text: description}
+			}
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, rule)
+		}
+
+		level := "warning"
+		if issue.Severity == "error" {
+			level = "error"
+		}
+		location := sarifLocation{
+			PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: issue.Artifact}},
+		}
+		if issue.Pointer != "" {
+			location.LogicalLocations = []sarifLogicalLocation{{FullyQualifiedName: issue.Pointer, Kind: "member"}}
+		}
+		run.Results = append(run.Results, sarifResult{
+			RuleID:    issue.Code,
+			RuleIndex: index,
+			Level:     level,
+			Message:   sarifMessage{Text: issue.Message},
+			Locations: []sarifLocation{location},
+		})
+	}
+
+	log := sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	}
+	data, err := json.MarshalIndent(log, "", "  ")
+	if err != nil {
+		return nil, promptforge.Wrap(err, promptforge.CodeInternal, "failed to encode SARIF log")
+	}
+	return append(data, '\n'), nil
+}
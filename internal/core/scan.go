@@ -0,0 +1,82 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/promptforge/promptforge/internal/ir"
+	"github.com/promptforge/promptforge/internal/scan"
+)
+
+// auditScan scans the prompt text in a compiled IR for secrets, personal data and
+// prompt-injection phrasing, reporting each finding with the IR field it is in.
+func auditScan(promptIR *ir.PromptIR, config scan.Config) ([]AuditIssue, error) {
+	var issues []AuditIssue
+	for _, field := range promptTextFields(promptIR) {
+		findings, err := scan.Text(field.text, config)
+		if err != nil {
+			return nil, err
+		}
+		for _, finding := range findings {
+			issues = append(issues, AuditIssue{
+				Code:     finding.Code,
+				Severity: finding.Severity,
+				Message:  fmt.Sprintf("%s in %s", finding.Message, field.path),
+				Artifact: irArtifact,
+				Pointer:  field.pointer,
+			})
+		}
+	}
+	return issues, nil
+}
+
+// textField is an IR field that reaches the model, with its path as shown in
+// messages and as a JSON pointer.
+type textField struct {
+	path    string
+	pointer string
+	text    string
+}
+
+// promptTextFields returns the IR fields whose text reaches the model.
+func promptTextFields(promptIR *ir.PromptIR) []textField {
+	fields := []textField{{path: "system_role", pointer: "/system_role", text: promptIR.SystemRole}}
+	for i, rule := range promptIR.Rules {
+		fields = append(fields,
+			textField{path: fmt.Sprintf("rules[%d].description", i), pointer: fmt.Sprintf("/rules/%d/description", i), text: rule.Description},
+			textField{path: fmt.Sprintf("rules[%d].condition", i), pointer: fmt.Sprintf("/rules/%d/condition", i), text: rule.Condition},
+		)
+	}
+	for i, fm := range promptIR.FailureModes {
+		fields = append(fields,
+			textField{path: fmt.Sprintf("failure_modes[%d].condition", i), pointer: fmt.Sprintf("/failure_modes/%d/condition", i), text: fm.Condition},
+			textField{path: fmt.Sprintf("failure_modes[%d].response", i), pointer: fmt.Sprintf("/failure_modes/%d/response", i), text: fm.Response},
+		)
+	}
+	for i, tool := range promptIR.Tools {
+		path, pointer := fmt.Sprintf("tools[%d]", i), fmt.Sprintf("/tools/%d", i)
+		fields = append(fields, textField{path: path + ".description", pointer: pointer + "/description", text: tool.Description})
+		fields = append(fields, schemaTextFields(path+".parameters", pointer+"/parameters", tool.Parameters.Properties)...)
+	}
+	for i, variable := range promptIR.Variables {
+		path, pointer := fmt.Sprintf("variables[%d]", i), fmt.Sprintf("/variables/%d", i)
+		fields = append(fields, textField{path: path + ".description", pointer: pointer + "/description", text: variable.Description})
+		if variable.Default != nil {
+			fields = append(fields, textField{path: path + ".default", pointer: pointer + "/default", text: *variable.Default})
+		}
+	}
+	fields = append(fields, schemaTextFields("input_schema", "/input_schema", promptIR.InputSchema.Properties)...)
+	fields = append(fields, schemaTextFields("output_schema", "/output_schema", promptIR.OutputSchema.Properties)...)
+	return fields
+}
+
+func schemaTextFields(path, pointer string, properties map[string]ir.Property) []textField {
+	var fields []textField
+	for _, name := range ir.SortedPropertyNames(properties) {
+		property := properties[name]
+		propertyPath := fmt.Sprintf("%s.properties.%s", path, name)
+		propertyPointer := fmt.Sprintf("%s/properties/%s", pointer, ir.EscapePointer(name))
+		fields = append(fields, textField{path: propertyPath + ".description", pointer: propertyPointer + "/description", text: property.Description})
+		fields = append(fields, schemaTextFields(propertyPath, propertyPointer, property.Properties)...)
+	}
+	return fields
+}
@@ -0,0 +1,168 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+
+	"github.com/promptforge/promptforge"
+	"github.com/promptforge/promptforge/internal/compiler"
+	"github.com/promptforge/promptforge/internal/ir"
+	"github.com/promptforge/promptforge/internal/linter"
+	"github.com/promptforge/promptforge/internal/parser"
+)
+
+// PlanPath returns the default plan location, promptforge/plan.md in projectDir.
+func PlanPath(projectDir string) string {
+	return filepath.Join(projectDir, "promptforge", "plan.md")
+}
+
+// contentPath resolves the path plan content stands for against projectDir.
+func contentPath(projectDir, planPath string) string {
+	if filepath.IsAbs(planPath) {
+		return planPath
+	}
+	return filepath.Join(projectDir, planPath)
+}
+
+// loadPlanSource reads plan.md, expands its include directives and merges it over
+// its base plan when it extends one.
+func loadPlanSource(planPath string) (*parser.Source, error) {
+	source, err := parser.LoadPlan(planPath, compiler.ItemIDs)
+	if err != nil {
+		if os.IsPermission(err) {
+			return nil, promptforge.Errorf(promptforge.CodePermissionDenied, "permission denied: cannot read plan.md at %s", planPath)
+		}
+		return nil, promptforge.Wrap(err, promptforge.IOCode(err), "failed to read plan.md at %s", planPath)
+	}
+	return source, nil
+}
+
+// includedFiles records the files included by source with their content digests.
+// Paths are stored relative to projectDir so the IR stays portable.
+func includedFiles(projectDir string, source *parser.Source) ([]ir.IncludedFile, error) {
+	if len(source.Includes) == 0 {
+		return nil, nil
+	}
+
+	files := make([]ir.IncludedFile, 0, len(source.Includes))
+	for _, path := range source.Includes {
+		digest, err := fileDigest(path)
+		if err != nil {
+			return nil, promptforge.Wrap(err, promptforge.IOCode(err), "failed to hash included file %s", path)
+		}
+		files = append(files, ir.IncludedFile{
+			Path:   projectRelative(projectDir, path),
+			SHA256: digest,
+		})
+	}
+	return files, nil
+}
+
+func fileDigest(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// projectRelative returns path relative to projectDir with forward slashes,
+// falling back to the cleaned path when it is outside the project.
+func projectRelative(projectDir, path string) string {
+	rel, err := filepath.Rel(projectDir, path)
+	if err != nil {
+		return filepath.ToSlash(filepath.Clean(path))
+	}
+	return filepath.ToSlash(rel)
+}
+
+// remapExplainSources points explain sources at the file each plan line came from
+// and, for plans that extend a base plan, marks whether it came from base or child.
+func remapExplainSources(report *compiler.ExplainReport, projectDir string, source *parser.Source) {
+	report.RemapSources(func(explainSource *compiler.ExplainSource) {
+		origin := source.Origin(explainSource.Line)
+		explainSource.Line = origin.Line
+		if origin.File != source.Path {
+			explainSource.File = projectRelative(projectDir, origin.File)
+		}
+		if len(source.Bases) > 0 {
+			explainSource.Origin = "child"
+			if origin.Plan != source.Path {
+				explainSource.Origin = "base"
+			}
+		}
+	})
+}
+
+// remapFix maps the edits of a fix from expanded lines to plan.md lines. It returns
+// nil when an edit touches lines from another file or spans an include, since such
+// a fix cannot be applied to plan.md.
+func remapFix(fix *linter.Fix, source *parser.Source) *linter.Fix {
+	if fix == nil {
+		return nil
+	}
+
+	// mapLine maps an expanded line to a plan.md line; a line just past the end of
+	// the expanded content maps to just past the line before it.
+	mapLine := func(line int) (int, bool) {
+		if line > len(source.Lines) && line > 1 {
+			origin := source.Origin(line - 1)
+			return origin.Line + 1, origin.File == source.Path
+		}
+		origin := source.Origin(line)
+		return origin.Line, origin.File == source.Path
+	}
+
+	remapped := &linter.Fix{Title: fix.Title, Edits: make([]linter.TextEdit, 0, len(fix.Edits))}
+	for _, edit := range fix.Edits {
+		start, ok := mapLine(edit.StartLine)
+		if !ok {
+			return nil
+		}
+		for line := edit.StartLine + 1; line <= edit.EndLine; line++ {
+			if mapped, ok := mapLine(line); !ok || mapped != start+line-edit.StartLine {
+				return nil
+			}
+		}
+		end := start + edit.EndLine - edit.StartLine
+		remapped.Edits = append(remapped.Edits, linter.TextEdit{StartLine: start, EndLine: end, NewLines: edit.NewLines})
+	}
+	return remapped
+}
+
+// remapDiagnostics points diagnostics at the file each plan line came from and
+// appends an error for every unresolved directive: PF110 for includes, PF111 for
+// extends and override directives.
+func remapDiagnostics(diagnostics []linter.Diagnostic, source *parser.Source) []linter.Diagnostic {
+	for i := range diagnostics {
+		origin := source.Origin(diagnostics[i].Line)
+		diagnostics[i].Line = origin.Line
+		if origin.File != source.Path {
+			diagnostics[i].File = origin.File
+		}
+		diagnostics[i].Fix = remapFix(diagnostics[i].Fix, source)
+	}
+
+	for _, sourceErr := range source.Errors {
+		code := "PF110"
+		if sourceErr.Kind == parser.SourceErrorExtends {
+			code = "PF111"
+		}
+		diag := linter.Diagnostic{
+			Severity: linter.SeverityError,
+			Code:     code,
+			Message:  sourceErr.Message,
+			Line:     sourceErr.Line,
+			Column:   1,
+		}
+		if sourceErr.File != source.Path {
+			diag.File = sourceErr.File
+		}
+		diagnostics = append(diagnostics, diag)
+	}
+
+	return diagnostics
+}
@@ -0,0 +1,139 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/promptforge/promptforge"
+	"github.com/promptforge/promptforge/internal/compiler"
+	"github.com/promptforge/promptforge/internal/ir"
+	"github.com/promptforge/promptforge/internal/linter"
+	"github.com/promptforge/promptforge/internal/templates"
+)
+
+// AddTemplate validates a template plan and copies it into the project template
+// directory (<project>/.promptforge/templates), or the user template directory
+// (~/.config/promptforge/templates) when user is true.
+//
+// Outputs:
+//   - Returns the added template, with Path set to its installed location
+//   - Returns error if:
+//   - the file cannot be read or is not a valid template plan
+//   - a template with the same name already exists in the target directory
+//   - the template cannot be written
+func AddTemplate(projectDir string, sourcePath string, user bool) (templates.Template, error) {
+	tmpl, err := templates.LoadFile(sourcePath)
+	if err != nil {
+		return templates.Template{}, err
+	}
+
+	targetDir := templates.ProjectDir(projectDir)
+	tmpl.Source = templates.SourceProject
+	if user {
+		targetDir, err = templates.UserDir()
+		if err != nil {
+			return templates.Template{}, err
+		}
+		tmpl.Source = templates.SourceUser
+	}
+
+	targetPath := filepath.Join(targetDir, tmpl.Name+".md")
+	if _, err := os.Stat(targetPath); err == nil {
+		return templates.Template{}, promptforge.Errorf(promptforge.CodeAlreadyExists, "template %s already exists at %s", tmpl.Name, targetPath)
+	}
+
+	content, err := os.ReadFile(sourcePath)
+	if err != nil {
+		return templates.Template{}, promptforge.Wrap(err, promptforge.IOCode(err), "failed to read template %s", sourcePath)
+	}
+
+	if err := os.MkdirAll(targetDir, 0755); err != nil {
+		if os.IsPermission(err) {
+			return templates.Template{}, promptforge.Errorf(promptforge.CodePermissionDenied, "permission denied: cannot create template directory %s", targetDir)
+		}
+		return templates.Template{}, promptforge.Wrap(err, promptforge.IOCode(err), "failed to create template directory %s", targetDir)
+	}
+
+	if err := os.WriteFile(targetPath, content, 0644); err != nil {
+		if os.IsPermission(err) {
+			return templates.Template{}, promptforge.Errorf(promptforge.CodePermissionDenied, "permission denied: cannot write template to %s", targetPath)
+		}
+		return templates.Template{}, promptforge.Wrap(err, promptforge.IOCode(err), "failed to write template to %s", targetPath)
+	}
+
+	tmpl.Path = targetPath
+	return tmpl, nil
+}
+
+// TemplateCheck is the result of verifying a single template.
+type TemplateCheck struct {
+	Template templates.Template
+
+	// Diagnostics are the lint findings for the rendered template plan.
+	Diagnostics []linter.Diagnostic
+
+	// IR is the compiled template, when compilation succeeded.
+	IR *ir.PromptIR
+
+	// Err is the first failure: loading, rendering, lint errors, compilation or IR
+	// validation.
+	Err error
+}
+
+// VerifyTemplates verifies every template available to the project: built-ins,
+// user templates and project templates.
+func VerifyTemplates(projectDir string) ([]TemplateCheck, error) {
+	catalog, err := templates.Catalog(projectDir)
+	if err != nil {
+		return nil, err
+	}
+
+	checks := make([]TemplateCheck, 0, len(catalog))
+	for _, tmpl := range catalog {
+		checks = append(checks, VerifyTemplate(tmpl))
+	}
+	return checks, nil
+}
+
+// VerifyTemplate renders a template with sample parameter values, lints it, compiles
+// it and validates the resulting IR against the IR schema. A template that failed
+// to load fails with its load error.
+func VerifyTemplate(tmpl templates.Template) TemplateCheck {
+	check := TemplateCheck{Template: tmpl}
+	if tmpl.Err != nil {
+		check.Err = tmpl.Err
+		return check
+	}
+
+	content, err := tmpl.Render(tmpl.SampleParams())
+	if err != nil {
+		check.Err = promptforge.Wrap(err, promptforge.CodeVariable, "render failed")
+		return check
+	}
+
+	check.Diagnostics = linter.LintPlan([]byte(content))
+	var lintErrors int
+	for _, diag := range check.Diagnostics {
+		if diag.Severity == linter.SeverityError {
+			lintErrors++
+		}
+	}
+	if lintErrors > 0 {
+		check.Err = promptforge.Errorf(promptforge.CodeFindings, "lint failed with %d error(s)", lintErrors)
+		return check
+	}
+
+	compiled, err := compiler.Compile([]byte(content))
+	if err != nil {
+		check.Err = fmt.Errorf("compilation failed: %w", err)
+		return check
+	}
+	if err := compiler.ValidateIR(compiled); err != nil {
+		check.Err = promptforge.Wrap(err, promptforge.CodeIRInvalid, "IR validation failed")
+		return check
+	}
+	check.IR = compiled
+
+	return check
+}
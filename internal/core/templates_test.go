@@ -0,0 +1,173 @@
+package core
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/promptforge/promptforge/internal/templates"
+)
+
+func TestAddTemplate_ProjectTemplateUsedByInit(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	projectDir := t.TempDir()
+
+	source := filepath.Join(t.TempDir(), "triage.md")
+	content := "---\nname: code-review\ndescription: Team review checklist\n---\n# Prompt Plan\n\n## Goal\nReview pull requests against the team checklist.\n\n## Constraints\n- Check the changelog entry\n"
+	if err := os.WriteFile(source, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+
+	tmpl, err := AddTemplate(projectDir, source, false)
+	if err != nil {
+		t.Fatalf("AddTemplate() failed: %v", err)
+	}
+	wantPath := filepath.Join(projectDir, ".promptforge", "templates", "code-review.md")
+	if tmpl.Path != wantPath {
+		t.Errorf("Expected template at %s, got %s", wantPath, tmpl.Path)
+	}
+
+	if _, err := AddTemplate(projectDir, source, false); err == nil {
+		t.Error("Adding the same template twice should fail")
+	}
+
+	if err := InitializeProjectWithTemplate(projectDir, "", "code-review"); err != nil {
+		t.Fatalf("InitializeProjectWithTemplate() failed: %v", err)
+	}
+	plan, err := os.ReadFile(filepath.Join(projectDir, "promptforge", "plan.md"))
+	if err != nil {
+		t.Fatalf("Failed to read plan.md: %v", err)
+	}
+	if !strings.Contains(string(plan), "Check the changelog entry") {
+		t.Errorf("Project template should shadow the built-in, got:\n%s", plan)
+	}
+	if strings.Contains(string(plan), "description:") {
+		t.Errorf("Template front matter should not be copied into plan.md:\n%s", plan)
+	}
+}
+
+func TestInitializeProjectWithParams_MissingParams(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	projectDir := t.TempDir()
+
+	err := InitializeProjectWithParams(projectDir, "", "triage", map[string]string{"domain": "Payments"})
+	if err == nil || !strings.Contains(err.Error(), "missing required parameters for template triage: categories") {
+		t.Fatalf("Expected missing parameter error, got %v", err)
+	}
+	if _, statErr := os.Stat(filepath.Join(projectDir, "promptforge", "plan.md")); !os.IsNotExist(statErr) {
+		t.Error("plan.md should not be written when parameters are missing")
+	}
+
+	if err := InitializeProjectWithParams(projectDir, "", "triage", map[string]string{"domain": "Payments", "categories": "bug,billing"}); err != nil {
+		t.Fatalf("InitializeProjectWithParams() failed: %v", err)
+	}
+	plan, err := os.ReadFile(filepath.Join(projectDir, "promptforge", "plan.md"))
+	if err != nil {
+		t.Fatalf("Failed to read plan.md: %v", err)
+	}
+	if !strings.Contains(string(plan), "exactly one of: bug, billing") {
+		t.Errorf("Template parameters were not filled in:\n%s", plan)
+	}
+}
+
+// TestVerifyTemplate_BuiltinGolden lints, compiles and validates every built-in
+// template and compares its IR with testdata/templates/<name>.ir.json.
+func TestVerifyTemplate_BuiltinGolden(t *testing.T) {
+	for _, tmpl := range templates.List() {
+		t.Run(tmpl.Name, func(t *testing.T) {
+			check := VerifyTemplate(tmpl)
+			if check.Err != nil {
+				t.Fatalf("VerifyTemplate() failed: %v (diagnostics: %+v)", check.Err, check.Diagnostics)
+			}
+
+			expectedPath := filepath.Join("testdata", "templates", tmpl.Name+".ir.json")
+			expected, err := os.ReadFile(expectedPath)
+			if err != nil {
+				t.Fatalf("Failed to read golden IR: %v", err)
+			}
+			actual, err := json.MarshalIndent(check.IR, "", "  ")
+			if err != nil {
+				t.Fatalf("Failed to marshal IR: %v", err)
+			}
+			if string(expected) != string(actual)+"\n" {
+				t.Fatalf("Golden mismatch. Update %s if this change is expected.", expectedPath)
+			}
+		})
+	}
+}
+
+func TestVerifyTemplates_ReportsBrokenUserTemplate(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	userDir := filepath.Join(home, ".config", "promptforge", "templates")
+	if err := os.MkdirAll(userDir, 0755); err != nil {
+		t.Fatalf("Failed to create user template directory: %v", err)
+	}
+	broken := "---\nname: broken\n---\n## Goal\nSummarize {{ticket}} for the on-call engineer.\n\n## Tone\n- Friendly\n"
+	if err := os.WriteFile(filepath.Join(userDir, "broken.md"), []byte(broken), 0644); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+
+	checks, err := VerifyTemplates(t.TempDir())
+	if err != nil {
+		t.Fatalf("VerifyTemplates() failed: %v", err)
+	}
+
+	var found bool
+	for _, check := range checks {
+		if check.Template.Name != "broken" {
+			if check.Err != nil {
+				t.Errorf("Template %s should verify: %v", check.Template.Name, check.Err)
+			}
+			continue
+		}
+		found = true
+		if check.Err == nil || !strings.Contains(check.Err.Error(), "lint failed with 2 error(s)") {
+			t.Errorf("Expected lint failure, got %v (%+v)", check.Err, check.Diagnostics)
+		}
+	}
+	if !found {
+		t.Fatal("User template was not verified")
+	}
+}
+
+func TestVerifyTemplates_ReportsUnloadableProjectTemplate(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	projectDir := t.TempDir()
+
+	projectTemplates := filepath.Join(projectDir, ".promptforge", "templates")
+	if err := os.MkdirAll(projectTemplates, 0755); err != nil {
+		t.Fatalf("Failed to create project template directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(projectTemplates, "nogoal.md"), []byte("## Constraints\n- Must return JSON only\n"), 0644); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+
+	checks, err := VerifyTemplates(projectDir)
+	if err != nil {
+		t.Fatalf("VerifyTemplates() should not fail on an invalid template: %v", err)
+	}
+
+	var found bool
+	for _, check := range checks {
+		if check.Template.Name != "nogoal" {
+			if check.Err != nil {
+				t.Errorf("Template %s should verify: %v", check.Template.Name, check.Err)
+			}
+			continue
+		}
+		found = true
+		if check.Template.Source != templates.SourceProject {
+			t.Errorf("Expected a project template, got source %q", check.Template.Source)
+		}
+		if check.Err == nil || !strings.Contains(check.Err.Error(), "Goal section is required") {
+			t.Errorf("Expected the load error, got %v", check.Err)
+		}
+	}
+	if !found {
+		t.Fatal("Invalid project template was not reported")
+	}
+}
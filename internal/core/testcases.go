@@ -0,0 +1,249 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/promptforge/promptforge"
+	"github.com/promptforge/promptforge/internal/compiler"
+	"github.com/promptforge/promptforge/internal/contract"
+	"github.com/promptforge/promptforge/internal/ir"
+)
+
+// ExpectPass is the expected outcome of a test case whose response satisfies the contract.
+const ExpectPass = "pass"
+
+// outcomeFail is the outcome of a response that breaks the contract.
+const outcomeFail = "fail"
+
+// TestCase is a recorded model exchange read from promptforge/tests/*.yaml:
+//
+//	name: refund for a known order
+//	input:
+//	  order_id: A-1001
+//	response: |
+//	  {"decision": "refund"}
+//	expect: pass
+//
+// Expect is "pass" or the ID of the failure mode the response must report.
+type TestCase struct {
+	Name string `yaml:"name"`
+
+	// Input is the payload sent to the model; it is checked against input_schema
+	// when present.
+	Input interface{} `yaml:"input"`
+
+	// Response is the recorded model response. A YAML mapping or list is treated
+	// as the equivalent JSON document.
+	Response interface{} `yaml:"response"`
+
+	Expect string `yaml:"expect"`
+}
+
+// TestResult is the outcome of one test case.
+type TestResult struct {
+	Name string `json:"name"`
+
+	// File is the case file relative to the project directory.
+	File string `json:"file"`
+
+	Expect string `json:"expect"`
+
+	// Outcome is "pass", "fail", or the failure mode the response reported.
+	Outcome string `json:"outcome"`
+
+	// Violations lists how the input or response breaks the contract.
+	Violations []contract.Violation `json:"violations"`
+
+	Passed bool `json:"passed"`
+}
+
+// TestReport holds the results of a test run, in file order.
+type TestReport struct {
+	Results []TestResult `json:"results"`
+}
+
+// Failed returns the number of failing test cases.
+func (r *TestReport) Failed() int {
+	failed := 0
+	for _, result := range r.Results {
+		if !result.Passed {
+			failed++
+		}
+	}
+	return failed
+}
+
+// RunTests checks every recorded case in promptforge/tests against prompt.ir.json.
+// No model is called: each recorded response is validated against output_schema
+// and the machine-checkable rules, and its outcome compared with the expected one.
+func RunTests(projectDir string) (*TestReport, error) {
+	if projectDir == "" {
+		return nil, promptforge.Errorf(promptforge.CodeInvalidArgument, "project directory cannot be empty")
+	}
+
+	if _, err := os.Stat(projectDir); os.IsNotExist(err) {
+		return nil, promptforge.Errorf(promptforge.CodeNotFound, "project directory does not exist: %s", projectDir)
+	}
+
+	promptIR, err := readPromptIR(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	if err := compiler.ValidateIR(promptIR); err != nil {
+		return nil, promptforge.Wrap(err, promptforge.CodeIRInvalid, "IR validation failed")
+	}
+
+	validator, err := contract.NewValidator(promptIR)
+	if err != nil {
+		return nil, err
+	}
+
+	testsDir := filepath.Join(projectDir, "promptforge", "tests")
+	paths, err := testCaseFiles(testsDir)
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, promptforge.Errorf(promptforge.CodeNotFound, "no test cases found in %s", testsDir)
+	}
+
+	report := &TestReport{}
+	for _, path := range paths {
+		testCase, err := readTestCase(path)
+		if err != nil {
+			return nil, err
+		}
+		rel := projectRelative(projectDir, path)
+		if testCase.Name == "" {
+			testCase.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		}
+		if testCase.Expect != ExpectPass && !validator.HasFailureMode(testCase.Expect) {
+			return nil, promptforge.Errorf(promptforge.CodeInvalidArgument, "invalid test case %s: expect %q is neither %q nor a failure mode declared in prompt.ir.json", rel, testCase.Expect, ExpectPass)
+		}
+
+		result, err := evaluateTestCase(validator, testCase)
+		if err != nil {
+			return nil, promptforge.Wrap(err, promptforge.CodeInvalidArgument, "invalid test case %s", rel)
+		}
+		result.File = rel
+		report.Results = append(report.Results, result)
+	}
+	return report, nil
+}
+
+// evaluateTestCase decides the outcome of one recorded response.
+func evaluateTestCase(validator *contract.Validator, testCase TestCase) (TestResult, error) {
+	result := TestResult{Name: testCase.Name, Expect: testCase.Expect}
+
+	response, err := responseBytes(testCase.Response)
+	if err != nil {
+		return result, err
+	}
+
+	result.Outcome, result.Violations = checkResponse(validator, testCase.Input, response)
+	result.Passed = result.Outcome == testCase.Expect && len(result.Violations) == 0
+	return result, nil
+}
+
+// checkResponse validates a model response to input and returns its outcome:
+// "pass", "fail", or the failure mode the response reported. When the input
+// itself breaks input_schema, the response must report the matching failure mode.
+// A nil input is not checked.
+func checkResponse(validator *contract.Validator, input interface{}, response []byte) (string, []contract.Violation) {
+	output := validator.ValidateOutput(response)
+	violations := output.Violations
+
+	var outcome string
+	switch {
+	case output.FailureMode != "":
+		outcome = output.FailureMode
+	case len(output.Violations) > 0:
+		outcome = outcomeFail
+	default:
+		outcome = ExpectPass
+	}
+
+	if input != nil {
+		inputViolations := validator.ValidateInput(input)
+		if len(inputViolations) > 0 && output.FailureMode != inputViolations[0].ID {
+			violations = append(violations, inputViolations...)
+		}
+	}
+	return outcome, violations
+}
+
+// responseBytes returns the raw response text of a test case.
+func responseBytes(response interface{}) ([]byte, error) {
+	switch r := response.(type) {
+	case nil:
+		return nil, promptforge.Errorf(promptforge.CodeInvalidArgument, "response is required")
+	case string:
+		return []byte(r), nil
+	default:
+		data, err := json.Marshal(r)
+		if err != nil {
+			return nil, promptforge.Wrap(err, promptforge.CodeInvalidArgument, "response is not representable as JSON")
+		}
+		return data, nil
+	}
+}
+
+func testCaseFiles(testsDir string) ([]string, error) {
+	entries, err := os.ReadDir(testsDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		if os.IsPermission(err) {
+			return nil, promptforge.Errorf(promptforge.CodePermissionDenied, "permission denied: cannot read tests directory at %s", testsDir)
+		}
+		return nil, promptforge.Wrap(err, promptforge.IOCode(err), "failed to read tests directory at %s", testsDir)
+	}
+
+	var paths []string
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		paths = append(paths, filepath.Join(testsDir, entry.Name()))
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+func readTestCase(path string) (TestCase, error) {
+	var testCase TestCase
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsPermission(err) {
+			return testCase, promptforge.Errorf(promptforge.CodePermissionDenied, "permission denied: cannot read test case at %s", path)
+		}
+		return testCase, promptforge.Wrap(err, promptforge.IOCode(err), "failed to read test case at %s", path)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&testCase); err != nil && !errors.Is(err, io.EOF) {
+		return testCase, promptforge.Wrap(err, promptforge.CodeInvalidArgument, "failed to parse test case at %s", path)
+	}
+	if testCase.Expect == "" {
+		return testCase, promptforge.Errorf(promptforge.CodeInvalidArgument, "invalid test case at %s: expect is required (%q or a failure mode ID)", path, ExpectPass)
+	}
+	return testCase, nil
+}
+
+// readPromptIR reads and parses prompt.ir.json from the project directory.
+func readPromptIR(projectDir string) (*ir.PromptIR, error) {
+	return readIRFile(filepath.Join(projectDir, "prompt.ir.json"))
+}
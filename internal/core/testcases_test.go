@@ -0,0 +1,201 @@
+package core
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/promptforge/promptforge/internal/ir"
+)
+
+// writeTestProject writes a prompt.ir.json with a refund contract and the given
+// test case files into a temporary project.
+func writeTestProject(t *testing.T, cases map[string]string) string {
+	t.Helper()
+	tmpDir := t.TempDir()
+
+	promptIR := ir.PromptIR{
+		Version:    ir.CurrentVersion,
+		SystemRole: "You triage refund requests.",
+		Rules: []ir.Rule{
+			{ID: "output-json", Description: "Output must be valid JSON"},
+			{ID: "no-explanations", Description: "Do not include explanations unless explicitly requested"},
+		},
+		InputSchema: ir.Schema{
+			Type:       "object",
+			Properties: map[string]ir.Property{"order_id": {Type: "string"}},
+			Required:   []string{"order_id"},
+		},
+		OutputSchema: ir.Schema{
+			Type: "object",
+			Properties: map[string]ir.Property{
+				"decision": {Type: "string", Enum: []interface{}{"refund", "deny"}},
+			},
+			Required: []string{"decision"},
+		},
+		FailureModes: []ir.FailureMode{
+			{ID: "invalid-input", Condition: "Input does not match input_schema", Response: "Return error"},
+			{ID: "missing-required", Condition: "Required fields are missing", Response: "Return error"},
+		},
+	}
+	data, err := json.MarshalIndent(promptIR, "", "  ")
+	if err != nil {
+		t.Fatalf("Failed to marshal IR: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "prompt.ir.json"), data, 0644); err != nil {
+		t.Fatalf("Failed to write prompt.ir.json: %v", err)
+	}
+
+	testsDir := filepath.Join(tmpDir, "promptforge", "tests")
+	if err := os.MkdirAll(testsDir, 0755); err != nil {
+		t.Fatalf("Failed to create tests directory: %v", err)
+	}
+	for name, content := range cases {
+		if err := os.WriteFile(filepath.Join(testsDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	return tmpDir
+}
+
+func TestRunTests_Outcomes(t *testing.T) {
+	tmpDir := writeTestProject(t, map[string]string{
+		"01-refund.yaml": `name: refund
+input:
+  order_id: A-1
+response: |
+  {"decision": "refund"}
+expect: pass
+`,
+		"02-mapping.yaml": `input:
+  order_id: A-2
+response:
+  decision: deny
+expect: pass
+`,
+		"03-missing.yaml": `name: missing order
+input: {}
+response: '{"error": "missing-required"}'
+expect: missing-required
+`,
+		"04-regression.yaml": `name: chatty
+input:
+  order_id: A-3
+response: '{"decision": "maybe"} Let me know!'
+expect: pass
+`,
+		"05-answered-invalid.yaml": `name: answered invalid input
+input: {}
+response: '{"decision": "deny"}'
+expect: pass
+`,
+		"notes.txt": "ignored",
+	})
+
+	report, err := RunTests(tmpDir)
+	if err != nil {
+		t.Fatalf("RunTests() failed: %v", err)
+	}
+	if len(report.Results) != 5 {
+		t.Fatalf("Expected 5 results, got %d", len(report.Results))
+	}
+
+	want := []struct {
+		name    string
+		outcome string
+		passed  bool
+	}{
+		{"refund", "pass", true},
+		{"02-mapping", "pass", true},
+		{"missing order", "missing-required", true},
+		{"chatty", "fail", false},
+		{"answered invalid input", "pass", false},
+	}
+	for i, w := range want {
+		result := report.Results[i]
+		if result.Name != w.name || result.Outcome != w.outcome || result.Passed != w.passed {
+			t.Errorf("Results[%d] = {%q %q passed=%v}, want {%q %q passed=%v}", i, result.Name, result.Outcome, result.Passed, w.name, w.outcome, w.passed)
+		}
+	}
+	if report.Failed() != 2 {
+		t.Errorf("Failed() = %d, want 2", report.Failed())
+	}
+
+	chatty := report.Results[3]
+	if len(chatty.Violations) != 2 || chatty.Violations[0].ID != "no-explanations" || chatty.Violations[1].ID != "output-schema" {
+		t.Errorf("Expected no-explanations and output-schema violations, got %v", chatty.Violations)
+	}
+	if report.Results[4].Violations[0].ID != "missing-required" {
+		t.Errorf("Expected the input violation to be reported, got %v", report.Results[4].Violations)
+	}
+}
+
+func TestRunTests_InvalidCases(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "missing expect", content: "response: '{}'\n", wantErr: "expect is required"},
+		{name: "unknown expect", content: "response: '{}'\nexpect: timeout\n", wantErr: `expect "timeout"`},
+		{name: "unknown field", content: "response: '{}'\nexpected: pass\n", wantErr: "failed to parse test case"},
+		{name: "missing response", content: "expect: pass\n", wantErr: "response is required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := writeTestProject(t, map[string]string{"case.yaml": tt.content})
+			_, err := RunTests(tmpDir)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestRunTests_NoCases(t *testing.T) {
+	tmpDir := writeTestProject(t, nil)
+
+	if _, err := RunTests(tmpDir); err == nil || !strings.Contains(err.Error(), "no test cases found") {
+		t.Fatalf("Expected a no test cases error, got %v", err)
+	}
+}
+
+func TestJUnitXML(t *testing.T) {
+	tmpDir := writeTestProject(t, map[string]string{
+		"pass.yaml": "response: '{\"decision\": \"refund\"}'\nexpect: pass\n",
+		"fail.yaml": "response: 'not json'\nexpect: pass\n",
+	})
+
+	report, err := RunTests(tmpDir)
+	if err != nil {
+		t.Fatalf("RunTests() failed: %v", err)
+	}
+	data, err := JUnitXML(report)
+	if err != nil {
+		t.Fatalf("JUnitXML() failed: %v", err)
+	}
+
+	var doc junitTestSuites
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("JUnitXML() produced invalid XML: %v\n%s", err, data)
+	}
+	if doc.Tests != 2 || doc.Failures != 1 {
+		t.Errorf("Expected 2 tests and 1 failure, got %d and %d", doc.Tests, doc.Failures)
+	}
+	cases := doc.Suites[0].Cases
+	if cases[0].Name != "fail" || cases[0].Failure == nil {
+		t.Fatalf("Expected the first case to be the failing one, got %+v", cases[0])
+	}
+	if cases[0].Failure.Message != "expected pass, got fail" || !strings.Contains(cases[0].Failure.Text, "output-json") {
+		t.Errorf("Unexpected failure details: %+v", cases[0].Failure)
+	}
+	if cases[1].Failure != nil {
+		t.Errorf("Expected the passing case to have no failure, got %+v", cases[1].Failure)
+	}
+	if cases[1].File != "promptforge/tests/pass.yaml" {
+		t.Errorf("File = %q, want promptforge/tests/pass.yaml", cases[1].File)
+	}
+}
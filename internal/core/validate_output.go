@@ -0,0 +1,77 @@
+package core
+
+import (
+	"os"
+
+	"github.com/promptforge/promptforge"
+	"github.com/promptforge/promptforge/internal/compiler"
+	"github.com/promptforge/promptforge/internal/contract"
+	"github.com/promptforge/promptforge/internal/ir"
+	"github.com/promptforge/promptforge/internal/repair"
+)
+
+// OutputReport is the result of ValidateOutput.
+type OutputReport struct {
+	// Output is the checked response: the repaired one when repairs were applied.
+	Output []byte
+
+	// Applied and Refused list the repairs made and declined (only with repair set).
+	Applied []repair.Change
+	Refused []repair.Change
+
+	// FailureMode is set when the response reports a failure mode.
+	FailureMode string
+
+	Violations []contract.Violation
+}
+
+// ValidateOutput checks a model response against prompt.ir.json. With repair set,
+// safe deterministic repairs are applied first and the repaired response is checked.
+func ValidateOutput(projectDir string, response []byte, repairResponse bool) (*OutputReport, error) {
+	if projectDir == "" {
+		return nil, promptforge.Errorf(promptforge.CodeInvalidArgument, "project directory cannot be empty")
+	}
+
+	if _, err := os.Stat(projectDir); os.IsNotExist(err) {
+		return nil, promptforge.Errorf(promptforge.CodeNotFound, "project directory does not exist: %s", projectDir)
+	}
+
+	promptIR, err := readPromptIR(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	return ValidateOutputIR(promptIR, response, repairResponse)
+}
+
+// ValidateOutputIR checks a model response against promptIR, like ValidateOutput.
+func ValidateOutputIR(promptIR *ir.PromptIR, response []byte, repairResponse bool) (*OutputReport, error) {
+	if err := compiler.ValidateIR(promptIR); err != nil {
+		return nil, promptforge.Wrap(err, promptforge.CodeIRInvalid, "IR validation failed")
+	}
+
+	if repairResponse {
+		repairer, err := repair.New(promptIR)
+		if err != nil {
+			return nil, err
+		}
+		result := repairer.Repair(response)
+		return &OutputReport{
+			Output:      result.Output,
+			Applied:     result.Applied,
+			Refused:     result.Refused,
+			FailureMode: result.FailureMode,
+			Violations:  result.Violations,
+		}, nil
+	}
+
+	validator, err := contract.NewValidator(promptIR)
+	if err != nil {
+		return nil, err
+	}
+	out := validator.ValidateOutput(response)
+	return &OutputReport{
+		Output:      response,
+		FailureMode: out.FailureMode,
+		Violations:  out.Violations,
+	}, nil
+}
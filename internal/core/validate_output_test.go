@@ -0,0 +1,39 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateOutput(t *testing.T) {
+	tmpDir := writeTestProject(t, nil)
+	fenced := []byte("```json\n{\"decision\": \"Refund\"}\n```")
+
+	report, err := ValidateOutput(tmpDir, fenced, false)
+	if err != nil {
+		t.Fatalf("ValidateOutput() failed: %v", err)
+	}
+	if len(report.Violations) == 0 || len(report.Applied) != 0 {
+		t.Errorf("Expected violations and no repairs without --repair, got %+v", report)
+	}
+
+	report, err = ValidateOutput(tmpDir, fenced, true)
+	if err != nil {
+		t.Fatalf("ValidateOutput() with repair failed: %v", err)
+	}
+	if len(report.Violations) != 0 {
+		t.Errorf("Expected the repaired response to satisfy the contract, got %v", report.Violations)
+	}
+	if string(report.Output) != `{"decision":"refund"}` {
+		t.Errorf("Unexpected repaired output %s", report.Output)
+	}
+	if len(report.Applied) != 2 {
+		t.Errorf("Expected a fence and an enum repair, got %v", report.Applied)
+	}
+}
+
+func TestValidateOutput_MissingIR(t *testing.T) {
+	if _, err := ValidateOutput(t.TempDir(), []byte(`{}`), true); err == nil || !strings.Contains(err.Error(), "prompt.ir.json") {
+		t.Errorf("Expected a missing prompt.ir.json error, got %v", err)
+	}
+}
@@ -0,0 +1,35 @@
+package formatter
+
+import "testing"
+
+func TestDiff(t *testing.T) {
+	a := []byte("1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n")
+	b := []byte("1\n2\n3\n4\nfive\n6\n7\n8\n9\n10\n11\n12\n13\n")
+
+	want := `--- a
++++ b
+@@ -2,7 +2,7 @@
+ 2
+ 3
+ 4
+-5
++five
+ 6
+ 7
+ 8
+@@ -10,3 +10,4 @@
+ 10
+ 11
+ 12
++13
+`
+	if got := Diff("a", "b", a, b); got != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestDiff_Equal(t *testing.T) {
+	if got := Diff("a", "b", []byte("same\n"), []byte("same\n")); got != "" {
+		t.Fatalf("expected no diff, got:\n%s", got)
+	}
+}
@@ -0,0 +1,260 @@
+// Package formatter rewrites plan.md into a canonical form.
+package formatter
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/promptforge/promptforge/internal/parser"
+)
+
+// Width is the column at which long Constraints and Out of Scope items are wrapped.
+const Width = 80
+
+// canonicalSections lists the known sections in canonical order and spelling.
+var canonicalSections = []string{"Goal", "Constraints", "Out of Scope", "Tools", "Variables"}
+
+var (
+	headingRe    = regexp.MustCompile(`^##\s+(.+?)\s*$`)
+	listMarkerRe = regexp.MustCompile(`^(?:[-*•]|\d+\.)\s+`)
+	spaceRe      = regexp.MustCompile(`\s+`)
+)
+
+type section struct {
+	name    string
+	key     string
+	body    []line
+	trailer []line
+}
+
+// line is a plan line with its comment-stripped counterpart.
+type line struct {
+	raw      string
+	stripped string
+}
+
+// isComment reports whether the line holds only (part of) an HTML comment.
+func (l line) isComment() bool {
+	return strings.TrimSpace(l.stripped) == "" && strings.TrimSpace(l.raw) != ""
+}
+
+func (l line) isBlank() bool {
+	return strings.TrimSpace(l.raw) == ""
+}
+
+// Format returns plan content in canonical form:
+//
+//   - front matter and the preamble before the first section are kept as-is
+//   - known sections appear in the order Goal, Constraints, Out of Scope, Tools,
+//     Variables, with canonical heading case; other sections follow in file order
+//   - list items use "-" bullets, duplicates are dropped and Constraints and
+//     Out of Scope items longer than Width are wrapped onto indented lines
+//   - blank lines are collapsed, with one blank line between sections
+//   - comments are preserved, and text after a "---" separator moves to the end
+//
+// Formatting does not change the compiled IR.
+func Format(content []byte) []byte {
+	text := strings.ReplaceAll(string(content), "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+	rawLines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	for i := range rawLines {
+		rawLines[i] = strings.TrimRight(rawLines[i], " \t")
+	}
+
+	var frontMatter []string
+	if fm := parser.ParseFrontMatter([]byte(text)); fm != nil {
+		frontMatter = rawLines[:fm.EndLine]
+		rawLines = rawLines[fm.EndLine:]
+	}
+
+	stripped := parser.StripComments(rawLines)
+	lines := make([]line, len(rawLines))
+	for i := range rawLines {
+		lines[i] = line{raw: rawLines[i], stripped: stripped[i]}
+	}
+
+	var preamble []line
+	var sections []*section
+	for _, l := range lines {
+		if match := headingRe.FindStringSubmatch(strings.TrimSpace(l.stripped)); match != nil {
+			name := spaceRe.ReplaceAllString(match[1], " ")
+			sections = append(sections, &section{name: name, key: strings.ToLower(name)})
+			continue
+		}
+		if len(sections) == 0 {
+			preamble = append(preamble, l)
+			continue
+		}
+		current := sections[len(sections)-1]
+		if current.trailer != nil || strings.TrimSpace(l.stripped) == "---" {
+			current.trailer = append(current.trailer, l)
+			continue
+		}
+		current.body = append(current.body, l)
+	}
+
+	var out []string
+	out = append(out, frontMatter...)
+	if block := formatBlock(preamble, false); len(block) > 0 {
+		out = append(out, block...)
+	}
+
+	var trailers []string
+	for _, s := range orderSections(sections) {
+		if len(out) > 0 && out[len(out)-1] != "" {
+			out = append(out, "")
+		}
+		out = append(out, "## "+s.name)
+		switch s.key {
+		case "constraints", "out of scope":
+			out = append(out, formatList(s.body)...)
+		case "tools", "variables":
+			out = append(out, formatBlock(s.body, true)...)
+		default:
+			out = append(out, formatBlock(s.body, false)...)
+		}
+		if block := formatBlock(s.trailer, false); len(block) > 0 {
+			if len(trailers) > 0 {
+				trailers = append(trailers, "")
+			}
+			trailers = append(trailers, block...)
+		}
+	}
+
+	if len(trailers) > 0 {
+		out = append(out, "")
+		out = append(out, trailers...)
+	}
+
+	return []byte(strings.Join(out, "\n") + "\n")
+}
+
+// orderSections returns known sections in canonical order, each followed by any
+// duplicates of it, and then unknown sections in file order.
+func orderSections(sections []*section) []*section {
+	ordered := make([]*section, 0, len(sections))
+	for _, name := range canonicalSections {
+		key := strings.ToLower(name)
+		for _, s := range sections {
+			if s.key == key {
+				s.name = name
+				ordered = append(ordered, s)
+			}
+		}
+	}
+	for _, s := range sections {
+		if !isKnown(s.key) {
+			ordered = append(ordered, s)
+		}
+	}
+	return ordered
+}
+
+func isKnown(key string) bool {
+	for _, name := range canonicalSections {
+		if strings.ToLower(name) == key {
+			return true
+		}
+	}
+	return false
+}
+
+// formatBlock trims leading and trailing blank lines and collapses blank runs.
+// When bullets is true, list markers outside comments are rewritten to "-".
+func formatBlock(lines []line, bullets bool) []string {
+	var out []string
+	blank := false
+	for _, l := range lines {
+		if l.isBlank() {
+			blank = len(out) > 0
+			continue
+		}
+		if blank {
+			out = append(out, "")
+			blank = false
+		}
+		text := l.raw
+		if bullets && !l.isComment() {
+			trimmed := strings.TrimLeft(text, " \t")
+			if marker := listMarkerRe.FindString(trimmed); marker != "" {
+				text = text[:len(text)-len(trimmed)] + "- " + trimmed[len(marker):]
+			}
+		}
+		out = append(out, text)
+	}
+	return out
+}
+
+// formatList rewrites a Constraints or Out of Scope body: one "-" item per entry,
+// wrapped items joined and rewrapped, duplicates dropped and comments kept in place.
+func formatList(lines []line) []string {
+	type item struct {
+		text    string
+		comment bool
+	}
+
+	var items []item
+	continuable := false
+	for _, l := range lines {
+		switch {
+		case l.isBlank():
+			continuable = false
+		case l.isComment():
+			items = append(items, item{text: l.raw, comment: true})
+			continuable = false
+		default:
+			text := strings.TrimSpace(l.raw)
+			marked := listMarkerRe.MatchString(text)
+			indented := strings.HasPrefix(l.raw, " ") || strings.HasPrefix(l.raw, "\t")
+			if continuable && indented && !marked {
+				items[len(items)-1].text += " " + text
+				continue
+			}
+			items = append(items, item{text: listMarkerRe.ReplaceAllString(text, "")})
+			continuable = true
+		}
+	}
+
+	var out []string
+	seen := make(map[string]bool)
+	for _, it := range items {
+		if it.comment {
+			out = append(out, it.text)
+			continue
+		}
+		key := strings.ToLower(strings.TrimSpace(parser.StripComments([]string{it.text})[0]))
+		if key != "" {
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+		}
+		out = append(out, wrapItem(it.text)...)
+	}
+	return out
+}
+
+// wrapItem renders a list item as "- text", wrapping at Width with two-space
+// continuation lines. A line never starts with a word that reads as a list marker,
+// and comments are never split.
+func wrapItem(text string) []string {
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return []string{"-"}
+	}
+	if strings.Contains(text, "<!--") {
+		return []string{"- " + strings.Join(words, " ")}
+	}
+
+	var lines []string
+	current := "- " + words[0]
+	for _, word := range words[1:] {
+		if len(current)+1+len(word) > Width && !listMarkerRe.MatchString(word+" ") {
+			lines = append(lines, current)
+			current = "  " + word
+			continue
+		}
+		current += " " + word
+	}
+	return append(lines, current)
+}
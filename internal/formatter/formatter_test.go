@@ -0,0 +1,113 @@
+package formatter
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/promptforge/promptforge/internal/compiler"
+)
+
+const messyPlan = `# Prompt Plan
+
+
+## out of scope
+* Legal advice
+* legal advice
+
+##   Goal
+Answer billing questions for Acme customers.
+<!-- keep this short -->
+
+## constraints
+1. Reply in English
+* Never share account numbers or card details with anyone, including the account holder on an unverified channel
+- Must ask a clarifying question when
+  the category is unclear
+- reply in english
+
+<!-- TODO: add tone guidance
+     once legal signs off -->
+
+## Notes
+Free-form notes stay put.
+
+## Variables
+* customer_name (string, required): Customer's name
+
+---
+*Generated by promptforge init*
+`
+
+const canonicalPlan = `# Prompt Plan
+
+## Goal
+Answer billing questions for Acme customers.
+<!-- keep this short -->
+
+## Constraints
+- Reply in English
+- Never share account numbers or card details with anyone, including the account
+  holder on an unverified channel
+- Must ask a clarifying question when the category is unclear
+<!-- TODO: add tone guidance
+     once legal signs off -->
+
+## Out of Scope
+- Legal advice
+
+## Variables
+- customer_name (string, required): Customer's name
+
+## Notes
+Free-form notes stay put.
+
+---
+*Generated by promptforge init*
+`
+
+func TestFormat_Canonical(t *testing.T) {
+	got := string(Format([]byte(messyPlan)))
+	if got != canonicalPlan {
+		t.Fatalf("unexpected output:\n%s\ndiff:\n%s", got, Diff("want", "got", []byte(canonicalPlan), []byte(got)))
+	}
+}
+
+func TestFormat_Idempotent(t *testing.T) {
+	once := Format([]byte(messyPlan))
+	twice := Format(once)
+	if string(once) != string(twice) {
+		t.Fatalf("format is not idempotent:\n%s", Diff("once", "twice", once, twice))
+	}
+}
+
+func TestFormat_PreservesIR(t *testing.T) {
+	original, err := compiler.Compile([]byte(messyPlan))
+	if err != nil {
+		t.Fatalf("compile original: %v", err)
+	}
+	formatted, err := compiler.Compile(Format([]byte(messyPlan)))
+	if err != nil {
+		t.Fatalf("compile formatted: %v", err)
+	}
+	if !reflect.DeepEqual(original, formatted) {
+		t.Fatalf("formatting changed the IR:\noriginal:  %+v\nformatted: %+v", original, formatted)
+	}
+}
+
+func TestFormat_KeepsFrontMatter(t *testing.T) {
+	input := "---\nextends: base.md\n---\n## Constraints\n* ~remove Reply in English\n"
+	want := "---\nextends: base.md\n---\n\n## Constraints\n- ~remove Reply in English\n"
+	if got := string(Format([]byte(input))); got != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestWrapItem_NeverStartsLineWithMarker(t *testing.T) {
+	text := strings.Repeat("word ", 15) + "- dash and more words after it"
+	for _, line := range wrapItem(text)[1:] {
+		if listMarkerRe.MatchString(strings.TrimSpace(line)) {
+			t.Fatalf("continuation line reads as a list item: %q", line)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package ir
+
+import "sort"
+
+// JSONSchemaDialect is the JSON Schema draft used for exported contract schemas.
+const JSONSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// ContractJSONSchema converts a contract schema (input_schema or output_schema)
+// into a standalone JSON Schema document with the given $id and title.
+func ContractJSONSchema(schema Schema, id string, title string) map[string]interface{} {
+	doc := SchemaToJSONSchema(schema)
+	doc["$schema"] = JSONSchemaDialect
+	if id != "" {
+		doc["$id"] = id
+	}
+	if title != "" {
+		doc["title"] = title
+	}
+	return doc
+}
+
+// SchemaToJSONSchema converts a contract schema into a JSON Schema fragment.
+func SchemaToJSONSchema(schema Schema) map[string]interface{} {
+	out := map[string]interface{}{
+		"type": schema.Type,
+	}
+	if len(schema.Properties) > 0 {
+		out["properties"] = propertiesToJSONSchema(schema.Properties)
+	}
+	if len(schema.Required) > 0 {
+		required := make([]string, len(schema.Required))
+		copy(required, schema.Required)
+		out["required"] = required
+	}
+	if schema.Items != nil {
+		out["items"] = SchemaToJSONSchema(*schema.Items)
+	}
+	if schema.AdditionalProperties != nil {
+		out["additionalProperties"] = *schema.AdditionalProperties
+	}
+	return out
+}
+
+func propertiesToJSONSchema(properties map[string]Property) map[string]interface{} {
+	out := make(map[string]interface{}, len(properties))
+	for _, name := range SortedPropertyNames(properties) {
+		out[name] = propertyToJSONSchema(properties[name])
+	}
+	return out
+}
+
+func propertyToJSONSchema(property Property) map[string]interface{} {
+	out := map[string]interface{}{
+		"type": property.Type,
+	}
+	if property.Description != "" {
+		out["description"] = property.Description
+	}
+	if len(property.Enum) > 0 {
+		out["enum"] = property.Enum
+	}
+	if len(property.Properties) > 0 {
+		out["properties"] = propertiesToJSONSchema(property.Properties)
+	}
+	if len(property.Required) > 0 {
+		required := make([]string, len(property.Required))
+		copy(required, property.Required)
+		out["required"] = required
+	}
+	if property.Items != nil {
+		out["items"] = SchemaToJSONSchema(*property.Items)
+	}
+	if property.AdditionalProperties != nil {
+		out["additionalProperties"] = *property.AdditionalProperties
+	}
+	return out
+}
+
+// SortedPropertyNames returns property names in deterministic (lexical) order.
+func SortedPropertyNames(properties map[string]Property) []string {
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
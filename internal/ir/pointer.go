@@ -0,0 +1,11 @@
+package ir
+
+import "strings"
+
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// EscapePointer escapes a property name for use as a JSON pointer token
+// (RFC 6901), so "a/b" becomes "a~1b".
+func EscapePointer(name string) string {
+	return pointerEscaper.Replace(name)
+}
@@ -20,6 +20,16 @@ type PromptIR struct {
 
 	// FailureModes describes how the system should handle errors.
 	FailureModes []FailureMode `json:"failure_modes"`
+
+	// Tools lists the tools (functions) the model may call.
+	Tools []Tool `json:"tools,omitempty"`
+
+	// Variables declares the {{name}} slots that must be filled in at render time.
+	Variables []Variable `json:"variables,omitempty"`
+
+	// Includes records the files pulled into plan.md by include directives,
+	// so audits can detect when they change after compilation.
+	Includes []IncludedFile `json:"includes,omitempty"`
 }
 
 // Rule represents a single behavioral constraint.
@@ -32,6 +42,9 @@ type Rule struct {
 
 	// Condition is an optional condition that must be met for the rule to apply.
 	Condition string `json:"condition,omitempty"`
+
+	// Tools lists the names of tools this rule refers to.
+	Tools []string `json:"tools,omitempty"`
 }
 
 // Schema defines the structure of input or output data.
@@ -47,6 +60,10 @@ type Schema struct {
 
 	// Items defines the schema for array element types.
 	Items *Schema `json:"items,omitempty"`
+
+	// AdditionalProperties, when set to false, rejects object properties that are
+	// not declared in Properties.
+	AdditionalProperties *bool `json:"additional_properties,omitempty"`
 }
 
 // Property defines a single field in a schema.
@@ -63,8 +80,15 @@ type Property struct {
 	// Properties defines nested properties for object types.
 	Properties map[string]Property `json:"properties,omitempty"`
 
+	// Required lists required nested property names for object types.
+	Required []string `json:"required,omitempty"`
+
 	// Items defines the schema for array element types.
 	Items *Schema `json:"items,omitempty"`
+
+	// AdditionalProperties, when set to false, rejects nested object properties
+	// that are not declared in Properties.
+	AdditionalProperties *bool `json:"additional_properties,omitempty"`
 }
 
 // FailureMode describes how to handle a specific failure scenario.
@@ -77,4 +101,46 @@ type FailureMode struct {
 
 	// Response describes how the system should respond to this failure.
 	Response string `json:"response"`
+
+	// Tools lists the names of tools this failure mode refers to.
+	Tools []string `json:"tools,omitempty"`
+}
+
+// Tool describes a tool (function) the model may call, in provider-neutral form.
+type Tool struct {
+	// Name is the identifier the model uses to call the tool.
+	Name string `json:"name"`
+
+	// Description explains the tool's purpose to the model.
+	Description string `json:"description,omitempty"`
+
+	// Parameters is the JSON Schema (type "object") for the tool's arguments.
+	Parameters Schema `json:"parameters"`
+}
+
+// Variable declares a runtime value substituted into {{name}} slots when the prompt is rendered.
+type Variable struct {
+	// Name is the slot name used in {{name}} placeholders.
+	Name string `json:"name"`
+
+	// Type is the value type: "string", "number", "integer", "boolean" or "date".
+	Type string `json:"type"`
+
+	// Required indicates that rendering fails when no value or default is available.
+	Required bool `json:"required,omitempty"`
+
+	// Default is used when no value is supplied at render time.
+	Default *string `json:"default,omitempty"`
+
+	// Description is a human-readable description of the variable.
+	Description string `json:"description,omitempty"`
+}
+
+// IncludedFile records a file included into the plan at compile time.
+type IncludedFile struct {
+	// Path is the file path relative to the project directory, using forward slashes.
+	Path string `json:"path"`
+
+	// SHA256 is the hex-encoded SHA-256 digest of the file contents at compile time.
+	SHA256 string `json:"sha256"`
 }
@@ -53,6 +53,24 @@ func promptIRSchema() map[string]interface{} {
 					"$ref": "#/$defs/failure_mode",
 				},
 			},
+			"tools": map[string]interface{}{
+				"type": "array",
+				"items": map[string]interface{}{
+					"$ref": "#/$defs/tool",
+				},
+			},
+			"variables": map[string]interface{}{
+				"type": "array",
+				"items": map[string]interface{}{
+					"$ref": "#/$defs/variable",
+				},
+			},
+			"includes": map[string]interface{}{
+				"type": "array",
+				"items": map[string]interface{}{
+					"$ref": "#/$defs/included_file",
+				},
+			},
 		},
 		"$defs": map[string]interface{}{
 			"rule": map[string]interface{}{
@@ -74,6 +92,9 @@ func promptIRSchema() map[string]interface{} {
 					"condition": map[string]interface{}{
 						"type": "string",
 					},
+					"tools": map[string]interface{}{
+						"$ref": "#/$defs/tool_refs",
+					},
 				},
 			},
 			"schema": map[string]interface{}{
@@ -102,6 +123,9 @@ func promptIRSchema() map[string]interface{} {
 					"items": map[string]interface{}{
 						"$ref": "#/$defs/schema",
 					},
+					"additional_properties": map[string]interface{}{
+						"type": "boolean",
+					},
 				},
 			},
 			"property": map[string]interface{}{
@@ -127,9 +151,18 @@ func promptIRSchema() map[string]interface{} {
 							"$ref": "#/$defs/property",
 						},
 					},
+					"required": map[string]interface{}{
+						"type": "array",
+						"items": map[string]interface{}{
+							"type": "string",
+						},
+					},
 					"items": map[string]interface{}{
 						"$ref": "#/$defs/schema",
 					},
+					"additional_properties": map[string]interface{}{
+						"type": "boolean",
+					},
 				},
 			},
 			"failure_mode": map[string]interface{}{
@@ -153,6 +186,81 @@ func promptIRSchema() map[string]interface{} {
 						"type":      "string",
 						"minLength": 1,
 					},
+					"tools": map[string]interface{}{
+						"$ref": "#/$defs/tool_refs",
+					},
+				},
+			},
+			"tool": map[string]interface{}{
+				"type": "object",
+				"required": []string{
+					"name",
+					"parameters",
+				},
+				"additionalProperties": false,
+				"properties": map[string]interface{}{
+					"name": map[string]interface{}{
+						"type":    "string",
+						"pattern": "^[A-Za-z0-9_-]{1,64}$",
+					},
+					"description": map[string]interface{}{
+						"type": "string",
+					},
+					"parameters": map[string]interface{}{
+						"$ref": "#/$defs/schema",
+					},
+				},
+			},
+			"variable": map[string]interface{}{
+				"type": "object",
+				"required": []string{
+					"name",
+					"type",
+				},
+				"additionalProperties": false,
+				"properties": map[string]interface{}{
+					"name": map[string]interface{}{
+						"type":      "string",
+						"minLength": 1,
+					},
+					"type": map[string]interface{}{
+						"type": "string",
+						"enum": []string{"string", "number", "integer", "boolean", "date"},
+					},
+					"required": map[string]interface{}{
+						"type": "boolean",
+					},
+					"default": map[string]interface{}{
+						"type": "string",
+					},
+					"description": map[string]interface{}{
+						"type": "string",
+					},
+				},
+			},
+			"included_file": map[string]interface{}{
+				"type": "object",
+				"required": []string{
+					"path",
+					"sha256",
+				},
+				"additionalProperties": false,
+				"properties": map[string]interface{}{
+					"path": map[string]interface{}{
+						"type":      "string",
+						"minLength": 1,
+					},
+					"sha256": map[string]interface{}{
+						"type":    "string",
+						"pattern": "^[0-9a-f]{64}$",
+					},
+				},
+			},
+			"tool_refs": map[string]interface{}{
+				"type": "array",
+				"items": map[string]interface{}{
+					"type":      "string",
+					"minLength": 1,
 				},
 			},
 		},
@@ -0,0 +1,47 @@
+package ir
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// VariableDateLayout is the accepted format for "date" variables.
+const VariableDateLayout = "2006-01-02"
+
+// IsVariableType reports whether typeName is a supported variable type.
+func IsVariableType(typeName string) bool {
+	switch typeName {
+	case "string", "number", "integer", "boolean", "date":
+		return true
+	default:
+		return false
+	}
+}
+
+// CheckVariableValue reports whether value is a valid literal for the given variable type.
+func CheckVariableValue(typeName, value string) error {
+	switch typeName {
+	case "string":
+		return nil
+	case "number":
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return fmt.Errorf("%q is not a number", value)
+		}
+	case "integer":
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			return fmt.Errorf("%q is not an integer", value)
+		}
+	case "boolean":
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("%q is not a boolean", value)
+		}
+	case "date":
+		if _, err := time.Parse(VariableDateLayout, value); err != nil {
+			return fmt.Errorf("%q is not a date (expected YYYY-MM-DD)", value)
+		}
+	default:
+		return fmt.Errorf("unknown variable type %q", typeName)
+	}
+	return nil
+}
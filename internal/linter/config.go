@@ -0,0 +1,34 @@
+package linter
+
+import "github.com/promptforge/promptforge/internal/scan"
+
+// Config holds project lint settings. Projects override the defaults in
+// .promptforge/lint.json:
+//
+//	{
+//	  "verifiability": {"warn_below": 50, "error_below": 20},
+//	  "scan": {"allow": [{"code": "PF302", "pattern": "^support@acme\\.com$"}]}
+//	}
+type Config struct {
+	Verifiability VerifiabilityConfig `json:"verifiability"`
+
+	// Scan allowlists false positives of the secret, PII and injection scan.
+	Scan scan.Config `json:"scan"`
+}
+
+// VerifiabilityConfig sets the verifiability score thresholds for constraints.
+// A threshold of 0 disables it.
+type VerifiabilityConfig struct {
+	// WarnBelow reports constraints scoring below it as PF208 warnings.
+	WarnBelow int `json:"warn_below"`
+
+	// ErrorBelow reports constraints scoring below it as PF109 errors.
+	ErrorBelow int `json:"error_below"`
+}
+
+// DefaultConfig returns the settings used when a project has no lint config.
+func DefaultConfig() Config {
+	return Config{
+		Verifiability: VerifiabilityConfig{WarnBelow: 30},
+	}
+}
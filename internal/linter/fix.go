@@ -0,0 +1,213 @@
+package linter
+
+import (
+	"sort"
+	"strings"
+)
+
+// TextEdit replaces the 1-based, inclusive line range StartLine..EndLine with
+// NewLines. An edit with EndLine == StartLine-1 inserts NewLines before StartLine;
+// an edit without NewLines deletes the range.
+type TextEdit struct {
+	StartLine int      `json:"startLine"`
+	EndLine   int      `json:"endLine"`
+	NewLines  []string `json:"newLines"`
+}
+
+// Fix is a machine-applicable fix for a diagnostic.
+type Fix struct {
+	Title string     `json:"title"`
+	Edits []TextEdit `json:"edits"`
+}
+
+// skeletonComments are the placeholder comments inserted with a missing section,
+// matching the ones written by promptforge init.
+var skeletonComments = map[string]string{
+	"Constraints":  "<!-- List the limitations, rules, and boundaries that must be respected -->",
+	"Out of Scope": "<!-- Explicitly state what this prompt should NOT handle or address -->",
+}
+
+// ApplyFixes applies the fixes attached to diagnostics to content and returns the
+// result with the number of fixes applied. A fix whose edits overlap an edit of an
+// earlier fix is skipped; linting the result again yields it (or its replacement).
+func ApplyFixes(content []byte, diagnostics []Diagnostic) ([]byte, int) {
+	var fixes []*Fix
+	for i := range diagnostics {
+		if fix := diagnostics[i].Fix; fix != nil && len(fix.Edits) > 0 {
+			fixes = append(fixes, fix)
+		}
+	}
+	if len(fixes) == 0 {
+		return content, 0
+	}
+
+	sort.SliceStable(fixes, func(i, j int) bool {
+		return fixes[i].Edits[0].StartLine < fixes[j].Edits[0].StartLine
+	})
+
+	var accepted []TextEdit
+	applied := 0
+	for _, fix := range fixes {
+		if overlapsAny(fix.Edits, accepted) {
+			continue
+		}
+		accepted = append(accepted, fix.Edits...)
+		applied++
+	}
+
+	// Apply bottom-up so earlier line numbers stay valid. At the same line, the
+	// replacement goes first so that an insertion lands in front of its result.
+	sort.SliceStable(accepted, func(i, j int) bool {
+		if accepted[i].StartLine != accepted[j].StartLine {
+			return accepted[i].StartLine > accepted[j].StartLine
+		}
+		return accepted[i].EndLine > accepted[j].EndLine
+	})
+
+	lines := strings.Split(normalizeNewlines(string(content)), "\n")
+	for _, edit := range accepted {
+		start := edit.StartLine - 1
+		end := edit.EndLine
+		if start < 0 || start > len(lines) || end < start || end > len(lines) {
+			continue
+		}
+		updated := make([]string, 0, len(lines)-(end-start)+len(edit.NewLines))
+		updated = append(updated, lines[:start]...)
+		updated = append(updated, edit.NewLines...)
+		updated = append(updated, lines[end:]...)
+		lines = updated
+	}
+
+	return []byte(strings.Join(lines, "\n")), applied
+}
+
+func overlapsAny(edits []TextEdit, accepted []TextEdit) bool {
+	for _, edit := range edits {
+		for _, other := range accepted {
+			if edit.StartLine <= max(other.StartLine, other.EndLine) && other.StartLine <= max(edit.StartLine, edit.EndLine) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// suggestSection returns the known section closest to name by edit distance, or ""
+// when none is close enough to be a likely typo.
+func suggestSection(name string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", " ", "_", " ").Replace(strings.TrimSpace(name)))
+	best, bestDistance := "", 3
+	for _, known := range knownSections {
+		if distance := editDistance(normalized, strings.ToLower(known)); distance < bestDistance {
+			best, bestDistance = known, distance
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	ar, br := []rune(a), []rune(b)
+	prev := make([]int, len(br)+1)
+	curr := make([]int, len(br)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ar); i++ {
+		curr[0] = i
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(br)]
+}
+
+// lastContentLine returns the last non-blank line of a section, or its heading.
+func lastContentLine(lines []string, info sectionInfo) int {
+	last := info.startLine
+	for idx := info.startLine + 1; idx <= info.endLine && idx <= len(lines); idx++ {
+		if strings.TrimSpace(lines[idx-1]) != "" {
+			last = idx
+		}
+	}
+	return last
+}
+
+// mergeSectionFix moves the body of a duplicate section to the end of the first one
+// and removes the duplicate.
+func mergeSectionFix(lines []string, first, duplicate sectionInfo) *Fix {
+	var body []string
+	for idx := duplicate.startLine + 1; idx <= duplicate.endLine && idx <= len(lines); idx++ {
+		body = append(body, lines[idx-1])
+	}
+	for len(body) > 0 && strings.TrimSpace(body[0]) == "" {
+		body = body[1:]
+	}
+	for len(body) > 0 && strings.TrimSpace(body[len(body)-1]) == "" {
+		body = body[:len(body)-1]
+	}
+
+	insertAt := lastContentLine(lines, first) + 1
+	edits := []TextEdit{{StartLine: duplicate.startLine, EndLine: duplicate.endLine}}
+	if len(body) > 0 {
+		edits = append([]TextEdit{{StartLine: insertAt, EndLine: insertAt - 1, NewLines: body}}, edits...)
+	}
+
+	return &Fix{
+		Title: "Merge into the first " + strings.TrimSpace(first.name) + " section",
+		Edits: edits,
+	}
+}
+
+// insertSectionFix inserts an empty section skeleton after the first of anchors that
+// exists, or at the end of the plan (before any "---" trailer).
+func insertSectionFix(lines []string, name string, sectionBounds map[string]sectionInfo, anchors ...string) *Fix {
+	at := 0
+	for _, anchor := range anchors {
+		if info := firstSectionInfo(sectionBounds, anchor); info != nil {
+			at = lastContentLine(lines, *info)
+			break
+		}
+	}
+	if at == 0 {
+		lastHeading := 0
+		for _, info := range sectionBounds {
+			lastHeading = max(lastHeading, info.startLine)
+		}
+		end := len(lines)
+		for idx := lastHeading + 1; idx <= len(lines); idx++ {
+			if lastHeading > 0 && strings.TrimSpace(lines[idx-1]) == "---" {
+				end = idx - 1
+				break
+			}
+		}
+		for idx := 1; idx <= end; idx++ {
+			if strings.TrimSpace(lines[idx-1]) != "" {
+				at = idx
+			}
+		}
+	}
+
+	newLines := []string{"", "## " + name, skeletonComments[name]}
+	if at < len(lines) && strings.TrimSpace(lines[at]) != "" {
+		newLines = append(newLines, "")
+	}
+
+	return &Fix{
+		Title: "Insert " + name + " section",
+		Edits: []TextEdit{{StartLine: at + 1, EndLine: at, NewLines: newLines}},
+	}
+}
+
+// removeLinesFix deletes the lines of a list item.
+func removeLinesFix(title string, item listItem) *Fix {
+	return &Fix{
+		Title: title,
+		Edits: []TextEdit{{StartLine: item.line, EndLine: item.endLine}},
+	}
+}
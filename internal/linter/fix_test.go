@@ -0,0 +1,95 @@
+package linter
+
+import (
+	"testing"
+)
+
+// fixAll applies fixes until none are left, as promptforge lint --fix does.
+func fixAll(t *testing.T, content string) string {
+	t.Helper()
+	for pass := 0; pass < 10; pass++ {
+		fixed, applied := ApplyFixes([]byte(content), LintPlan([]byte(content)))
+		if applied == 0 {
+			return content
+		}
+		content = string(fixed)
+	}
+	t.Fatal("fixes did not converge")
+	return ""
+}
+
+func findDiagnostic(diags []Diagnostic, code string) *Diagnostic {
+	for i := range diags {
+		if diags[i].Code == code {
+			return &diags[i]
+		}
+	}
+	return nil
+}
+
+func TestFix_MergesDuplicateSections(t *testing.T) {
+	content := "## Goal\nAnswer billing questions.\n\n## Constraints\n- Reply in English\n\n## Out of Scope\n- Legal advice\n\n## Constraints\n- Cite the invoice number\n"
+	want := "## Goal\nAnswer billing questions.\n\n## Constraints\n- Reply in English\n- Cite the invoice number\n\n## Out of Scope\n- Legal advice\n"
+
+	if got := fixAll(t, content); got != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestFix_RenamesUnknownSection(t *testing.T) {
+	content := "## Goal\nAnswer billing questions.\n\n## Constraint\n- Reply in English\n\n## Out-of-scope\n- Legal advice\n"
+
+	diag := findDiagnostic(LintPlan([]byte(content)), "PF103")
+	if diag == nil || diag.Message != "unknown section heading: Constraint (did you mean Constraints?)" {
+		t.Fatalf("unexpected PF103 diagnostic: %+v", diag)
+	}
+
+	want := "## Goal\nAnswer billing questions.\n\n## Constraints\n- Reply in English\n\n## Out of Scope\n- Legal advice\n"
+	if got := fixAll(t, content); got != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestFix_NoSuggestionForUnrelatedSection(t *testing.T) {
+	diag := findDiagnostic(LintPlan([]byte("## Goal\nAnswer billing questions.\n\n## Notes\nSomething\n")), "PF103")
+	if diag == nil || diag.Fix != nil {
+		t.Fatalf("expected PF103 without a fix, got %+v", diag)
+	}
+}
+
+func TestFix_InsertsMissingSections(t *testing.T) {
+	content := "# Prompt Plan\n\n## Goal\nAnswer billing questions.\n\n---\nNote\n"
+	want := "# Prompt Plan\n\n## Goal\nAnswer billing questions.\n\n" +
+		"## Constraints\n" + skeletonComments["Constraints"] + "\n\n" +
+		"## Out of Scope\n" + skeletonComments["Out of Scope"] + "\n\n---\nNote\n"
+
+	if got := fixAll(t, content); got != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestFix_RemovesDuplicateConstraints(t *testing.T) {
+	content := "## Goal\nAnswer billing questions.\n\n## Constraints\n- Reply in English\n- Never share card\n  numbers\n- reply in english\n- Never share card numbers\n\n## Out of Scope\n- Legal advice\n"
+
+	diags := LintPlan([]byte(content))
+	diag := findDiagnostic(diags, "PF207")
+	if diag == nil || diag.Line != 8 || diag.Message != "duplicate constraint: reply in english (first on line 5)" {
+		t.Fatalf("unexpected PF207 diagnostic: %+v", diag)
+	}
+
+	want := "## Goal\nAnswer billing questions.\n\n## Constraints\n- Reply in English\n- Never share card\n  numbers\n\n## Out of Scope\n- Legal advice\n"
+	if got := fixAll(t, content); got != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestApplyFixes_SkipsOverlappingFixes(t *testing.T) {
+	diags := []Diagnostic{
+		{Fix: &Fix{Edits: []TextEdit{{StartLine: 2, EndLine: 2, NewLines: []string{"B"}}}}},
+		{Fix: &Fix{Edits: []TextEdit{{StartLine: 2, EndLine: 3}}}},
+	}
+	got, applied := ApplyFixes([]byte("a\nb\nc\n"), diags)
+	if applied != 1 || string(got) != "a\nB\nc\n" {
+		t.Fatalf("got %q (%d applied)", got, applied)
+	}
+}
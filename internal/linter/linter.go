@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+
+	"github.com/promptforge/promptforge/internal/parser"
 )
 
 type Severity string
@@ -14,11 +16,21 @@ const (
 )
 
 type Diagnostic struct {
-	Severity Severity
-	Code     string
-	Message  string
-	Line     int
-	Column   int
+	Severity Severity `json:"severity"`
+	Code     string   `json:"code"`
+	Message  string   `json:"message"`
+	Line     int      `json:"line"`
+	Column   int      `json:"column"`
+
+	// File is set when the diagnostic points into a file other than the linted plan
+	// (for example, a file pulled in by an include directive).
+	File string `json:"file,omitempty"`
+
+	// Fix, when set, is an edit to the linted plan that resolves the diagnostic.
+	Fix *Fix `json:"fix,omitempty"`
+
+	// Score is the verifiability score of the constraint, for PF109 and PF208.
+	Score *int `json:"score,omitempty"`
 }
 
 type sectionInfo struct {
@@ -32,10 +44,16 @@ var (
 	listBulletRe   = regexp.MustCompile(`^[\s]*[-*•]\s+`)
 	listNumberRe   = regexp.MustCompile(`^\d+\.\s+`)
 	vagueTermsRe   = regexp.MustCompile(`\b(etc|misc|various|stuff|things)\b`)
+	toolNameRe     = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
 )
 
-// LintPlan analyzes plan.md content and returns diagnostics.
+// LintPlan analyzes plan.md content with the default config and returns diagnostics.
 func LintPlan(content []byte) []Diagnostic {
+	return LintPlanWithConfig(content, DefaultConfig())
+}
+
+// LintPlanWithConfig analyzes plan.md content and returns diagnostics.
+func LintPlanWithConfig(content []byte, config Config) []Diagnostic {
 	if len(content) == 0 {
 		return []Diagnostic{
 			{
@@ -57,6 +75,10 @@ func LintPlan(content []byte) []Diagnostic {
 	var order []sectionInfo
 	var diagnostics []Diagnostic
 
+	// renamed records the sections that a PF103 fix renames a heading to, so that
+	// they are not also inserted as missing
+	renamed := make(map[string]bool)
+
 	for i, line := range strippedLines {
 		matches := headingPattern.FindStringSubmatch(strings.TrimSpace(line))
 		if len(matches) == 0 {
@@ -71,27 +93,22 @@ func LintPlan(content []byte) []Diagnostic {
 		order = append(order, info)
 
 		if !isKnownSection(name) {
-			diagnostics = append(diagnostics, Diagnostic{
+			diag := Diagnostic{
 				Severity: SeverityError,
 				Code:     "PF103",
 				Message:  fmt.Sprintf("unknown section heading: %s", name),
 				Line:     i + 1,
 				Column:   1,
-			})
-		}
-	}
-
-	for name, infos := range sections {
-		if isKnownSection(name) && len(infos) > 1 {
-			for i := 1; i < len(infos); i++ {
-				diagnostics = append(diagnostics, Diagnostic{
-					Severity: SeverityError,
-					Code:     "PF102",
-					Message:  fmt.Sprintf("duplicate section heading: %s", infos[i].name),
-					Line:     infos[i].startLine,
-					Column:   1,
-				})
 			}
+			if suggestion := suggestSection(name); suggestion != "" {
+				diag.Message += fmt.Sprintf(" (did you mean %s?)", suggestion)
+				renamed[strings.ToLower(suggestion)] = true
+				diag.Fix = &Fix{
+					Title: "Rename to " + suggestion,
+					Edits: []TextEdit{{StartLine: i + 1, EndLine: i + 1, NewLines: []string{"## " + suggestion}}},
+				}
+			}
+			diagnostics = append(diagnostics, diag)
 		}
 	}
 
@@ -111,12 +128,29 @@ func LintPlan(content []byte) []Diagnostic {
 			}
 		}
 
+		order[i] = info
 		key := strings.ToLower(strings.TrimSpace(info.name))
 		if _, ok := sectionBounds[key]; !ok {
 			sectionBounds[key] = info
 		}
 	}
 
+	for _, info := range order {
+		key := strings.ToLower(strings.TrimSpace(info.name))
+		first := sectionBounds[key]
+		if !isKnownSection(key) || first.startLine == info.startLine {
+			continue
+		}
+		diagnostics = append(diagnostics, Diagnostic{
+			Severity: SeverityError,
+			Code:     "PF102",
+			Message:  fmt.Sprintf("duplicate section heading: %s", info.name),
+			Line:     info.startLine,
+			Column:   1,
+			Fix:      mergeSectionFix(lines, first, info),
+		})
+	}
+
 	goalInfo := firstSectionInfo(sectionBounds, "goal")
 	if goalInfo == nil {
 		diagnostics = append(diagnostics, Diagnostic{
@@ -149,15 +183,19 @@ func LintPlan(content []byte) []Diagnostic {
 
 	constraintsInfo := firstSectionInfo(sectionBounds, "constraints")
 	if constraintsInfo == nil {
-		diagnostics = append(diagnostics, Diagnostic{
+		diag := Diagnostic{
 			Severity: SeverityWarn,
 			Code:     "PF200",
 			Message:  "Constraints section is missing",
 			Line:     1,
 			Column:   1,
-		})
+		}
+		if !renamed["constraints"] {
+			diag.Fix = insertSectionFix(lines, "Constraints", sectionBounds, "goal")
+		}
+		diagnostics = append(diagnostics, diag)
 	} else {
-		items := parseListWithLines(strippedLines, constraintsInfo.startLine, constraintsInfo.endLine)
+		items := parseListWithLines(strippedLines, constraintsInfo.startLine+1, constraintsInfo.endLine)
 		if len(items) == 0 {
 			diagnostics = append(diagnostics, Diagnostic{
 				Severity: SeverityWarn,
@@ -178,19 +216,26 @@ func LintPlan(content []byte) []Diagnostic {
 				})
 			}
 		}
+		diagnostics = append(diagnostics, lintVerifiability(items, declaredNames(strippedLines, sectionBounds), config.Verifiability)...)
+		diagnostics = append(diagnostics, lintDuplicateItems(items, "constraint")...)
+		diagnostics = append(diagnostics, lintOverrides(items)...)
 	}
 
 	outOfScopeInfo := firstSectionInfo(sectionBounds, "out of scope")
 	if outOfScopeInfo == nil {
-		diagnostics = append(diagnostics, Diagnostic{
+		diag := Diagnostic{
 			Severity: SeverityWarn,
 			Code:     "PF201",
 			Message:  "Out of Scope section is missing",
 			Line:     1,
 			Column:   1,
-		})
+		}
+		if !renamed["out of scope"] {
+			diag.Fix = insertSectionFix(lines, "Out of Scope", sectionBounds, "constraints", "goal")
+		}
+		diagnostics = append(diagnostics, diag)
 	} else {
-		items := parseListWithLines(strippedLines, outOfScopeInfo.startLine, outOfScopeInfo.endLine)
+		items := parseListWithLines(strippedLines, outOfScopeInfo.startLine+1, outOfScopeInfo.endLine)
 		if len(items) == 0 {
 			diagnostics = append(diagnostics, Diagnostic{
 				Severity: SeverityWarn,
@@ -200,11 +245,161 @@ func LintPlan(content []byte) []Diagnostic {
 				Column:   1,
 			})
 		}
+		diagnostics = append(diagnostics, lintDuplicateItems(items, "out of scope item")...)
+		diagnostics = append(diagnostics, lintOverrides(items)...)
+	}
+
+	if toolsInfo := firstSectionInfo(sectionBounds, "tools"); toolsInfo != nil {
+		tools := parser.ParseToolsSection(strippedLines, toolsInfo.startLine+1, toolsInfo.endLine)
+		diagnostics = append(diagnostics, lintTools(tools)...)
+	}
+
+	diagnostics = append(diagnostics, lintVariables(strippedLines, sectionBounds)...)
+	diagnostics = append(diagnostics, lintScan(contentStr, config.Scan)...)
+
+	return diagnostics
+}
+
+// lintDuplicateItems reports list items that repeat an earlier item (ignoring case).
+// The parser drops them, so removing them does not change the compiled IR.
+func lintDuplicateItems(items []listItem, kind string) []Diagnostic {
+	var diagnostics []Diagnostic
+	seen := make(map[string]int)
+	for _, item := range items {
+		key := strings.ToLower(item.text)
+		firstLine, ok := seen[key]
+		if !ok {
+			seen[key] = item.line
+			continue
+		}
+		diagnostics = append(diagnostics, Diagnostic{
+			Severity: SeverityWarn,
+			Code:     "PF207",
+			Message:  fmt.Sprintf("duplicate %s: %s (first on line %d)", kind, item.text, firstLine),
+			Line:     item.line,
+			Column:   1,
+			Fix:      removeLinesFix("Remove duplicate "+kind, item),
+		})
+	}
+	return diagnostics
+}
+
+// lintOverrides reports ~remove/~replace directives left in a plan. Loading a plan
+// that extends a base consumes them, so any that remain have no base to apply to.
+func lintOverrides(items []listItem) []Diagnostic {
+	var diagnostics []Diagnostic
+	for _, item := range items {
+		if parser.IsOverrideDirective(item.text) {
+			diagnostics = append(diagnostics, Diagnostic{
+				Severity: SeverityError,
+				Code:     "PF111",
+				Message:  fmt.Sprintf("override directive without a base plan: %s (add 'extends:' front matter)", item.text),
+				Line:     item.line,
+				Column:   1,
+			})
+		}
+	}
+	return diagnostics
+}
+
+// lintTools checks tool declarations for duplicate or invalid names and missing descriptions.
+func lintTools(tools []parser.Tool) []Diagnostic {
+	var diagnostics []Diagnostic
+	seen := make(map[string]int)
+
+	for _, tool := range tools {
+		if firstLine, ok := seen[tool.Name]; ok {
+			diagnostics = append(diagnostics, Diagnostic{
+				Severity: SeverityError,
+				Code:     "PF104",
+				Message:  fmt.Sprintf("duplicate tool name: %s (first declared on line %d)", tool.Name, firstLine),
+				Line:     tool.Line,
+				Column:   1,
+			})
+		} else {
+			seen[tool.Name] = tool.Line
+		}
+
+		if !toolNameRe.MatchString(tool.Name) {
+			diagnostics = append(diagnostics, Diagnostic{
+				Severity: SeverityError,
+				Code:     "PF105",
+				Message:  fmt.Sprintf("invalid tool name: %s (use letters, digits, '_' or '-', up to 64 characters)", tool.Name),
+				Line:     tool.Line,
+				Column:   1,
+			})
+		}
+
+		if strings.TrimSpace(tool.Description) == "" {
+			diagnostics = append(diagnostics, Diagnostic{
+				Severity: SeverityWarn,
+				Code:     "PF205",
+				Message:  fmt.Sprintf("tool %s has no description of its purpose", tool.Name),
+				Line:     tool.Line,
+				Column:   1,
+			})
+		}
+
+		for _, paramErr := range tool.Errors {
+			diagnostics = append(diagnostics, Diagnostic{
+				Severity: SeverityError,
+				Code:     "PF106",
+				Message:  fmt.Sprintf("invalid parameter declaration for tool %s: %s", tool.Name, paramErr.Message),
+				Line:     paramErr.Line,
+				Column:   1,
+			})
+		}
+
+		params := make(map[string]bool)
+		for _, param := range tool.Parameters {
+			if params[param.Name] {
+				diagnostics = append(diagnostics, Diagnostic{
+					Severity: SeverityError,
+					Code:     "PF106",
+					Message:  fmt.Sprintf("duplicate parameter %s for tool %s", param.Name, tool.Name),
+					Line:     param.Line,
+					Column:   1,
+				})
+			}
+			params[param.Name] = true
+
+			if !isKnownParameterType(param.Type) {
+				diagnostics = append(diagnostics, Diagnostic{
+					Severity: SeverityError,
+					Code:     "PF106",
+					Message:  fmt.Sprintf("unknown type %q for parameter %s of tool %s", param.Type, param.Name, tool.Name),
+					Line:     param.Line,
+					Column:   1,
+				})
+			}
+
+			if strings.TrimSpace(param.Description) == "" {
+				diagnostics = append(diagnostics, Diagnostic{
+					Severity: SeverityWarn,
+					Code:     "PF204",
+					Message:  fmt.Sprintf("parameter %s of tool %s has no description", param.Name, tool.Name),
+					Line:     param.Line,
+					Column:   1,
+				})
+			}
+		}
 	}
 
 	return diagnostics
 }
 
+func isKnownParameterType(typeName string) bool {
+	if elem, ok := parser.IsArrayType(typeName); ok {
+		typeName = elem
+	}
+	switch typeName {
+	case "string", "number", "integer", "boolean", "object", "array":
+		return true
+	default:
+		return false
+	}
+}
+
 func normalizeNewlines(input string) string {
 	input = strings.ReplaceAll(input, "\r\n", "\n")
 	return strings.ReplaceAll(input, "\r", "\n")
@@ -246,13 +441,17 @@ func stripComments(lines []string) []string {
 	return result
 }
 
+// knownSections lists the plan sections in canonical spelling.
+var knownSections = []string{"Goal", "Constraints", "Out of Scope", "Tools", "Variables"}
+
 func isKnownSection(name string) bool {
-	switch strings.ToLower(strings.TrimSpace(name)) {
-	case "goal", "constraints", "out of scope":
-		return true
-	default:
-		return false
+	key := strings.ToLower(strings.TrimSpace(name))
+	for _, known := range knownSections {
+		if strings.ToLower(known) == key {
+			return true
+		}
 	}
+	return false
 }
 
 func firstSectionInfo(sections map[string]sectionInfo, name string) *sectionInfo {
@@ -264,33 +463,20 @@ func firstSectionInfo(sections map[string]sectionInfo, name string) *sectionInfo
 }
 
 type listItem struct {
-	text string
-	line int
+	text    string
+	line    int
+	endLine int
 }
 
 func parseListWithLines(lines []string, startLine int, endLine int) []listItem {
 	var items []listItem
-
-	for idx := startLine; idx <= endLine && idx <= len(lines); idx++ {
-		line := strings.TrimSpace(lines[idx-1])
-		if line == "" {
-			continue
-		}
-
-		trimmed := listBulletRe.ReplaceAllString(line, "")
-		trimmed = listNumberRe.ReplaceAllString(trimmed, "")
-		trimmed = strings.TrimSpace(trimmed)
-
-		if trimmed == "" {
-			continue
-		}
-
+	for _, item := range parser.ListItems(lines, startLine, endLine) {
 		items = append(items, listItem{
-			text: trimmed,
-			line: idx,
+			text:    item.Text,
+			line:    item.Line,
+			endLine: item.EndLine,
 		})
 	}
-
 	return items
 }
 
@@ -0,0 +1,43 @@
+package linter
+
+import (
+	"fmt"
+
+	"github.com/promptforge/promptforge/internal/scan"
+)
+
+// lintScan reports secrets, personal data and prompt-injection phrasing (PF3xx).
+// Comments are scanned too: they stay in the repository even though they never
+// reach the prompt.
+func lintScan(content string, config scan.Config) []Diagnostic {
+	findings, err := scan.Text(content, config)
+	if err != nil {
+		// An invalid allowlist is reported when the config is loaded; scanning
+		// without it can only report more, never less.
+		findings, _ = scan.Text(content, scan.Config{})
+	}
+
+	diagnostics := make([]Diagnostic, 0, len(findings))
+	for _, finding := range findings {
+		diagnostics = append(diagnostics, Diagnostic{
+			Severity: Severity(finding.Severity),
+			Code:     finding.Code,
+			Message:  fmt.Sprintf("%s in plan; %s", finding.Message, scanAdvice(finding.Code)),
+			Line:     finding.Line,
+			Column:   finding.Column,
+		})
+	}
+	return diagnostics
+}
+
+// scanAdvice returns what to do about a scan finding.
+func scanAdvice(code string) string {
+	switch code {
+	case scan.CodeSecret, scan.CodePrivateKey:
+		return "plans are copied into prompts verbatim, so remove it and rotate it"
+	case scan.CodeInjection:
+		return "reword it, or allowlist it if the plan only describes the attack"
+	default:
+		return "use a placeholder or an example.com address, or allowlist it"
+	}
+}
@@ -0,0 +1,105 @@
+package linter
+
+import (
+	"fmt"
+
+	"github.com/promptforge/promptforge/internal/ir"
+	"github.com/promptforge/promptforge/internal/parser"
+)
+
+// placeholderSections are the sections whose text ends up in the rendered prompt.
+var placeholderSections = []string{"goal", "constraints", "out of scope"}
+
+// lintVariables checks Variables declarations and {{name}} usages in prompt sections.
+func lintVariables(strippedLines []string, sectionBounds map[string]sectionInfo) []Diagnostic {
+	var diagnostics []Diagnostic
+
+	declared := make(map[string]int)
+	var order []string
+	if info := firstSectionInfo(sectionBounds, "variables"); info != nil {
+		variables, errs := parser.ParseVariablesSection(strippedLines, info.startLine+1, info.endLine)
+		for _, paramErr := range errs {
+			diagnostics = append(diagnostics, Diagnostic{
+				Severity: SeverityError,
+				Code:     "PF108",
+				Message:  fmt.Sprintf("invalid variable declaration: %s", paramErr.Message),
+				Line:     paramErr.Line,
+				Column:   1,
+			})
+		}
+
+		for _, variable := range variables {
+			if firstLine, ok := declared[variable.Name]; ok {
+				diagnostics = append(diagnostics, Diagnostic{
+					Severity: SeverityError,
+					Code:     "PF108",
+					Message:  fmt.Sprintf("duplicate variable: %s (first declared on line %d)", variable.Name, firstLine),
+					Line:     variable.Line,
+					Column:   1,
+				})
+				continue
+			}
+			declared[variable.Name] = variable.Line
+			order = append(order, variable.Name)
+
+			if !ir.IsVariableType(variable.Type) {
+				diagnostics = append(diagnostics, Diagnostic{
+					Severity: SeverityError,
+					Code:     "PF108",
+					Message:  fmt.Sprintf("unknown type %q for variable %s (expected string, number, integer, boolean or date)", variable.Type, variable.Name),
+					Line:     variable.Line,
+					Column:   1,
+				})
+			} else if variable.HasDefault {
+				if err := ir.CheckVariableValue(variable.Type, variable.Default); err != nil {
+					diagnostics = append(diagnostics, Diagnostic{
+						Severity: SeverityError,
+						Code:     "PF108",
+						Message:  fmt.Sprintf("invalid default for variable %s: %s", variable.Name, err.Error()),
+						Line:     variable.Line,
+						Column:   1,
+					})
+				}
+			}
+		}
+	}
+
+	used := make(map[string]bool)
+	for _, name := range placeholderSections {
+		info := firstSectionInfo(sectionBounds, name)
+		if info == nil {
+			continue
+		}
+		for idx := info.startLine + 1; idx <= info.endLine && idx <= len(strippedLines); idx++ {
+			line := strippedLines[idx-1]
+			for _, placeholder := range parser.FindPlaceholders(line) {
+				variable := placeholder.Name
+				used[variable] = true
+				if _, ok := declared[variable]; ok {
+					continue
+				}
+				diagnostics = append(diagnostics, Diagnostic{
+					Severity: SeverityError,
+					Code:     "PF107",
+					Message:  fmt.Sprintf("undeclared variable {{%s}}; declare it in the Variables section", variable),
+					Line:     idx,
+					Column:   placeholder.Offset + 1,
+				})
+			}
+		}
+	}
+
+	for _, name := range order {
+		if !used[name] {
+			diagnostics = append(diagnostics, Diagnostic{
+				Severity: SeverityWarn,
+				Code:     "PF206",
+				Message:  fmt.Sprintf("variable %s is declared but never used", name),
+				Line:     declared[name],
+				Column:   1,
+			})
+		}
+	}
+
+	return diagnostics
+}
@@ -0,0 +1,249 @@
+package linter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/promptforge/promptforge/internal/parser"
+)
+
+// Constraints compile into rules, so each one should be something a reviewer or a
+// test can check. A constraint scores 0-100 for verifiability from these signals:
+//
+//   - a modal verb (must, never, always, only, at most, ...) or, for less, an
+//     imperative verb (return, reply, cite, ...)
+//   - a number or measurable limit
+//   - an enumeration of allowed values ("one of", quoted values, a, b or c)
+//   - a reference to a field, variable, tool or named format ({{name}}, `code`,
+//     snake_case names, JSON, English)
+//   - concrete nouns rather than only filler words
+//
+// Vague terms such as "helpful" or "appropriately" lower the score.
+
+const (
+	scoreModal       = 30
+	scoreImperative  = 15
+	scoreNumber      = 20
+	scoreEnumeration = 15
+	scoreReference   = 20
+	scoreConcrete    = 15
+	penaltyVague     = 15
+)
+
+var (
+	modalRe       = regexp.MustCompile(`(?i)\b(must|never|always|shall|only|exactly|required|forbidden|at most|at least|no more than|no fewer than|do not|don't|cannot|must not)\b`)
+	numberRe      = regexp.MustCompile(`(?i)\d|\b(one|two|three|four|five|six|seven|eight|nine|ten|twice|once|single)\b`)
+	enumerationRe = regexp.MustCompile(`(?i)"[^"]+"|'[^']+'|\bone of\b|\beither\b|\w+\s*,\s*\w+\s*,?\s*(?:or|and)\s+\w+|\w+(?:\s*,\s*\w+){2,}`)
+	referenceRe   = regexp.MustCompile("\\{\\{|`[^`]+`|\\b[a-z][a-z0-9]*_[a-z0-9_]+\\b|\\b[a-z]+[A-Z][A-Za-z0-9]*\\b")
+	wordRe        = regexp.MustCompile(`[A-Za-z][A-Za-z'-]*`)
+)
+
+var imperativeVerbs = map[string]bool{
+	"add": true, "answer": true, "ask": true, "avoid": true, "be": true, "call": true,
+	"check": true, "cite": true, "classify": true, "convert": true, "do": true, "ensure": true,
+	"escalate": true, "exclude": true, "flag": true, "follow": true, "format": true,
+	"include": true, "keep": true, "limit": true, "list": true, "mark": true, "mask": true,
+	"mention": true, "omit": true, "output": true, "prefer": true, "preserve": true,
+	"prioritize": true, "provide": true, "quote": true, "redact": true, "refuse": true,
+	"reject": true, "remove": true, "reply": true, "report": true, "respond": true,
+	"return": true, "sort": true, "summarize": true, "translate": true, "use": true,
+	"validate": true, "verify": true, "write": true,
+}
+
+var vagueWords = map[string]bool{
+	"appropriate": true, "appropriately": true, "best": true, "better": true, "clear": true,
+	"clearly": true, "etc": true, "friendly": true, "good": true, "great": true, "helpful": true,
+	"misc": true, "nice": true, "nicely": true, "properly": true, "reasonable": true,
+	"reasonably": true, "relevant": true, "stuff": true, "suitable": true, "things": true,
+	"various": true, "well": true,
+}
+
+var stopWords = map[string]bool{
+	"about": true, "also": true, "been": true, "being": true, "could": true, "each": true,
+	"from": true, "have": true, "into": true, "more": true, "most": true, "other": true,
+	"over": true, "should": true, "some": true, "such": true, "than": true, "that": true,
+	"their": true, "them": true, "then": true, "there": true, "they": true, "this": true,
+	"under": true, "were": true, "what": true, "when": true, "which": true, "will": true,
+	"with": true, "would": true, "your": true,
+}
+
+// Verifiability is the verifiability score of a constraint with the signals it lacks.
+type Verifiability struct {
+	Score int
+
+	// Vague lists the vague terms found in the constraint.
+	Vague []string
+
+	// Suggestions describe how to make the constraint checkable, most useful first.
+	Suggestions []string
+}
+
+// ScoreConstraint scores how verifiable a constraint is. Names lists the field,
+// variable and tool names declared by the plan; mentioning one counts as a reference.
+func ScoreConstraint(text string, names []string) Verifiability {
+	return scoreConstraint(text, namesPattern(names))
+}
+
+// namesPattern compiles a case-insensitive pattern matching any of names as a
+// whole word, or returns nil when there are no names.
+func namesPattern(names []string) *regexp.Regexp {
+	if len(names) == 0 {
+		return nil
+	}
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = regexp.QuoteMeta(name)
+	}
+	return regexp.MustCompile(`(?i)\b(?:` + strings.Join(quoted, "|") + `)\b`)
+}
+
+// scoreConstraint is ScoreConstraint with the declared names compiled once per
+// lint run by namesPattern.
+func scoreConstraint(text string, names *regexp.Regexp) Verifiability {
+	var result Verifiability
+	words := wordRe.FindAllString(text, -1)
+
+	switch {
+	case modalRe.MatchString(text):
+		result.Score += scoreModal
+	case len(words) > 0 && imperativeVerbs[strings.ToLower(words[0])]:
+		result.Score += scoreImperative
+		result.Suggestions = append(result.Suggestions, "state it as a rule with 'must' or 'never'")
+	default:
+		result.Suggestions = append(result.Suggestions, "state it as a rule with 'must' or 'never'")
+	}
+
+	concrete := 0
+	for _, word := range words {
+		lower := strings.ToLower(word)
+		if vagueWords[lower] {
+			result.Vague = append(result.Vague, word)
+			continue
+		}
+		if len(lower) >= 4 && !stopWords[lower] && !modalRe.MatchString(lower) {
+			concrete++
+		}
+	}
+
+	if hasReference(text, words, names) {
+		result.Score += scoreReference
+	} else {
+		result.Suggestions = append(result.Suggestions, "name the output field, variable or format it applies to")
+	}
+
+	if numberRe.MatchString(text) {
+		result.Score += scoreNumber
+	} else {
+		result.Suggestions = append(result.Suggestions, "add a measurable limit (a count, length or threshold)")
+	}
+
+	if enumerationRe.MatchString(text) {
+		result.Score += scoreEnumeration
+	} else {
+		result.Suggestions = append(result.Suggestions, "list the allowed values")
+	}
+
+	switch {
+	case concrete >= 2:
+		result.Score += scoreConcrete
+	case concrete == 1:
+		result.Score += scoreConcrete / 2
+	}
+
+	if len(result.Vague) > 0 {
+		result.Score -= penaltyVague * len(result.Vague)
+		result.Suggestions = append([]string{fmt.Sprintf("replace %q with a concrete criterion", result.Vague[0])}, result.Suggestions...)
+	}
+
+	result.Score = max(0, min(100, result.Score))
+	return result
+}
+
+// hasReference reports whether a constraint mentions a declared name, a placeholder,
+// code, an identifier-like name or a proper noun such as a format or language.
+func hasReference(text string, words []string, names *regexp.Regexp) bool {
+	if referenceRe.MatchString(text) || (names != nil && names.MatchString(text)) {
+		return true
+	}
+	for i, word := range words {
+		runes := []rune(word)
+		if i > 0 && unicode.IsUpper(runes[0]) {
+			return true
+		}
+		if len(runes) > 1 && strings.ToUpper(word) == word {
+			return true
+		}
+	}
+	return false
+}
+
+// lintVerifiability scores each constraint and reports those below the configured
+// thresholds: PF109 (error) below ErrorBelow, otherwise PF208 (warning) below WarnBelow.
+func lintVerifiability(items []listItem, names []string, config VerifiabilityConfig) []Diagnostic {
+	if config.WarnBelow <= 0 && config.ErrorBelow <= 0 {
+		return nil
+	}
+
+	pattern := namesPattern(names)
+	var diagnostics []Diagnostic
+	for _, item := range items {
+		// PF203 already reports constraints with vague filler terms
+		if parser.IsOverrideDirective(item.text) || vagueTermsRe.MatchString(strings.ToLower(item.text)) {
+			continue
+		}
+		result := scoreConstraint(item.text, pattern)
+
+		var severity Severity
+		var code string
+		switch {
+		case result.Score < config.ErrorBelow:
+			severity, code = SeverityError, "PF109"
+		case result.Score < config.WarnBelow:
+			severity, code = SeverityWarn, "PF208"
+		default:
+			continue
+		}
+
+		message := fmt.Sprintf("constraint is hard to verify (score %d/100): %s", result.Score, item.text)
+		if len(result.Suggestions) > 0 {
+			suggestions := result.Suggestions
+			if len(suggestions) > 2 {
+				suggestions = suggestions[:2]
+			}
+			message += "; " + strings.Join(suggestions, ", ")
+		}
+
+		score := result.Score
+		diagnostics = append(diagnostics, Diagnostic{
+			Severity: severity,
+			Code:     code,
+			Message:  message,
+			Line:     item.line,
+			Column:   1,
+			Score:    &score,
+		})
+	}
+	return diagnostics
+}
+
+// declaredNames returns the tool, tool parameter and variable names declared by a plan.
+func declaredNames(strippedLines []string, sectionBounds map[string]sectionInfo) []string {
+	var names []string
+	if info := firstSectionInfo(sectionBounds, "tools"); info != nil {
+		for _, tool := range parser.ParseToolsSection(strippedLines, info.startLine+1, info.endLine) {
+			names = append(names, tool.Name)
+			for _, param := range tool.Parameters {
+				names = append(names, param.Name)
+			}
+		}
+	}
+	if info := firstSectionInfo(sectionBounds, "variables"); info != nil {
+		variables, _ := parser.ParseVariablesSection(strippedLines, info.startLine+1, info.endLine)
+		for _, variable := range variables {
+			names = append(names, variable.Name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,82 @@
+package linter
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestScoreConstraint(t *testing.T) {
+	low := []string{"Be helpful", "Respond appropriately", "Be strict"}
+	high := []string{
+		"Must return JSON",
+		"Never share card numbers",
+		"Keep answers under 100 words",
+		"Set priority to one of low, medium or high",
+	}
+
+	for _, text := range low {
+		if result := ScoreConstraint(text, nil); result.Score >= DefaultConfig().Verifiability.WarnBelow {
+			t.Errorf("ScoreConstraint(%q) = %d, expected a low score", text, result.Score)
+		}
+	}
+	for _, text := range high {
+		if result := ScoreConstraint(text, nil); result.Score < DefaultConfig().Verifiability.WarnBelow {
+			t.Errorf("ScoreConstraint(%q) = %d, expected a passing score", text, result.Score)
+		}
+	}
+
+	result := ScoreConstraint("Respond appropriately", nil)
+	if len(result.Vague) != 1 || !strings.Contains(result.Suggestions[0], `"appropriately"`) {
+		t.Errorf("expected the vague term to lead the suggestions, got %+v", result)
+	}
+}
+
+func TestScoreConstraint_DeclaredNames(t *testing.T) {
+	without := ScoreConstraint("Must fill ticket priority", nil)
+	with := ScoreConstraint("Must fill ticket priority", []string{"priority"})
+	if with.Score <= without.Score {
+		t.Errorf("expected a declared name to raise the score: %d <= %d", with.Score, without.Score)
+	}
+}
+
+func TestLintPlan_LowVerifiability(t *testing.T) {
+	content := []byte("## Goal\nAnswer billing questions for Acme.\n\n## Constraints\n- Be helpful\n- Must reply in English\n\n## Out of Scope\n- Legal advice\n")
+
+	diags := LintPlan(content)
+	diag := findDiagnostic(diags, "PF208")
+	if diag == nil || diag.Line != 5 || diag.Score == nil || *diag.Score != 0 {
+		t.Fatalf("expected PF208 with a score on line 5, got %+v", diags)
+	}
+	if !strings.Contains(diag.Message, "Be helpful") || !strings.Contains(diag.Message, `replace "helpful"`) {
+		t.Errorf("unexpected message: %s", diag.Message)
+	}
+	if len(diags) != 1 {
+		t.Errorf("expected only the PF208 warning, got %+v", diags)
+	}
+
+	data, err := json.Marshal(diag)
+	if err != nil {
+		t.Fatalf("json.Marshal() failed: %v", err)
+	}
+	if !strings.Contains(string(data), `"score":0`) {
+		t.Errorf("expected the score in JSON output: %s", data)
+	}
+}
+
+func TestLintPlanWithConfig_Thresholds(t *testing.T) {
+	content := []byte("## Goal\nAnswer billing questions for Acme.\n\n## Constraints\n- Be helpful\n- Reply in English\n\n## Out of Scope\n- Legal advice\n")
+
+	disabled := LintPlanWithConfig(content, Config{})
+	if hasCode(disabled, "PF208") || hasCode(disabled, "PF109") {
+		t.Fatalf("expected no verifiability diagnostics when disabled, got %+v", disabled)
+	}
+
+	strict := LintPlanWithConfig(content, Config{Verifiability: VerifiabilityConfig{WarnBelow: 80, ErrorBelow: 10}})
+	if diag := findDiagnostic(strict, "PF109"); diag == nil || diag.Line != 5 || diag.Severity != SeverityError {
+		t.Fatalf("expected PF109 for line 5, got %+v", strict)
+	}
+	if diag := findDiagnostic(strict, "PF208"); diag == nil || diag.Line != 6 {
+		t.Fatalf("expected PF208 for line 6, got %+v", strict)
+	}
+}
@@ -0,0 +1,346 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// A plan can inherit from a base plan by naming it in front matter:
+//
+//	---
+//	extends: base-support.plan.md
+//	---
+//
+// The child inherits every section of the base. Constraints and Out of Scope are
+// merged item by item: child items are appended, and override directives edit
+// inherited items, naming them by IR identifier or by their exact text:
+//
+//   - ~remove constraint-xyz
+//   - ~replace constraint-xyz: New constraint text
+//
+// Any other section present in the child replaces the base section wholesale.
+var overrideRe = regexp.MustCompile(`^~(remove|replace)\s+(.+)$`)
+
+// mergedSections are merged item by item rather than replaced.
+var mergedSections = map[string]string{
+	"constraints":  "Constraints",
+	"out of scope": "Out of Scope",
+}
+
+// ItemIDFunc returns the IR identifiers the compiler assigns to the items of a
+// list section ("Constraints" or "Out of Scope"), in item order.
+type ItemIDFunc func(section string, items []string) []string
+
+// IsOverrideDirective reports whether a list item is a ~remove or ~replace directive.
+func IsOverrideDirective(item string) bool {
+	return overrideRe.MatchString(strings.TrimSpace(item))
+}
+
+// LoadPlan reads a plan file, expands its include directives and, when its front
+// matter names a base plan with extends, merges it over that base.
+// ids resolves override targets given by identifier; when nil, targets are matched
+// by item text only.
+//
+// Only failure to read the plan itself is returned as an error; unresolved includes,
+// base plans and override targets are collected in Source.Errors.
+func LoadPlan(path string, ids ItemIDFunc) (*Source, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return LoadPlanContent(path, content, ids), nil
+}
+
+// LoadPlanContent is like LoadPlan for plan content that was not read from path,
+// such as standard input. Includes and the base plan resolve relative to path.
+func LoadPlanContent(path string, content []byte, ids ItemIDFunc) *Source {
+	return loadPlanChain(path, content, ids, []string{absPath(path)})
+}
+
+func loadPlanChain(path string, content []byte, ids ItemIDFunc, chain []string) *Source {
+	source := ExpandIncludes(path, content)
+
+	extends, ok := ParseFrontMatter(source.Content).Lookup("extends")
+	if !ok {
+		return source
+	}
+
+	origin := source.Origin(extends.Line)
+	fail := func(format string, args ...interface{}) *Source {
+		source.Errors = append(source.Errors, &SourceError{
+			Kind:    SourceErrorExtends,
+			File:    origin.File,
+			Line:    origin.Line,
+			Message: fmt.Sprintf(format, args...),
+		})
+		return source
+	}
+
+	if extends.Value == "" {
+		return fail("extends requires a base plan path")
+	}
+
+	basePath := resolveInclude(origin.File, extends.Value)
+	absBase := absPath(basePath)
+	if cycle := indexOf(chain, absBase); cycle != -1 {
+		names := append(append([]string{}, chain[cycle:]...), absBase)
+		for i := range names {
+			names[i] = filepath.Base(names[i])
+		}
+		return fail("extends cycle: %s", strings.Join(names, " -> "))
+	}
+
+	baseContent, err := os.ReadFile(basePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fail("base plan not found: %s", basePath)
+		}
+		return fail("cannot read base plan %s: %v", basePath, err)
+	}
+
+	base := loadPlanChain(basePath, baseContent, ids, append(chain, absBase))
+	return mergePlans(base, source, ids)
+}
+
+// planSection is a "##" section of a plan, by 1-based line numbers.
+type planSection struct {
+	key     string
+	heading int
+	end     int
+}
+
+// planSections lists the sections of stripped plan lines in file order. A section
+// body ends before the next heading or a "---" separator.
+func planSections(stripped []string) []planSection {
+	headingRe := regexp.MustCompile(`^##\s+(.+?)\s*$`)
+
+	var sections []planSection
+	for i, line := range stripped {
+		match := headingRe.FindStringSubmatch(strings.TrimSpace(line))
+		if match == nil {
+			continue
+		}
+		if len(sections) > 0 && sections[len(sections)-1].end == 0 {
+			sections[len(sections)-1].end = i
+		}
+		sections = append(sections, planSection{key: strings.ToLower(match[1]), heading: i + 1})
+	}
+	if len(sections) > 0 && sections[len(sections)-1].end == 0 {
+		sections[len(sections)-1].end = len(stripped)
+	}
+
+	for i := range sections {
+		for idx := sections[i].heading + 1; idx <= sections[i].end; idx++ {
+			if strings.TrimSpace(stripped[idx-1]) == "---" {
+				sections[i].end = idx - 1
+				break
+			}
+		}
+	}
+
+	return sections
+}
+
+// mergedPlan accumulates the lines of a merged plan with their origins.
+type mergedPlan struct {
+	lines []sourceText
+}
+
+func (m *mergedPlan) copyLines(source *Source, lines []string, from, to int) {
+	for idx := from; idx <= to && idx <= len(lines); idx++ {
+		m.lines = append(m.lines, sourceText{text: lines[idx-1], origin: source.Origin(idx)})
+	}
+}
+
+// mergePlans merges child over base. Lines keep the origin they had in their own
+// source, so diagnostics and explain output point at the base or child file.
+func mergePlans(base, child *Source, ids ItemIDFunc) *Source {
+	merged := &Source{
+		Path:   child.Path,
+		Bases:  append([]string{base.Path}, base.Bases...),
+		Errors: append(append([]*SourceError{}, child.Errors...), base.Errors...),
+	}
+
+	seenInclude := make(map[string]bool)
+	for _, path := range append(append(append([]string{}, child.Includes...), base.Path), base.Includes...) {
+		if key := absPath(path); !seenInclude[key] {
+			seenInclude[key] = true
+			merged.Includes = append(merged.Includes, path)
+		}
+	}
+
+	baseLines := strings.Split(string(base.Content), "\n")
+	childLines := strings.Split(string(child.Content), "\n")
+	baseStripped := stripComments(baseLines)
+	childStripped := stripComments(childLines)
+	baseSections := planSections(baseStripped)
+	childSections := planSections(childStripped)
+
+	var out mergedPlan
+
+	// The child's preamble (front matter and title) comes first.
+	preambleEnd := len(childLines)
+	if len(childSections) > 0 {
+		preambleEnd = childSections[0].heading - 1
+	}
+	out.copyLines(child, childLines, 1, preambleEnd)
+
+	childUsed := make([]bool, len(childSections))
+	findChild := func(key string) int {
+		for i, section := range childSections {
+			if section.key == key && !childUsed[i] {
+				return i
+			}
+		}
+		return -1
+	}
+
+	baseSeen := make(map[string]bool)
+	for _, baseSection := range baseSections {
+		if baseSeen[baseSection.key] {
+			// A repeated base section is kept as-is so lint still reports it.
+			out.copyLines(base, baseLines, baseSection.heading, baseSection.end)
+			continue
+		}
+		baseSeen[baseSection.key] = true
+
+		childIndex := findChild(baseSection.key)
+		if childIndex != -1 {
+			childUsed[childIndex] = true
+		}
+
+		name, isList := mergedSections[baseSection.key]
+		switch {
+		case isList:
+			baseItems := parseListWithLines(baseStripped, baseSection.heading+1, baseSection.end)
+			var childItems []PlanItem
+			if childIndex != -1 {
+				childSection := childSections[childIndex]
+				childItems = parseListWithLines(childStripped, childSection.heading+1, childSection.end)
+			}
+			out.copyLines(base, baseLines, baseSection.heading, baseSection.heading)
+			merged.Errors = append(merged.Errors, out.mergeItems(name, base, baseItems, child, childItems, ids)...)
+		case childIndex != -1:
+			childSection := childSections[childIndex]
+			out.copyLines(child, childLines, childSection.heading, childSection.end)
+		default:
+			out.copyLines(base, baseLines, baseSection.heading, baseSection.end)
+		}
+	}
+
+	// Sections only the child declares keep their place at the end. List sections
+	// still go through the merge so stray override directives are reported.
+	for i, childSection := range childSections {
+		if childUsed[i] {
+			continue
+		}
+		name, isList := mergedSections[childSection.key]
+		if isList && !baseSeen[childSection.key] {
+			baseSeen[childSection.key] = true
+			childItems := parseListWithLines(childStripped, childSection.heading+1, childSection.end)
+			out.copyLines(child, childLines, childSection.heading, childSection.heading)
+			merged.Errors = append(merged.Errors, out.mergeItems(name, base, nil, child, childItems, ids)...)
+			continue
+		}
+		out.copyLines(child, childLines, childSection.heading, childSection.end)
+	}
+
+	texts := make([]string, len(out.lines))
+	merged.Lines = make([]SourceLine, len(out.lines))
+	for i, line := range out.lines {
+		texts[i] = line.text
+		merged.Lines[i] = line.origin
+	}
+	merged.Content = []byte(strings.Join(texts, "\n"))
+
+	return merged
+}
+
+// mergeItems writes the base items of a list section with the child's additions
+// and overrides applied, and returns errors for overrides that match nothing.
+func (m *mergedPlan) mergeItems(section string, base *Source, baseItems []PlanItem, child *Source, childItems []PlanItem, ids ItemIDFunc) []*SourceError {
+	type entry struct {
+		text    string
+		origin  SourceLine
+		removed bool
+	}
+
+	entries := make([]entry, len(baseItems))
+	texts := make([]string, len(baseItems))
+	for i, item := range baseItems {
+		entries[i] = entry{text: item.Text, origin: base.Origin(item.Line)}
+		texts[i] = item.Text
+	}
+
+	var itemIDs []string
+	if ids != nil && len(texts) > 0 {
+		itemIDs = ids(section, texts)
+	}
+
+	find := func(target string) int {
+		for i, text := range texts {
+			if entries[i].removed {
+				continue
+			}
+			if strings.EqualFold(text, target) || (i < len(itemIDs) && strings.EqualFold(itemIDs[i], target)) {
+				return i
+			}
+		}
+		return -1
+	}
+
+	var errs []*SourceError
+	var added []entry
+	for _, item := range childItems {
+		origin := child.Origin(item.Line)
+		match := overrideRe.FindStringSubmatch(item.Text)
+		if match == nil {
+			added = append(added, entry{text: item.Text, origin: origin})
+			continue
+		}
+
+		fail := func(format string, args ...interface{}) {
+			errs = append(errs, &SourceError{
+				Kind:    SourceErrorExtends,
+				File:    origin.File,
+				Line:    origin.Line,
+				Message: fmt.Sprintf(format, args...),
+			})
+		}
+
+		action, target, replacement := match[1], strings.TrimSpace(match[2]), ""
+		if action == "replace" {
+			var ok bool
+			target, replacement, ok = strings.Cut(target, ":")
+			target, replacement = strings.TrimSpace(target), strings.TrimSpace(replacement)
+			if !ok || target == "" || replacement == "" {
+				fail("~replace expects 'target: new text' in %s", section)
+				continue
+			}
+		}
+
+		index := find(target)
+		if index == -1 {
+			fail("~%s target not found in base %s: %s", action, section, target)
+			continue
+		}
+		if action == "remove" {
+			entries[index].removed = true
+			continue
+		}
+		entries[index].text = replacement
+		entries[index].origin = origin
+	}
+
+	for _, e := range append(entries, added...) {
+		if e.removed {
+			continue
+		}
+		m.lines = append(m.lines, sourceText{text: "- " + e.text, origin: e.origin})
+	}
+
+	return errs
+}
@@ -0,0 +1,141 @@
+package parser
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseFrontMatter(t *testing.T) {
+	content := "---\nextends: base.plan.md\nName: Triage\nnot a pair\n---\n# Prompt Plan\n"
+
+	frontMatter := ParseFrontMatter([]byte(content))
+	if frontMatter == nil {
+		t.Fatal("Expected front matter")
+	}
+	if frontMatter.EndLine != 5 {
+		t.Errorf("Expected EndLine 5, got %d", frontMatter.EndLine)
+	}
+	if got := frontMatter.Get("extends"); got != "base.plan.md" {
+		t.Errorf("Expected extends base.plan.md, got %q", got)
+	}
+	if entry, ok := frontMatter.Lookup("name"); !ok || entry.Value != "Triage" || entry.Line != 3 {
+		t.Errorf("Unexpected name entry: %+v", entry)
+	}
+
+	if ParseFrontMatter([]byte("---\nextends: base.plan.md\n")) != nil {
+		t.Error("Unterminated front matter should be ignored")
+	}
+	if ParseFrontMatter([]byte("# Prompt Plan\n---\n")) != nil {
+		t.Error("Front matter must start on the first line")
+	}
+}
+
+func itemIDs(section string, items []string) []string {
+	ids := make([]string, len(items))
+	for i := range items {
+		ids[i] = strings.ToLower(strings.ReplaceAll(section, " ", "-")) + "-" + string(rune('a'+i))
+	}
+	return ids
+}
+
+func TestLoadPlan_Extends(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "base-support.plan.md"), strings.Join([]string{
+		"# Base",
+		"## Goal",
+		"Answer support questions",
+		"## Constraints",
+		"- Reply in English",
+		"- Be concise",
+		"- Never guess order numbers",
+		"## Out of Scope",
+		"- Refunds",
+	}, "\n"))
+	planPath := filepath.Join(dir, "plan.md")
+	writeFile(t, planPath, strings.Join([]string{
+		"---",
+		"extends: base-support.plan.md",
+		"---",
+		"## Constraints",
+		"- ~remove constraints-b",
+		"- ~replace Reply in English: Reply in the customer's language",
+		"- Escalate legal threats",
+		"## Out of Scope",
+		"- Password resets",
+	}, "\n"))
+
+	source, err := LoadPlan(planPath, itemIDs)
+	if err != nil {
+		t.Fatalf("LoadPlan() failed: %v", err)
+	}
+	if err := source.Err(); err != nil {
+		t.Fatalf("Unexpected source error: %v", err)
+	}
+
+	plan, err := ParsePlanWithLines(source.Content)
+	if err != nil {
+		t.Fatalf("ParsePlanWithLines() failed: %v\n%s", err, source.Content)
+	}
+	if plan.Plan.Goal != "Answer support questions" {
+		t.Errorf("Goal should be inherited, got %q", plan.Plan.Goal)
+	}
+
+	wantConstraints := []string{"Reply in the customer's language", "Never guess order numbers", "Escalate legal threats"}
+	if strings.Join(plan.Plan.Constraints, "|") != strings.Join(wantConstraints, "|") {
+		t.Errorf("Constraints = %q, want %q", plan.Plan.Constraints, wantConstraints)
+	}
+	if strings.Join(plan.Plan.OutOfScope, "|") != "Refunds|Password resets" {
+		t.Errorf("Unexpected Out of Scope: %q", plan.Plan.OutOfScope)
+	}
+
+	basePath := filepath.Join(dir, "base-support.plan.md")
+	origins := []SourceLine{
+		{File: planPath, Line: 6, Plan: planPath},
+		{File: basePath, Line: 7, Plan: basePath},
+		{File: planPath, Line: 7, Plan: planPath},
+	}
+	for i, item := range plan.Constraints {
+		if got := source.Origin(item.Line); got != origins[i] {
+			t.Errorf("Origin of %q = %+v, want %+v", item.Text, got, origins[i])
+		}
+	}
+
+	if len(source.Bases) != 1 || source.Bases[0] != basePath {
+		t.Errorf("Expected base %s, got %v", basePath, source.Bases)
+	}
+	if len(source.Includes) != 1 || source.Includes[0] != basePath {
+		t.Errorf("Base plan should be tracked as an included file, got %v", source.Includes)
+	}
+}
+
+func TestLoadPlan_ExtendsErrors(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "base.md"), "---\nextends: plan.md\n---\n## Goal\nBase goal\n## Constraints\n- Be kind\n")
+	planPath := filepath.Join(dir, "plan.md")
+	writeFile(t, planPath, "---\nextends: base\n---\n## Constraints\n- ~remove constraint-missing\n- ~replace Be kind\n")
+
+	source, err := LoadPlan(planPath, nil)
+	if err != nil {
+		t.Fatalf("LoadPlan() failed: %v", err)
+	}
+
+	var messages []string
+	for _, sourceErr := range source.Errors {
+		if sourceErr.Kind != SourceErrorExtends {
+			t.Errorf("Unexpected error kind for %v", sourceErr)
+		}
+		messages = append(messages, sourceErr.Error())
+	}
+	joined := strings.Join(messages, "\n")
+
+	for _, want := range []string{
+		"base.md:2: extends cycle: plan.md -> base.md -> plan.md",
+		"plan.md:5: ~remove target not found in base Constraints: constraint-missing",
+		"plan.md:6: ~replace expects 'target: new text' in Constraints",
+	} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("Expected error %q in:\n%s", want, joined)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package parser
+
+import (
+	"strings"
+)
+
+// FrontMatterEntry is a single "key: value" line of plan front matter.
+type FrontMatterEntry struct {
+	Key   string
+	Value string
+	Line  int
+}
+
+// FrontMatter is the optional metadata block at the top of a plan:
+//
+//	---
+//	extends: base-support.plan.md
+//	---
+//
+// Keys are case-insensitive and may repeat; entries keep their file order.
+type FrontMatter struct {
+	Entries []FrontMatterEntry
+
+	// EndLine is the 1-based line of the closing "---".
+	EndLine int
+}
+
+// ParseFrontMatter returns the front matter at the start of content, or nil if
+// content does not open with a "---" line followed by a closing "---" line.
+// Blank lines and lines without a colon inside the block are ignored.
+func ParseFrontMatter(content []byte) *FrontMatter {
+	lines := strings.Split(normalizeNewlines(string(content)), "\n")
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return nil
+	}
+
+	frontMatter := &FrontMatter{}
+	for i := 1; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "---" {
+			frontMatter.EndLine = i + 1
+			return frontMatter
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		if key == "" {
+			continue
+		}
+		frontMatter.Entries = append(frontMatter.Entries, FrontMatterEntry{
+			Key:   key,
+			Value: strings.TrimSpace(value),
+			Line:  i + 1,
+		})
+	}
+
+	return nil
+}
+
+// Lookup returns the first entry for key.
+func (f *FrontMatter) Lookup(key string) (FrontMatterEntry, bool) {
+	if f == nil {
+		return FrontMatterEntry{}, false
+	}
+	key = strings.ToLower(key)
+	for _, entry := range f.Entries {
+		if entry.Key == key {
+			return entry, true
+		}
+	}
+	return FrontMatterEntry{}, false
+}
+
+// Get returns the value of the first entry for key, or "" if there is none.
+func (f *FrontMatter) Get(key string) string {
+	entry, _ := f.Lookup(key)
+	return entry.Value
+}
+
+// All returns every entry for key, in file order.
+func (f *FrontMatter) All(key string) []FrontMatterEntry {
+	if f == nil {
+		return nil
+	}
+	key = strings.ToLower(key)
+	var entries []FrontMatterEntry
+	for _, entry := range f.Entries {
+		if entry.Key == key {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
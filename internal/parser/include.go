@@ -0,0 +1,213 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/promptforge/promptforge"
+)
+
+// Include directives pull shared plan fragments into a plan. Two forms are supported:
+//
+//	<!-- @include ../shared/pii.md -->
+//	- @include shared/pii
+//
+// Paths are resolved relative to the file containing the directive; ".md" is appended
+// when the path has no extension. The included lines replace the directive line.
+var (
+	includeCommentRe = regexp.MustCompile(`^\s*<!--\s*@include\s+(\S+)\s*-->\s*$`)
+	includeBulletRe  = regexp.MustCompile(`^\s*(?:[-*•]|\d+\.)\s+@include\s+(\S+)\s*$`)
+)
+
+// SourceLine identifies the file and line an expanded plan line came from.
+type SourceLine struct {
+	File string
+	Line int
+
+	// Plan is the plan file that File belongs to: the plan itself, or a base plan
+	// named by extends. It differs from File for lines pulled in by includes.
+	Plan string
+}
+
+// SourceErrorKind classifies a SourceError.
+type SourceErrorKind int
+
+const (
+	// SourceErrorInclude marks an include directive that could not be resolved.
+	SourceErrorInclude SourceErrorKind = iota
+	// SourceErrorExtends marks a base plan or override directive that could not be resolved.
+	SourceErrorExtends
+)
+
+// SourceError describes a directive that could not be resolved while loading a plan.
+type SourceError struct {
+	Kind    SourceErrorKind
+	File    string
+	Line    int
+	Message string
+}
+
+func (e *SourceError) Error() string {
+	return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Message)
+}
+
+// Source is plan content with include directives expanded.
+type Source struct {
+	// Path is the plan file the source was loaded from.
+	Path string
+
+	// Content is the expanded plan content.
+	Content []byte
+
+	// Lines maps each expanded line (index = line-1) back to its origin.
+	Lines []SourceLine
+
+	// Includes lists the files pulled in by include directives or extends, in
+	// first-inclusion order.
+	Includes []string
+
+	// Bases lists the base plans named by extends, nearest first.
+	Bases []string
+
+	// Errors lists directives that could not be resolved. They are left unexpanded.
+	Errors []*SourceError
+}
+
+// Origin returns the file and line that an expanded (1-based) line came from.
+func (s *Source) Origin(line int) SourceLine {
+	if line < 1 || line > len(s.Lines) {
+		return SourceLine{File: s.Path, Line: line, Plan: s.Path}
+	}
+	return s.Lines[line-1]
+}
+
+// Err returns the first source error, or nil if every directive was resolved.
+// The error is a *promptforge.Error with code CodePlanInclude and the position
+// of the directive.
+func (s *Source) Err() error {
+	if len(s.Errors) == 0 {
+		return nil
+	}
+	first := s.Errors[0]
+	return &promptforge.Error{
+		Code:    promptforge.CodePlanInclude,
+		Message: first.Message,
+		File:    first.File,
+		Line:    first.Line,
+	}
+}
+
+// ExpandIncludes expands include directives in content, which was read from path.
+func ExpandIncludes(path string, content []byte) *Source {
+	source := &Source{Path: path}
+	seen := make(map[string]bool)
+
+	lines := expandFile(source, path, content, []string{absPath(path)}, seen)
+
+	out := make([]string, len(lines))
+	source.Lines = make([]SourceLine, len(lines))
+	for i, line := range lines {
+		out[i] = line.text
+		source.Lines[i] = line.origin
+		source.Lines[i].Plan = path
+	}
+	source.Content = []byte(strings.Join(out, "\n"))
+
+	return source
+}
+
+type sourceText struct {
+	text   string
+	origin SourceLine
+}
+
+func expandFile(source *Source, path string, content []byte, stack []string, seen map[string]bool) []sourceText {
+	lines := strings.Split(normalizeNewlines(string(content)), "\n")
+	var out []sourceText
+
+	for i, line := range lines {
+		origin := SourceLine{File: path, Line: i + 1}
+
+		match := includeCommentRe.FindStringSubmatch(line)
+		if match == nil {
+			match = includeBulletRe.FindStringSubmatch(line)
+		}
+		if match == nil {
+			out = append(out, sourceText{text: line, origin: origin})
+			continue
+		}
+
+		target := resolveInclude(path, match[1])
+		absTarget := absPath(target)
+		fail := func(format string, args ...interface{}) {
+			source.Errors = append(source.Errors, &SourceError{
+				Kind:    SourceErrorInclude,
+				File:    path,
+				Line:    i + 1,
+				Message: fmt.Sprintf(format, args...),
+			})
+			out = append(out, sourceText{text: line, origin: origin})
+		}
+
+		if cycle := indexOf(stack, absTarget); cycle != -1 {
+			chain := append(append([]string{}, stack[cycle:]...), absTarget)
+			for j := range chain {
+				chain[j] = filepath.Base(chain[j])
+			}
+			fail("include cycle: %s", strings.Join(chain, " -> "))
+			continue
+		}
+
+		included, err := os.ReadFile(target)
+		if err != nil {
+			if os.IsNotExist(err) {
+				fail("included file not found: %s", target)
+			} else {
+				fail("cannot read included file %s: %v", target, err)
+			}
+			continue
+		}
+
+		if !seen[absTarget] {
+			seen[absTarget] = true
+			source.Includes = append(source.Includes, target)
+		}
+
+		included = []byte(strings.TrimSuffix(normalizeNewlines(string(included)), "\n"))
+		out = append(out, expandFile(source, target, included, append(stack, absTarget), seen)...)
+	}
+
+	return out
+}
+
+// resolveInclude resolves an include target relative to the including file.
+func resolveInclude(fromPath, target string) string {
+	if filepath.Ext(target) == "" {
+		target += ".md"
+	}
+	target = filepath.FromSlash(target)
+	if filepath.IsAbs(target) {
+		return filepath.Clean(target)
+	}
+	return filepath.Join(filepath.Dir(fromPath), target)
+}
+
+func absPath(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return filepath.Clean(path)
+	}
+	return abs
+}
+
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
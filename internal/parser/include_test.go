@@ -0,0 +1,99 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+}
+
+func TestExpandIncludes_BothForms(t *testing.T) {
+	dir := t.TempDir()
+	planPath := filepath.Join(dir, "promptforge", "plan.md")
+	writeFile(t, filepath.Join(dir, "shared", "pii.md"), "- Never store PII\n- Mask emails\n")
+	writeFile(t, filepath.Join(dir, "promptforge", "shared", "tone.md"), "- Be polite\n")
+	writeFile(t, planPath, strings.Join([]string{
+		"# Prompt Plan",
+		"## Goal",
+		"Help customers",
+		"## Constraints",
+		"<!-- @include ../shared/pii.md -->",
+		"- @include shared/tone",
+		"- Reply in English",
+	}, "\n"))
+
+	source, err := LoadPlan(planPath, nil)
+	if err != nil {
+		t.Fatalf("LoadPlan() failed: %v", err)
+	}
+	if err := source.Err(); err != nil {
+		t.Fatalf("Unexpected include error: %v", err)
+	}
+
+	plan, err := ParsePlanWithLines(source.Content)
+	if err != nil {
+		t.Fatalf("ParsePlanWithLines() failed: %v", err)
+	}
+	if len(plan.Constraints) != 4 {
+		t.Fatalf("Expected 4 constraints, got %+v", plan.Constraints)
+	}
+
+	origin := source.Origin(plan.Constraints[1].Line)
+	if filepath.Base(origin.File) != "pii.md" || origin.Line != 2 {
+		t.Errorf("Mask emails should map to pii.md:2, got %+v", origin)
+	}
+	origin = source.Origin(plan.Constraints[3].Line)
+	if origin.File != planPath || origin.Line != 7 {
+		t.Errorf("Reply in English should map to plan.md:7, got %+v", origin)
+	}
+	if len(source.Includes) != 2 {
+		t.Errorf("Expected 2 included files, got %v", source.Includes)
+	}
+}
+
+func TestExpandIncludes_Cycle(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a.md"), "- @include b\n")
+	writeFile(t, filepath.Join(dir, "b.md"), "- @include a.md\n")
+	planPath := filepath.Join(dir, "plan.md")
+	writeFile(t, planPath, "## Constraints\n- @include a\n")
+
+	source, err := LoadPlan(planPath, nil)
+	if err != nil {
+		t.Fatalf("LoadPlan() failed: %v", err)
+	}
+	if len(source.Errors) != 1 {
+		t.Fatalf("Expected 1 include error, got %v", source.Errors)
+	}
+	includeErr := source.Errors[0]
+	if !strings.Contains(includeErr.Message, "cycle: a.md -> b.md -> a.md") {
+		t.Errorf("Unexpected cycle message: %s", includeErr.Message)
+	}
+	if filepath.Base(includeErr.File) != "b.md" || includeErr.Line != 1 {
+		t.Errorf("Cycle should be reported at b.md:1, got %s:%d", includeErr.File, includeErr.Line)
+	}
+}
+
+func TestExpandIncludes_MissingFile(t *testing.T) {
+	dir := t.TempDir()
+	planPath := filepath.Join(dir, "plan.md")
+	content := "## Constraints\n- @include missing\n"
+
+	source := ExpandIncludes(planPath, []byte(content))
+	if source.Err() == nil {
+		t.Fatal("Expected an error for a missing include")
+	}
+	if string(source.Content) != strings.TrimSuffix(content, "\n")+"\n" {
+		t.Errorf("Unresolved directives should be left in place, got %q", source.Content)
+	}
+}
@@ -0,0 +1,53 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	listMarkerRe = regexp.MustCompile(`^(?:[-*•]|\d+\.)\s+`)
+)
+
+// ListItems returns the items of a list in lines, which are 1-based and inclusive
+// between startLine and endLine. Items may be marked with "-", "*", "•" or "1.",
+// or be plain lines. An indented line without a marker that directly follows an
+// item continues it, so long items can be wrapped onto lines indented by two
+// spaces. Each item's Line is the line it starts on and EndLine the line it ends
+// on. Duplicates are kept.
+func ListItems(lines []string, startLine, endLine int) []PlanItem {
+	if startLine < 1 || endLine < startLine {
+		return nil
+	}
+
+	var items []PlanItem
+	continuable := false
+
+	for idx := startLine; idx <= endLine && idx <= len(lines); idx++ {
+		raw := lines[idx-1]
+		line := strings.TrimSpace(raw)
+		if line == "" {
+			continuable = false
+			continue
+		}
+
+		marked := listMarkerRe.MatchString(line)
+		indented := strings.HasPrefix(raw, " ") || strings.HasPrefix(raw, "\t")
+		if continuable && indented && !marked {
+			items[len(items)-1].Text += " " + line
+			items[len(items)-1].EndLine = idx
+			continue
+		}
+
+		text := strings.TrimSpace(listMarkerRe.ReplaceAllString(line, ""))
+		if text == "" {
+			continuable = false
+			continue
+		}
+
+		items = append(items, PlanItem{Text: text, Line: idx, EndLine: idx})
+		continuable = true
+	}
+
+	return items
+}
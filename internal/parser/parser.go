@@ -1,9 +1,10 @@
 package parser
 
 import (
-	"fmt"
 	"regexp"
 	"strings"
+
+	"github.com/promptforge/promptforge"
 )
 
 // Plan represents the parsed structure of plan.md
@@ -11,12 +12,17 @@ type Plan struct {
 	Goal        string
 	Constraints []string
 	OutOfScope  []string
+	Tools       []Tool
+	Variables   []Parameter
 }
 
 // PlanItem represents a parsed list item with line information.
 type PlanItem struct {
 	Text string
 	Line int
+
+	// EndLine is the last line of the item, after any wrapped continuation lines.
+	EndLine int
 }
 
 // PlanWithLines includes line numbers for explain and lint workflows.
@@ -25,13 +31,18 @@ type PlanWithLines struct {
 	GoalLine    int
 	Constraints []PlanItem
 	OutOfScope  []PlanItem
+	Tools       []Tool
+	Variables   []Parameter
+
+	// VariableErrors lists Variables bullets that could not be parsed.
+	VariableErrors []ParameterError
 }
 
 // ParsePlan extracts structured data from plan.md content.
 // Returns an error if the Goal section is missing or if the content is invalid.
 func ParsePlan(content []byte) (*Plan, error) {
 	if len(content) == 0 {
-		return nil, fmt.Errorf("plan content is empty")
+		return nil, promptforge.Errorf(promptforge.CodePlanEmpty, "plan content is empty")
 	}
 
 	contentStr := string(content)
@@ -43,7 +54,7 @@ func ParsePlan(content []byte) (*Plan, error) {
 	// Extract Goal section (required)
 	goalLine, goalText := extractSectionWithLine(contentStr, "Goal")
 	if goalText == "" {
-		return nil, fmt.Errorf("Goal section is required but not found. Expected: ## Goal")
+		return nil, errGoalMissing()
 	}
 
 	// Extract Constraints section (optional, but warn if completely missing)
@@ -57,20 +68,39 @@ func ParsePlan(content []byte) (*Plan, error) {
 	// Trim and validate Goal
 	goal := strings.TrimSpace(goalText)
 	if goal == "" {
-		return nil, fmt.Errorf("Goal section is empty at line %d. Please provide a goal description", goalLine)
+		return nil, errGoalEmpty(goalLine)
 	}
 
+	strippedLines := stripComments(strings.Split(contentStr, "\n"))
+	variables, _ := parseVariables(strippedLines)
+
 	return &Plan{
 		Goal:        goal,
 		Constraints: constraints,
 		OutOfScope:  outOfScope,
+		Tools:       parseTools(strippedLines),
+		Variables:   variables,
 	}, nil
 }
 
+func errGoalMissing() error {
+	return promptforge.Errorf(promptforge.CodePlanSyntax, "Goal section is required but not found").WithHint("Expected: ## Goal")
+}
+
+// errGoalEmpty reports an empty Goal section whose heading is on line.
+func errGoalEmpty(line int) error {
+	return &promptforge.Error{
+		Code:    promptforge.CodePlanSyntax,
+		Message: "Goal section is empty",
+		Line:    line,
+		Hint:    "Please provide a goal description",
+	}
+}
+
 // ParsePlanWithLines extracts structured data from plan.md content with line numbers.
 func ParsePlanWithLines(content []byte) (*PlanWithLines, error) {
 	if len(content) == 0 {
-		return nil, fmt.Errorf("plan content is empty")
+		return nil, promptforge.Errorf(promptforge.CodePlanEmpty, "plan content is empty")
 	}
 
 	contentStr := normalizeNewlines(string(content))
@@ -79,7 +109,7 @@ func ParsePlanWithLines(content []byte) (*PlanWithLines, error) {
 
 	startLine, endLine, found := sectionRange(strippedLines, "Goal")
 	if !found {
-		return nil, fmt.Errorf("Goal section is required but not found. Expected: ## Goal")
+		return nil, errGoalMissing()
 	}
 
 	goalLine := -1
@@ -97,7 +127,7 @@ func ParsePlanWithLines(content []byte) (*PlanWithLines, error) {
 
 	goal := strings.TrimSpace(strings.Join(goalLines, "\n"))
 	if goal == "" {
-		return nil, fmt.Errorf("Goal section is empty at line %d. Please provide a goal description", startLine)
+		return nil, errGoalEmpty(startLine - 1)
 	}
 
 	constraintsStart, constraintsEnd, constraintsFound := sectionRange(strippedLines, "Constraints")
@@ -112,20 +142,37 @@ func ParsePlanWithLines(content []byte) (*PlanWithLines, error) {
 		outOfScope = parseListWithLines(strippedLines, outStart, outEnd)
 	}
 
+	tools := parseTools(strippedLines)
+	variables, variableErrors := parseVariables(strippedLines)
+
 	plan := &Plan{
 		Goal:        goal,
 		Constraints: toTextList(constraints),
 		OutOfScope:  toTextList(outOfScope),
+		Tools:       tools,
+		Variables:   variables,
 	}
 
 	return &PlanWithLines{
-		Plan:        plan,
-		GoalLine:    goalLine,
-		Constraints: constraints,
-		OutOfScope:  outOfScope,
+		Plan:           plan,
+		GoalLine:       goalLine,
+		Constraints:    constraints,
+		OutOfScope:     outOfScope,
+		Tools:          tools,
+		Variables:      variables,
+		VariableErrors: variableErrors,
 	}, nil
 }
 
+// parseTools extracts tool declarations from the Tools section, if present.
+func parseTools(strippedLines []string) []Tool {
+	start, end, found := sectionRange(strippedLines, "Tools")
+	if !found {
+		return nil
+	}
+	return ParseToolsSection(strippedLines, start, end)
+}
+
 // extractSection extracts content from a markdown section.
 // Returns empty string if section is not found.
 func extractSection(content, sectionName string) string {
@@ -190,6 +237,12 @@ func normalizeNewlines(input string) string {
 	return strings.ReplaceAll(input, "\r", "\n")
 }
 
+// StripComments returns lines with HTML comments (which may span lines) blanked out,
+// keeping line numbers intact.
+func StripComments(lines []string) []string {
+	return stripComments(lines)
+}
+
 func stripComments(lines []string) []string {
 	result := make([]string, len(lines))
 	inComment := false
@@ -226,6 +279,8 @@ func stripComments(lines []string) []string {
 	return result
 }
 
+// sectionRange returns the 1-based, inclusive line range of a section's body
+// (excluding its heading).
 func sectionRange(lines []string, sectionName string) (int, int, bool) {
 	headerPattern := regexp.MustCompile(`(?i)^##\s+` + regexp.QuoteMeta(sectionName) + `\s*$`)
 	nextHeaderPattern := regexp.MustCompile(`^##\s+`)
@@ -233,7 +288,7 @@ func sectionRange(lines []string, sectionName string) (int, int, bool) {
 	sectionStart := -1
 	for i, line := range lines {
 		if headerPattern.MatchString(strings.TrimSpace(line)) {
-			sectionStart = i + 1
+			sectionStart = i + 2
 			break
 		}
 	}
@@ -242,7 +297,7 @@ func sectionRange(lines []string, sectionName string) (int, int, bool) {
 	}
 
 	sectionEnd := len(lines)
-	for i := sectionStart; i < len(lines); i++ {
+	for i := sectionStart - 1; i < len(lines); i++ {
 		line := lines[i]
 		if nextHeaderPattern.MatchString(strings.TrimSpace(line)) {
 			sectionEnd = i
@@ -257,37 +312,19 @@ func sectionRange(lines []string, sectionName string) (int, int, bool) {
 	return sectionStart, sectionEnd, true
 }
 
+// parseListWithLines returns the list items between startLine and endLine, dropping
+// case-insensitive duplicates.
 func parseListWithLines(lines []string, startLine, endLine int) []PlanItem {
-	if startLine < 1 || endLine < startLine {
-		return []PlanItem{}
-	}
-
-	var items []PlanItem
+	items := []PlanItem{}
 	seen := make(map[string]bool)
 
-	for idx := startLine; idx <= endLine && idx <= len(lines); idx++ {
-		line := strings.TrimSpace(lines[idx-1])
-		if line == "" {
-			continue
-		}
-
-		line = regexp.MustCompile(`^[\s]*[-*ƒ?›]\s+`).ReplaceAllString(line, "")
-		line = regexp.MustCompile(`^\d+\.\s+`).ReplaceAllString(line, "")
-		line = strings.TrimSpace(line)
-		if line == "" {
+	for _, item := range ListItems(lines, startLine, endLine) {
+		lowerText := strings.ToLower(item.Text)
+		if seen[lowerText] {
 			continue
 		}
-
-		lowerLine := strings.ToLower(line)
-		if seen[lowerLine] {
-			continue
-		}
-		seen[lowerLine] = true
-
-		items = append(items, PlanItem{
-			Text: line,
-			Line: idx,
-		})
+		seen[lowerText] = true
+		items = append(items, item)
 	}
 
 	return items
@@ -320,37 +357,21 @@ func parseList(text string) []string {
 	var items []string
 	seen := make(map[string]bool) // Track duplicates
 
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-
-		// Skip empty lines
-		if line == "" {
-			continue
-		}
-
+	// Markers (-, *, •, 1.) are removed and wrapped items joined by ListItems
+	for _, item := range ListItems(lines, 1, len(lines)) {
 		// Skip HTML comments (including multi-line)
-		if strings.HasPrefix(line, "<!--") {
-			continue
-		}
-
-		// Remove markdown list markers (-, *, •, 1., etc.)
-		line = regexp.MustCompile(`^[\s]*[-*•]\s+`).ReplaceAllString(line, "")
-		line = regexp.MustCompile(`^\d+\.\s+`).ReplaceAllString(line, "")
-		line = strings.TrimSpace(line)
-
-		// Skip if empty after processing
-		if line == "" {
+		if strings.HasPrefix(item.Text, "<!--") {
 			continue
 		}
 
 		// Skip duplicates (case-insensitive)
-		lowerLine := strings.ToLower(line)
+		lowerLine := strings.ToLower(item.Text)
 		if seen[lowerLine] {
 			continue
 		}
 		seen[lowerLine] = true
 
-		items = append(items, line)
+		items = append(items, item.Text)
 	}
 
 	// If no items found but text exists (and wasn't just comments), treat entire text as one item
@@ -1,7 +1,11 @@
 package parser
 
 import (
+	"errors"
+	"strings"
 	"testing"
+
+	"github.com/promptforge/promptforge"
 )
 
 func TestParsePlan_CompletePlan(t *testing.T) {
@@ -295,3 +299,75 @@ func TestParseList_MixedMarkers(t *testing.T) {
 		t.Errorf("Expected 4 items, got %d", len(result))
 	}
 }
+
+func TestParsePlanWithLines_ExcludesHeadings(t *testing.T) {
+	content := "# Prompt Plan\n\n## Goal\nSummarize bug reports\n\n## Constraints\n- Must return JSON\n\n## Out of Scope\n- Payments\n"
+
+	plan, err := ParsePlanWithLines([]byte(content))
+	if err != nil {
+		t.Fatalf("ParsePlanWithLines() failed: %v", err)
+	}
+
+	if plan.Plan.Goal != "Summarize bug reports" || plan.GoalLine != 4 {
+		t.Errorf("Goal mismatch: got %q on line %d", plan.Plan.Goal, plan.GoalLine)
+	}
+	if len(plan.Constraints) != 1 || plan.Constraints[0].Text != "Must return JSON" || plan.Constraints[0].Line != 7 {
+		t.Errorf("Unexpected constraints: %+v", plan.Constraints)
+	}
+	if len(plan.OutOfScope) != 1 || plan.OutOfScope[0].Text != "Payments" {
+		t.Errorf("Unexpected out of scope items: %+v", plan.OutOfScope)
+	}
+}
+
+// TestSectionRange_ExcludesHeading guards against the off-by-one where a section
+// range started at its heading, so "## Constraints" was parsed as the first item
+// and compile --explain produced a different IR than compile.
+func TestSectionRange_ExcludesHeading(t *testing.T) {
+	lines := strings.Split("# Prompt Plan\n## Goal\nSummarize bug reports\n## Constraints\n- Must return JSON\n- Must be terse\n## Tools", "\n")
+
+	tests := []struct {
+		section    string
+		start, end int
+	}{
+		{"Goal", 3, 3},
+		{"Constraints", 5, 6},
+		{"Tools", 8, 7},
+	}
+	for _, tt := range tests {
+		start, end, found := sectionRange(lines, tt.section)
+		if !found || start != tt.start || end != tt.end {
+			t.Errorf("sectionRange(%s) = %d, %d, %t; want %d, %d, true", tt.section, start, end, found, tt.start, tt.end)
+		}
+	}
+}
+
+// TestParsePlanWithLines_EmptyGoalLine tests that an empty Goal is reported at its
+// heading, not at the line after it.
+func TestParsePlanWithLines_EmptyGoalLine(t *testing.T) {
+	_, err := ParsePlanWithLines([]byte("# Prompt Plan\n\n## Goal\n\n## Constraints\n- Must return JSON\n"))
+	var pfErr *promptforge.Error
+	if !errors.As(err, &pfErr) || pfErr.Line != 3 {
+		t.Fatalf("Expected the empty Goal error on line 3, got %v", err)
+	}
+}
+
+func TestListItems_ContinuationLines(t *testing.T) {
+	lines := []string{
+		"- Must ask a clarifying question when",
+		"  the category is unclear",
+		"- Reply in English",
+		"",
+		"  Indented after a blank line",
+	}
+
+	items := ListItems(lines, 1, len(lines))
+	if len(items) != 3 {
+		t.Fatalf("Expected 3 items, got %+v", items)
+	}
+	if items[0].Text != "Must ask a clarifying question when the category is unclear" || items[0].Line != 1 {
+		t.Errorf("Unexpected wrapped item: %+v", items[0])
+	}
+	if items[2].Text != "Indented after a blank line" || items[2].Line != 5 {
+		t.Errorf("Unexpected item after blank line: %+v", items[2])
+	}
+}
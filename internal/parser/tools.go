@@ -0,0 +1,173 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/promptforge/promptforge"
+)
+
+// Tool represents a tool (function) declared in the Tools section.
+//
+// Tools are declared as level-3 headings followed by a free-text purpose and
+// one bullet per parameter:
+//
+//	## Tools
+//	### search_orders
+//	Look up recent orders for a customer.
+//	- email (string, required): Customer email address
+//	- limit (integer): Maximum number of orders to return
+type Tool struct {
+	Name        string
+	Description string
+	Parameters  []Parameter
+	Line        int
+
+	// Errors lists parameter bullets that could not be parsed.
+	Errors []ParameterError
+}
+
+// Parameter is a typed declaration parsed from a bullet such as
+// "email (string, required): Customer email address".
+type Parameter struct {
+	Name        string
+	Type        string
+	Required    bool
+	Default     string
+	HasDefault  bool
+	Description string
+	Line        int
+}
+
+// ParameterError describes a declaration bullet that could not be parsed.
+type ParameterError struct {
+	Text    string
+	Message string
+	Line    int
+}
+
+var (
+	toolHeadingRe   = regexp.MustCompile(`^###\s+(.+?)\s*$`)
+	paramBulletRe   = regexp.MustCompile(`^[-*•]\s+`)
+	paramNameRe     = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_.-]*)\s*`)
+	paramDefaultRe  = regexp.MustCompile(`(?i)^default\s*[=:]\s*(.*)$`)
+	paramTypeSuffix = "[]"
+)
+
+// ParseParameter parses a single declaration of the form
+// "name (type, required, default=value): description".
+// The parenthesized attributes and the description are optional; the type defaults to "string".
+func ParseParameter(text string) (Parameter, error) {
+	text = strings.TrimSpace(text)
+	match := paramNameRe.FindStringSubmatch(text)
+	if match == nil {
+		return Parameter{}, promptforge.Errorf(promptforge.CodePlanSyntax, "expected a parameter name at the start of %q", text)
+	}
+
+	param := Parameter{Name: match[1], Type: "string"}
+	rest := strings.TrimSpace(text[len(match[0]):])
+
+	if strings.HasPrefix(rest, "(") {
+		end := strings.Index(rest, ")")
+		if end == -1 {
+			return Parameter{}, promptforge.Errorf(promptforge.CodePlanSyntax, "unterminated attribute list in %q", text)
+		}
+		attrs := strings.Split(rest[1:end], ",")
+		for i, attr := range attrs {
+			attr = strings.TrimSpace(attr)
+			if attr == "" {
+				continue
+			}
+			if strings.EqualFold(attr, "required") {
+				param.Required = true
+				continue
+			}
+			if strings.EqualFold(attr, "optional") {
+				continue
+			}
+			if m := paramDefaultRe.FindStringSubmatch(attr); m != nil {
+				param.Default = strings.Trim(strings.TrimSpace(m[1]), `"'`)
+				param.HasDefault = true
+				continue
+			}
+			if i == 0 {
+				param.Type = strings.ToLower(attr)
+				continue
+			}
+			return Parameter{}, promptforge.Errorf(promptforge.CodePlanSyntax, "unknown attribute %q for %s", attr, param.Name)
+		}
+		rest = strings.TrimSpace(rest[end+1:])
+	}
+
+	if rest != "" {
+		if !strings.HasPrefix(rest, ":") {
+			return Parameter{}, promptforge.Errorf(promptforge.CodePlanSyntax, "expected ':' before the description of %s", param.Name)
+		}
+		param.Description = strings.TrimSpace(rest[1:])
+	}
+
+	return param, nil
+}
+
+// IsArrayType reports whether a declared type is an array shorthand such as "string[]",
+// returning the element type.
+func IsArrayType(typeName string) (string, bool) {
+	if strings.HasSuffix(typeName, paramTypeSuffix) {
+		return strings.TrimSuffix(typeName, paramTypeSuffix), true
+	}
+	return "", false
+}
+
+// ParseToolsSection parses tool declarations from comment-stripped lines.
+// startLine and endLine are 1-based and inclusive, and exclude the "## Tools" heading.
+func ParseToolsSection(lines []string, startLine, endLine int) []Tool {
+	var tools []Tool
+	var current *Tool
+	var description []string
+
+	flush := func() {
+		if current == nil {
+			return
+		}
+		current.Description = strings.Join(description, " ")
+		tools = append(tools, *current)
+		current = nil
+		description = nil
+	}
+
+	for idx := startLine; idx <= endLine && idx <= len(lines); idx++ {
+		if idx < 1 {
+			continue
+		}
+		line := strings.TrimSpace(lines[idx-1])
+		if line == "" {
+			continue
+		}
+
+		if m := toolHeadingRe.FindStringSubmatch(line); m != nil {
+			flush()
+			current = &Tool{Name: strings.Trim(m[1], "`"), Line: idx}
+			continue
+		}
+		if current == nil {
+			continue
+		}
+
+		if paramBulletRe.MatchString(line) {
+			text := paramBulletRe.ReplaceAllString(line, "")
+			param, err := ParseParameter(text)
+			if err != nil {
+				current.Errors = append(current.Errors, ParameterError{Text: text, Message: err.Error(), Line: idx})
+				continue
+			}
+			param.Line = idx
+			current.Parameters = append(current.Parameters, param)
+			continue
+		}
+
+		description = append(description, line)
+	}
+	flush()
+
+	return tools
+}
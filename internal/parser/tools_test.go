@@ -0,0 +1,95 @@
+package parser
+
+import "testing"
+
+func TestParseParameter_Full(t *testing.T) {
+	param, err := ParseParameter("limit (integer, required, default=10): Maximum number of results")
+	if err != nil {
+		t.Fatalf("ParseParameter() failed: %v", err)
+	}
+
+	if param.Name != "limit" || param.Type != "integer" || !param.Required {
+		t.Errorf("Unexpected parameter: %+v", param)
+	}
+	if !param.HasDefault || param.Default != "10" {
+		t.Errorf("Expected default 10, got %+v", param)
+	}
+	if param.Description != "Maximum number of results" {
+		t.Errorf("Description mismatch: got %q", param.Description)
+	}
+}
+
+func TestParseParameter_NameOnly(t *testing.T) {
+	param, err := ParseParameter("query")
+	if err != nil {
+		t.Fatalf("ParseParameter() failed: %v", err)
+	}
+	if param.Name != "query" || param.Type != "string" || param.Required {
+		t.Errorf("Unexpected parameter: %+v", param)
+	}
+}
+
+func TestParseParameter_Invalid(t *testing.T) {
+	for _, input := range []string{
+		"(string): no name",
+		"email (string, required: unterminated",
+		"email (string, sometimes): unknown attribute",
+		"email extra words",
+	} {
+		if _, err := ParseParameter(input); err == nil {
+			t.Errorf("ParseParameter(%q) should fail", input)
+		}
+	}
+}
+
+func TestParsePlanWithLines_Tools(t *testing.T) {
+	content := `# Prompt Plan
+
+## Goal
+Help customers track their orders
+
+## Tools
+### search_orders
+Look up recent orders
+for a customer.
+- email (string, required): Customer email address
+- limit (integer): Maximum number of orders
+- bad bullet here
+
+### cancel_order
+- order_id (string, required): Order to cancel
+
+## Constraints
+- Always call search_orders before answering
+`
+
+	plan, err := ParsePlanWithLines([]byte(content))
+	if err != nil {
+		t.Fatalf("ParsePlanWithLines() failed: %v", err)
+	}
+
+	if len(plan.Tools) != 2 {
+		t.Fatalf("Expected 2 tools, got %d", len(plan.Tools))
+	}
+
+	search := plan.Tools[0]
+	if search.Name != "search_orders" || search.Line != 7 {
+		t.Errorf("Unexpected tool: %+v", search)
+	}
+	if search.Description != "Look up recent orders for a customer." {
+		t.Errorf("Description mismatch: got %q", search.Description)
+	}
+	if len(search.Parameters) != 2 || search.Parameters[0].Line != 10 {
+		t.Errorf("Unexpected parameters: %+v", search.Parameters)
+	}
+	if len(search.Errors) != 1 || search.Errors[0].Line != 12 {
+		t.Errorf("Expected one parameter error on line 12, got %+v", search.Errors)
+	}
+
+	if len(plan.Constraints) != 1 {
+		t.Errorf("Expected 1 constraint, got %d", len(plan.Constraints))
+	}
+	if len(plan.Plan.Tools) != 2 {
+		t.Errorf("Plan.Tools should mirror PlanWithLines.Tools")
+	}
+}
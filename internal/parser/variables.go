@@ -0,0 +1,93 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+)
+
+// placeholderRe matches template slots such as {{company_name}}.
+var placeholderRe = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_.-]*)\s*\}\}`)
+
+// ParseVariablesSection parses variable declarations from comment-stripped lines.
+// Each bullet uses the parameter syntax, e.g. "company_name (string, required): Display name".
+// startLine and endLine are 1-based and inclusive, and exclude the "## Variables" heading.
+func ParseVariablesSection(lines []string, startLine, endLine int) ([]Parameter, []ParameterError) {
+	var variables []Parameter
+	var errors []ParameterError
+
+	for idx := startLine; idx <= endLine && idx <= len(lines); idx++ {
+		if idx < 1 {
+			continue
+		}
+		line := strings.TrimSpace(lines[idx-1])
+		if line == "" {
+			continue
+		}
+
+		text := paramBulletRe.ReplaceAllString(line, "")
+		param, err := ParseParameter(text)
+		if err != nil {
+			errors = append(errors, ParameterError{Text: text, Message: err.Error(), Line: idx})
+			continue
+		}
+		param.Line = idx
+		variables = append(variables, param)
+	}
+
+	return variables, errors
+}
+
+// Placeholder is a {{name}} reference found in text.
+type Placeholder struct {
+	Name string
+
+	// Offset is the byte offset of the opening "{{" in the text.
+	Offset int
+}
+
+// FindPlaceholders returns the {{name}} references in text, in order of appearance.
+func FindPlaceholders(text string) []Placeholder {
+	matches := placeholderRe.FindAllStringSubmatchIndex(text, -1)
+	if len(matches) == 0 {
+		return nil
+	}
+	placeholders := make([]Placeholder, 0, len(matches))
+	for _, m := range matches {
+		placeholders = append(placeholders, Placeholder{Name: text[m[2]:m[3]], Offset: m[0]})
+	}
+	return placeholders
+}
+
+// Placeholders returns the variable names referenced as {{name}} in text, in order of appearance.
+func Placeholders(text string) []string {
+	placeholders := FindPlaceholders(text)
+	if len(placeholders) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(placeholders))
+	for _, placeholder := range placeholders {
+		names = append(names, placeholder.Name)
+	}
+	return names
+}
+
+// ReplacePlaceholders substitutes each {{name}} in text using lookup.
+// Placeholders for which lookup reports false are left untouched.
+func ReplacePlaceholders(text string, lookup func(name string) (string, bool)) string {
+	return placeholderRe.ReplaceAllStringFunc(text, func(match string) string {
+		name := placeholderRe.FindStringSubmatch(match)[1]
+		if value, ok := lookup(name); ok {
+			return value
+		}
+		return match
+	})
+}
+
+// parseVariables extracts variable declarations from the Variables section, if present.
+func parseVariables(strippedLines []string) ([]Parameter, []ParameterError) {
+	start, end, found := sectionRange(strippedLines, "Variables")
+	if !found {
+		return nil, nil
+	}
+	return ParseVariablesSection(strippedLines, start, end)
+}
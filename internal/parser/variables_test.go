@@ -0,0 +1,45 @@
+package parser
+
+import "testing"
+
+func TestPlaceholders(t *testing.T) {
+	names := Placeholders("Hello {{ name }}, today is {{today}} ({{name}})")
+	if len(names) != 3 || names[0] != "name" || names[1] != "today" {
+		t.Fatalf("Unexpected placeholders: %v", names)
+	}
+
+	found := FindPlaceholders("Hi {{ name }} and {{x}}")
+	if len(found) != 2 || found[0] != (Placeholder{Name: "name", Offset: 3}) || found[1] != (Placeholder{Name: "x", Offset: 18}) {
+		t.Errorf("Unexpected placeholder positions: %+v", found)
+	}
+
+	replaced := ReplacePlaceholders("Hi {{name}} {{other}}", func(name string) (string, bool) {
+		return "Ada", name == "name"
+	})
+	if replaced != "Hi Ada {{other}}" {
+		t.Errorf("Unexpected replacement: %q", replaced)
+	}
+}
+
+func TestParsePlanWithLines_Variables(t *testing.T) {
+	content := `# Prompt Plan
+
+## Goal
+Support customers of {{company_name}}
+
+## Variables
+- company_name (string, required): Company display name
+- (oops)
+`
+
+	plan, err := ParsePlanWithLines([]byte(content))
+	if err != nil {
+		t.Fatalf("ParsePlanWithLines() failed: %v", err)
+	}
+	if len(plan.Variables) != 1 || plan.Variables[0].Name != "company_name" || plan.Variables[0].Line != 7 {
+		t.Errorf("Unexpected variables: %+v", plan.Variables)
+	}
+	if len(plan.VariableErrors) != 1 || plan.VariableErrors[0].Line != 8 {
+		t.Errorf("Expected one variable error on line 8, got %+v", plan.VariableErrors)
+	}
+}
@@ -0,0 +1,308 @@
+// Package policy checks compiled prompt contracts against organization policy
+// packs: rules every contract must contain, content no contract may contain, and
+// constraints on its schemas.
+package policy
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/promptforge/promptforge/internal/ir"
+)
+
+// Violation codes.
+const (
+	CodeMissing              = "PF500" // too few rules or failure modes match a require check
+	CodeForbidden            = "PF501" // a rule or failure mode matches a forbid check
+	CodeAdditionalProperties = "PF502" // a schema object breaks additional_properties
+	CodeRequired             = "PF503" // a schema does not require a property
+)
+
+// Descriptions describes each violation code, for rule metadata such as SARIF.
+var Descriptions = map[string]string{
+	CodeMissing:              "A required rule or failure mode is missing",
+	CodeForbidden:            "A rule or failure mode is forbidden by policy",
+	CodeAdditionalProperties: "A schema object breaks the additional_properties policy",
+	CodeRequired:             "A schema does not require a property required by policy",
+}
+
+// Lists a check can search.
+const (
+	InRules        = "rules"
+	InFailureModes = "failure_modes"
+)
+
+// Schemas a schema check can apply to.
+const (
+	InInputSchema  = "input_schema"
+	InOutputSchema = "output_schema"
+)
+
+// Pack is a set of policies, read from a JSON or YAML file:
+//
+//	policies:
+//	  - id: no-prompt-leak
+//	    description: Contracts must forbid revealing the system prompt
+//	    require: {text: "(?i)never reveal .*system prompt"}
+//	  - id: closed-output
+//	    schema: {additional_properties: false}
+type Pack struct {
+	Policies []Policy `json:"policies" yaml:"policies"`
+}
+
+// Policy is one requirement on a contract. It is violated when any of its checks
+// fails.
+type Policy struct {
+	ID          string `json:"id" yaml:"id"`
+	Description string `json:"description,omitempty" yaml:"description"`
+
+	// Severity is "error" (the default) or "warn".
+	Severity string `json:"severity,omitempty" yaml:"severity"`
+
+	// Require is met when enough rules or failure modes match it.
+	Require *Match `json:"require,omitempty" yaml:"require"`
+
+	// Forbid is violated by every rule or failure mode that matches it.
+	Forbid *Match `json:"forbid,omitempty" yaml:"forbid"`
+
+	Schema *SchemaCheck `json:"schema,omitempty" yaml:"schema"`
+}
+
+// Match selects rules or failure modes. ID and Text are regular expressions
+// that must both match somewhere in an item's ID and text; an empty expression
+// matches anything. The text of a rule is its description, that of a failure
+// mode its condition.
+type Match struct {
+	// In is the list searched: "rules" (the default) or "failure_modes".
+	In string `json:"in,omitempty" yaml:"in"`
+
+	ID   string `json:"id,omitempty" yaml:"id"`
+	Text string `json:"text,omitempty" yaml:"text"`
+
+	// Min is how many items a require check needs; 0 means 1.
+	Min int `json:"min,omitempty" yaml:"min"`
+}
+
+// SchemaCheck constrains input_schema or output_schema.
+type SchemaCheck struct {
+	// In is the schema checked: "output_schema" (the default) or "input_schema".
+	In string `json:"in,omitempty" yaml:"in"`
+
+	// AdditionalProperties, when set, is the additional_properties value every
+	// object in the schema must declare. false forbids free-form objects. The
+	// compiler does not close output_schema yet, so false cannot be met on it.
+	AdditionalProperties *bool `json:"additional_properties,omitempty" yaml:"additional_properties"`
+
+	// Required lists top-level properties the schema must declare and require.
+	Required []string `json:"required,omitempty" yaml:"required"`
+}
+
+// Violation is a policy a contract breaks. Pointer is the JSON pointer of the
+// offending value in the IR.
+type Violation struct {
+	Code     string
+	Severity string
+	Policy   string
+	Message  string
+	Pointer  string
+}
+
+// Validate reports a policy without an ID, with a duplicate ID or an unknown
+// severity or list, without checks, or with an invalid regular expression.
+func (p *Pack) Validate() error {
+	seen := make(map[string]bool)
+	for i, policy := range p.Policies {
+		if policy.ID == "" {
+			return fmt.Errorf("policies[%d]: id is required", i)
+		}
+		if seen[policy.ID] {
+			return fmt.Errorf("policy %s: duplicate id", policy.ID)
+		}
+		seen[policy.ID] = true
+
+		if policy.Severity != "" && policy.Severity != "error" && policy.Severity != "warn" {
+			return fmt.Errorf("policy %s: invalid severity %q (expected error or warn)", policy.ID, policy.Severity)
+		}
+		if policy.Require == nil && policy.Forbid == nil && policy.Schema == nil {
+			return fmt.Errorf("policy %s: needs a require, forbid or schema check", policy.ID)
+		}
+		for _, match := range []*Match{policy.Require, policy.Forbid} {
+			if match == nil {
+				continue
+			}
+			if _, _, err := match.compile(); err != nil {
+				return fmt.Errorf("policy %s: %w", policy.ID, err)
+			}
+			if match.Min < 0 {
+				return fmt.Errorf("policy %s: min cannot be negative", policy.ID)
+			}
+		}
+		if check := policy.Schema; check != nil {
+			if check.In != "" && check.In != InInputSchema && check.In != InOutputSchema {
+				return fmt.Errorf("policy %s: invalid schema %q (expected %s or %s)", policy.ID, check.In, InInputSchema, InOutputSchema)
+			}
+			if check.AdditionalProperties == nil && len(check.Required) == 0 {
+				return fmt.Errorf("policy %s: schema check needs additional_properties or required", policy.ID)
+			}
+		}
+	}
+	return nil
+}
+
+// Evaluate checks promptIR against every policy in the pack, in order. The pack
+// must be valid.
+func (p *Pack) Evaluate(promptIR *ir.PromptIR) []Violation {
+	var violations []Violation
+	for _, policy := range p.Policies {
+		severity := policy.Severity
+		if severity == "" {
+			severity = "error"
+		}
+		add := func(code, pointer, detail string) {
+			message := detail
+			if policy.Description != "" {
+				message = fmt.Sprintf("%s (%s)", policy.Description, detail)
+			}
+			violations = append(violations, Violation{
+				Code:     code,
+				Severity: severity,
+				Policy:   policy.ID,
+				Message:  message,
+				Pointer:  pointer,
+			})
+		}
+
+		if match := policy.Require; match != nil {
+			list := match.list()
+			matches := match.find(promptIR)
+			need := match.Min
+			if need == 0 {
+				need = 1
+			}
+			if len(matches) < need {
+				add(CodeMissing, "/"+list, fmt.Sprintf("%d of %d required %s match %s", len(matches), need, list, match))
+			}
+		}
+		if match := policy.Forbid; match != nil {
+			list := match.list()
+			for _, index := range match.find(promptIR) {
+				add(CodeForbidden, fmt.Sprintf("/%s/%d", list, index), fmt.Sprintf("%s[%d] matches forbidden %s", list, index, match))
+			}
+		}
+		if check := policy.Schema; check != nil {
+			name := check.In
+			if name == "" {
+				name = InOutputSchema
+			}
+			schema := promptIR.OutputSchema
+			if name == InInputSchema {
+				schema = promptIR.InputSchema
+			}
+			if want := check.AdditionalProperties; want != nil {
+				for _, pointer := range objectsBreaking(&schema, "/"+name, *want) {
+					add(CodeAdditionalProperties, pointer, fmt.Sprintf("object must set additional_properties to %t", *want))
+				}
+			}
+			for _, property := range check.Required {
+				if _, ok := schema.Properties[property]; !ok || !slices.Contains(schema.Required, property) {
+					add(CodeRequired, "/"+name+"/required", fmt.Sprintf("%s must require property %q", name, property))
+				}
+			}
+		}
+	}
+	return violations
+}
+
+// list returns the IR list the match searches.
+func (m *Match) list() string {
+	if m.In == "" {
+		return InRules
+	}
+	return m.In
+}
+
+func (m *Match) compile() (*regexp.Regexp, *regexp.Regexp, error) {
+	if list := m.list(); list != InRules && list != InFailureModes {
+		return nil, nil, fmt.Errorf("invalid list %q (expected %s or %s)", m.In, InRules, InFailureModes)
+	}
+	idRe, err := regexp.Compile(m.ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid id pattern %q: %w", m.ID, err)
+	}
+	textRe, err := regexp.Compile(m.Text)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid text pattern %q: %w", m.Text, err)
+	}
+	return idRe, textRe, nil
+}
+
+// find returns the indexes of the items in promptIR that match.
+func (m *Match) find(promptIR *ir.PromptIR) []int {
+	idRe, textRe, err := m.compile()
+	if err != nil {
+		return nil
+	}
+
+	var ids, texts []string
+	if m.list() == InFailureModes {
+		for _, fm := range promptIR.FailureModes {
+			ids, texts = append(ids, fm.ID), append(texts, fm.Condition)
+		}
+	} else {
+		for _, rule := range promptIR.Rules {
+			ids, texts = append(ids, rule.ID), append(texts, rule.Description)
+		}
+	}
+
+	var matches []int
+	for i := range ids {
+		if idRe.MatchString(ids[i]) && textRe.MatchString(texts[i]) {
+			matches = append(matches, i)
+		}
+	}
+	return matches
+}
+
+// String describes the match for messages, such as `id /^no-leak/`.
+func (m *Match) String() string {
+	var parts []string
+	if m.ID != "" {
+		parts = append(parts, fmt.Sprintf("id /%s/", m.ID))
+	}
+	if m.Text != "" {
+		parts = append(parts, fmt.Sprintf("text /%s/", m.Text))
+	}
+	if len(parts) == 0 {
+		return "anything"
+	}
+	return strings.Join(parts, " and ")
+}
+
+// objectsBreaking returns the JSON pointers of the objects in schema whose
+// additional_properties is not want. An unset value allows additional
+// properties.
+func objectsBreaking(schema *ir.Schema, pointer string, want bool) []string {
+	return walkObject(pointer, schema.Type, schema.Properties, schema.Items, schema.AdditionalProperties, want)
+}
+
+func walkObject(pointer, typ string, properties map[string]ir.Property, items *ir.Schema, additional *bool, want bool) []string {
+	var pointers []string
+	if typ == "object" {
+		allowed := additional == nil || *additional
+		if allowed != want {
+			pointers = append(pointers, pointer)
+		}
+	}
+
+	for _, name := range ir.SortedPropertyNames(properties) {
+		property := properties[name]
+		propertyPointer := pointer + "/properties/" + ir.EscapePointer(name)
+		pointers = append(pointers, walkObject(propertyPointer, property.Type, property.Properties, property.Items, property.AdditionalProperties, want)...)
+	}
+	if items != nil {
+		pointers = append(pointers, objectsBreaking(items, pointer+"/items", want)...)
+	}
+	return pointers
+}
@@ -0,0 +1,128 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Mock is a deterministic local provider that replies from a fixture file:
+//
+//	responses:
+//	  - input: {order_id: A-1001}
+//	    response: '{"decision": "refund"}'
+//	  - response: '{"error": "invalid-input"}'
+//
+// The first entry whose input equals the request input (compared as JSON) is
+// used; an entry without input matches any request.
+type Mock struct {
+	entries []mockEntry
+}
+
+type mockEntry struct {
+	// input is the canonical JSON of the expected input; nil matches anything.
+	input    []byte
+	response string
+}
+
+type mockFixture struct {
+	Responses []struct {
+		Input    interface{} `yaml:"input"`
+		Response interface{} `yaml:"response"`
+	} `yaml:"responses"`
+}
+
+// NewMock loads a mock provider from a YAML or JSON fixture file.
+func NewMock(fixturePath string) (*Mock, error) {
+	if fixturePath == "" {
+		return nil, fmt.Errorf("mock provider requires a fixture file")
+	}
+
+	data, err := os.ReadFile(fixturePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("mock fixture not found at %s", fixturePath)
+		}
+		if os.IsPermission(err) {
+			return nil, fmt.Errorf("permission denied: cannot read mock fixture at %s", fixturePath)
+		}
+		return nil, fmt.Errorf("failed to read mock fixture at %s: %w", fixturePath, err)
+	}
+
+	var fixture mockFixture
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&fixture); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse mock fixture at %s: %w", fixturePath, err)
+	}
+	if len(fixture.Responses) == 0 {
+		return nil, fmt.Errorf("mock fixture at %s has no responses", fixturePath)
+	}
+
+	mock := &Mock{}
+	for i, r := range fixture.Responses {
+		var entry mockEntry
+		switch response := r.Response.(type) {
+		case nil:
+			return nil, fmt.Errorf("mock fixture at %s: responses[%d].response is required", fixturePath, i)
+		case string:
+			entry.response = response
+		default:
+			data, err := json.Marshal(response)
+			if err != nil {
+				return nil, fmt.Errorf("mock fixture at %s: responses[%d].response is not representable as JSON: %w", fixturePath, i, err)
+			}
+			entry.response = string(data)
+		}
+		if r.Input != nil {
+			entry.input, err = json.Marshal(r.Input)
+			if err != nil {
+				return nil, fmt.Errorf("mock fixture at %s: responses[%d].input is not representable as JSON: %w", fixturePath, i, err)
+			}
+		}
+		mock.entries = append(mock.entries, entry)
+	}
+	return mock, nil
+}
+
+// Name returns "mock".
+func (m *Mock) Name() string {
+	return "mock"
+}
+
+// Complete returns the fixture response for the request input.
+func (m *Mock) Complete(ctx context.Context, req Request) (Response, error) {
+	if err := ctx.Err(); err != nil {
+		return Response{}, err
+	}
+
+	input, err := canonicalJSON(req.Input)
+	if err != nil {
+		return Response{}, fmt.Errorf("mock provider: invalid input JSON: %w", err)
+	}
+	for _, entry := range m.entries {
+		if entry.input == nil || bytes.Equal(entry.input, input) {
+			return Response{Text: entry.response, Model: "mock"}, nil
+		}
+	}
+	return Response{}, fmt.Errorf("mock provider: no fixture response matches input %s", input)
+}
+
+// canonicalJSON re-encodes a JSON document so that equal documents compare equal
+// byte for byte (object keys sorted, insignificant whitespace removed).
+func canonicalJSON(data []byte) ([]byte, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return []byte("null"), nil
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	return json.Marshal(value)
+}
@@ -0,0 +1,123 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultOpenAIBaseURL is used when no base URL is configured.
+const DefaultOpenAIBaseURL = "https://api.openai.com/v1"
+
+// maxResponseBytes bounds how much of an API response is read.
+const maxResponseBytes = 10 << 20
+
+// OpenAI calls an OpenAI-compatible chat completions API. Any server implementing
+// POST {base}/chat/completions works, including local stand-ins.
+type OpenAI struct {
+	baseURL string
+	model   string
+	apiKey  string
+	client  *http.Client
+}
+
+// NewOpenAI builds an OpenAI-compatible provider. Options.Model is required.
+func NewOpenAI(opts Options) (*OpenAI, error) {
+	if opts.Model == "" {
+		return nil, fmt.Errorf("openai provider requires a model")
+	}
+	baseURL := opts.BaseURL
+	if baseURL == "" {
+		baseURL = DefaultOpenAIBaseURL
+	}
+	return &OpenAI{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		model:   opts.Model,
+		apiKey:  opts.APIKey,
+		client:  &http.Client{Timeout: 2 * time.Minute},
+	}, nil
+}
+
+// Name returns "openai".
+func (o *OpenAI) Name() string {
+	return "openai"
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatRequest struct {
+	Model       string        `json:"model"`
+	Messages    []chatMessage `json:"messages"`
+	Temperature float64       `json:"temperature"`
+}
+
+type chatResponse struct {
+	Model   string `json:"model"`
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// Complete sends the system prompt and the input (as the user message) with
+// temperature 0 and returns the first choice.
+func (o *OpenAI) Complete(ctx context.Context, req Request) (Response, error) {
+	body, err := json.Marshal(chatRequest{
+		Model: o.model,
+		Messages: []chatMessage{
+			{Role: "system", Content: req.SystemPrompt},
+			{Role: "user", Content: string(req.Input)},
+		},
+	})
+	if err != nil {
+		return Response{}, fmt.Errorf("openai provider: failed to encode request: %w", err)
+	}
+
+	url := o.baseURL + "/chat/completions"
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return Response{}, fmt.Errorf("openai provider: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if o.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+o.apiKey)
+	}
+
+	httpResp, err := o.client.Do(httpReq)
+	if err != nil {
+		return Response{}, fmt.Errorf("openai provider: request to %s failed: %w", url, err)
+	}
+	defer httpResp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(httpResp.Body, maxResponseBytes))
+	if err != nil {
+		return Response{}, fmt.Errorf("openai provider: failed to read response: %w", err)
+	}
+
+	var resp chatResponse
+	decodeErr := json.Unmarshal(data, &resp)
+	if httpResp.StatusCode != http.StatusOK {
+		if decodeErr == nil && resp.Error != nil && resp.Error.Message != "" {
+			return Response{}, fmt.Errorf("openai provider: %s returned %s: %s", url, httpResp.Status, resp.Error.Message)
+		}
+		return Response{}, fmt.Errorf("openai provider: %s returned %s", url, httpResp.Status)
+	}
+	if decodeErr != nil {
+		return Response{}, fmt.Errorf("openai provider: failed to parse response: %w", decodeErr)
+	}
+	if len(resp.Choices) == 0 {
+		return Response{}, fmt.Errorf("openai provider: response has no choices")
+	}
+
+	return Response{Text: resp.Choices[0].Message.Content, Model: resp.Model}, nil
+}
@@ -0,0 +1,88 @@
+// Package provider sends rendered prompts to a model backend. Backends implement
+// Provider; New builds one by name.
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Request is a single model call: the rendered system prompt and the input payload.
+type Request struct {
+	SystemPrompt string
+
+	// Input is the input payload as a JSON document.
+	Input json.RawMessage
+}
+
+// Response is the raw model reply.
+type Response struct {
+	Text string
+
+	// Model identifies the model that produced the reply, when the backend reports it.
+	Model string
+}
+
+// Provider is a model backend.
+type Provider interface {
+	// Name returns the provider name used on the command line.
+	Name() string
+
+	// Complete sends a request and returns the model reply.
+	Complete(ctx context.Context, req Request) (Response, error)
+}
+
+// Options configures the providers built by New. Each provider reads the fields
+// it needs and ignores the rest.
+type Options struct {
+	// Fixture is the mock provider's fixture file.
+	Fixture string
+
+	// BaseURL is the OpenAI-compatible API root, such as http://localhost:8080/v1.
+	// Defaults to $OPENAI_BASE_URL, then https://api.openai.com/v1.
+	BaseURL string
+
+	// Model is the model name sent to the OpenAI-compatible API.
+	Model string
+
+	// APIKey is sent as a bearer token. Defaults to $OPENAI_API_KEY.
+	APIKey string
+}
+
+var factories = map[string]func(Options) (Provider, error){
+	"mock": func(opts Options) (Provider, error) {
+		return NewMock(opts.Fixture)
+	},
+	"openai": func(opts Options) (Provider, error) {
+		if opts.BaseURL == "" {
+			opts.BaseURL = os.Getenv("OPENAI_BASE_URL")
+		}
+		if opts.APIKey == "" {
+			opts.APIKey = os.Getenv("OPENAI_API_KEY")
+		}
+		return NewOpenAI(opts)
+	},
+}
+
+// Names returns the available provider names, sorted.
+func Names() []string {
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// New builds the named provider.
+func New(name string, opts Options) (Provider, error) {
+	factory, ok := factories[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown provider: %s (expected: %s)", name, strings.Join(Names(), ", "))
+	}
+	return factory(opts)
+}
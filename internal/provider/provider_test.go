@@ -0,0 +1,147 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFixture(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "mock.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write fixture: %v", err)
+	}
+	return path
+}
+
+func TestMock_MatchesInput(t *testing.T) {
+	path := writeFixture(t, `responses:
+  - input: {order_id: A-1, amount: 12}
+    response: '{"decision": "refund"}'
+  - input: {order_id: A-2}
+    response:
+      decision: deny
+  - response: '{"error": "invalid-input"}'
+`)
+	mock, err := NewMock(path)
+	if err != nil {
+		t.Fatalf("NewMock() failed: %v", err)
+	}
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: `{"amount": 12.0, "order_id": "A-1"}`, want: `{"decision": "refund"}`},
+		{input: `{"order_id":"A-2"}`, want: `{"decision":"deny"}`},
+		{input: `{"order_id": "A-3"}`, want: `{"error": "invalid-input"}`},
+	}
+	for _, tt := range tests {
+		resp, err := mock.Complete(context.Background(), Request{Input: json.RawMessage(tt.input)})
+		if err != nil {
+			t.Fatalf("Complete(%s) failed: %v", tt.input, err)
+		}
+		if resp.Text != tt.want {
+			t.Errorf("Complete(%s) = %q, want %q", tt.input, resp.Text, tt.want)
+		}
+	}
+}
+
+func TestMock_NoMatch(t *testing.T) {
+	mock, err := NewMock(writeFixture(t, "responses:\n  - input: {a: 1}\n    response: '{}'\n"))
+	if err != nil {
+		t.Fatalf("NewMock() failed: %v", err)
+	}
+	if _, err := mock.Complete(context.Background(), Request{Input: json.RawMessage(`{"a": 2}`)}); err == nil {
+		t.Fatal("Complete() should fail when no fixture entry matches")
+	}
+}
+
+func TestNewMock_InvalidFixtures(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "empty", content: "", wantErr: "has no responses"},
+		{name: "missing response", content: "responses:\n  - input: {a: 1}\n", wantErr: "responses[0].response is required"},
+		{name: "unknown field", content: "replies: []\n", wantErr: "failed to parse mock fixture"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewMock(writeFixture(t, tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestOpenAI_Complete(t *testing.T) {
+	var got chatRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		if auth := r.Header.Get("Authorization"); auth != "Bearer test-key" {
+			t.Errorf("Authorization = %q", auth)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"model": "local-7b", "choices": [{"message": {"role": "assistant", "content": "{\"decision\": \"refund\"}"}}]}`))
+	}))
+	defer server.Close()
+
+	p, err := New("openai", Options{BaseURL: server.URL + "/v1/", Model: "local", APIKey: "test-key"})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	resp, err := p.Complete(context.Background(), Request{SystemPrompt: "You triage refunds.", Input: json.RawMessage(`{"order_id":"A-1"}`)})
+	if err != nil {
+		t.Fatalf("Complete() failed: %v", err)
+	}
+
+	if resp.Text != `{"decision": "refund"}` || resp.Model != "local-7b" {
+		t.Errorf("Unexpected response %+v", resp)
+	}
+	if got.Model != "local" || len(got.Messages) != 2 {
+		t.Fatalf("Unexpected request %+v", got)
+	}
+	if got.Messages[0].Role != "system" || got.Messages[0].Content != "You triage refunds." {
+		t.Errorf("Unexpected system message %+v", got.Messages[0])
+	}
+	if got.Messages[1].Role != "user" || got.Messages[1].Content != `{"order_id":"A-1"}` {
+		t.Errorf("Unexpected user message %+v", got.Messages[1])
+	}
+}
+
+func TestOpenAI_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error": {"message": "invalid api key"}}`))
+	}))
+	defer server.Close()
+
+	p, err := NewOpenAI(Options{BaseURL: server.URL, Model: "local"})
+	if err != nil {
+		t.Fatalf("NewOpenAI() failed: %v", err)
+	}
+	_, err = p.Complete(context.Background(), Request{Input: json.RawMessage(`{}`)})
+	if err == nil || !strings.Contains(err.Error(), "invalid api key") {
+		t.Fatalf("Expected the API error message, got %v", err)
+	}
+}
+
+func TestNew_UnknownProvider(t *testing.T) {
+	if _, err := New("llama", Options{}); err == nil || !strings.Contains(err.Error(), "mock, openai") {
+		t.Fatalf("Expected an unknown provider error listing providers, got %v", err)
+	}
+}
@@ -0,0 +1,158 @@
+package render
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/promptforge/promptforge/internal/ir"
+	"github.com/promptforge/promptforge/internal/parser"
+)
+
+// Render substitutes variable values into the prompt text of a compiled IR and
+// returns the rendered copy. The input IR is not modified.
+//
+// Values are checked against each variable's declared type. Declared defaults are
+// used for variables without a value; optional variables without either render as
+// an empty string. Rendering fails if a required variable has no value, if a value
+// is supplied for an undeclared variable, or if the IR uses an undeclared {{slot}}.
+func Render(promptIR *ir.PromptIR, values map[string]string) (*ir.PromptIR, error) {
+	if promptIR == nil {
+		return nil, fmt.Errorf("prompt IR is nil")
+	}
+
+	declared := make(map[string]ir.Variable, len(promptIR.Variables))
+	for _, variable := range promptIR.Variables {
+		declared[variable.Name] = variable
+	}
+
+	var unknown []string
+	for name := range values {
+		if _, ok := declared[name]; !ok {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("unknown variables: %s", strings.Join(unknown, ", "))
+	}
+
+	resolved := make(map[string]string, len(declared))
+	var missing []string
+	for _, variable := range promptIR.Variables {
+		value, ok := values[variable.Name]
+		switch {
+		case ok:
+		case variable.Default != nil:
+			value = *variable.Default
+		case variable.Required:
+			missing = append(missing, variable.Name)
+			continue
+		default:
+			resolved[variable.Name] = ""
+			continue
+		}
+
+		if err := ir.CheckVariableValue(variable.Type, value); err != nil {
+			return nil, fmt.Errorf("invalid value for variable %s: %w", variable.Name, err)
+		}
+		resolved[variable.Name] = value
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing required variables: %s", strings.Join(missing, ", "))
+	}
+
+	var undeclared []string
+	substitute := func(text string) string {
+		return parser.ReplacePlaceholders(text, func(name string) (string, bool) {
+			value, ok := resolved[name]
+			if !ok {
+				undeclared = append(undeclared, name)
+			}
+			return value, ok
+		})
+	}
+
+	rendered := *promptIR
+	rendered.SystemRole = substitute(promptIR.SystemRole)
+
+	rendered.Rules = make([]ir.Rule, len(promptIR.Rules))
+	for i, rule := range promptIR.Rules {
+		rule.Description = substitute(rule.Description)
+		rendered.Rules[i] = rule
+	}
+
+	rendered.FailureModes = make([]ir.FailureMode, len(promptIR.FailureModes))
+	for i, fm := range promptIR.FailureModes {
+		fm.Condition = substitute(fm.Condition)
+		fm.Response = substitute(fm.Response)
+		rendered.FailureModes[i] = fm
+	}
+
+	if len(promptIR.Tools) > 0 {
+		rendered.Tools = make([]ir.Tool, len(promptIR.Tools))
+		for i, tool := range promptIR.Tools {
+			tool.Description = substitute(tool.Description)
+			rendered.Tools[i] = tool
+		}
+	}
+
+	if len(undeclared) > 0 {
+		return nil, fmt.Errorf("undeclared variables used in prompt: %s", strings.Join(dedupe(undeclared), ", "))
+	}
+
+	return &rendered, nil
+}
+
+// SystemPrompt formats a (rendered) IR as the plain-text system prompt sent to a model.
+func SystemPrompt(promptIR *ir.PromptIR) string {
+	var b strings.Builder
+	b.WriteString(promptIR.SystemRole)
+	b.WriteString("\n")
+
+	if len(promptIR.Rules) > 0 {
+		b.WriteString("\nRules:\n")
+		for _, rule := range promptIR.Rules {
+			fmt.Fprintf(&b, "- [%s] %s\n", rule.ID, rule.Description)
+		}
+	}
+
+	if len(promptIR.FailureModes) > 0 {
+		b.WriteString("\nFailure modes:\n")
+		for _, fm := range promptIR.FailureModes {
+			fmt.Fprintf(&b, "- [%s] If %s: %s\n", fm.ID, lowerFirst(fm.Condition), fm.Response)
+		}
+	}
+
+	if len(promptIR.Tools) > 0 {
+		b.WriteString("\nTools:\n")
+		for _, tool := range promptIR.Tools {
+			if tool.Description != "" {
+				fmt.Fprintf(&b, "- %s: %s\n", tool.Name, tool.Description)
+			} else {
+				fmt.Fprintf(&b, "- %s\n", tool.Name)
+			}
+		}
+	}
+
+	return b.String()
+}
+
+func lowerFirst(text string) string {
+	if text == "" {
+		return text
+	}
+	return strings.ToLower(text[:1]) + text[1:]
+}
+
+func dedupe(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	var out []string
+	for _, name := range names {
+		if !seen[name] {
+			seen[name] = true
+			out = append(out, name)
+		}
+	}
+	return out
+}
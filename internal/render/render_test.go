@@ -0,0 +1,105 @@
+package render
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/promptforge/promptforge/internal/ir"
+)
+
+func testIR() *ir.PromptIR {
+	defaultTone := "friendly"
+	return &ir.PromptIR{
+		Version:    ir.CurrentVersion,
+		SystemRole: "You are an assistant designed to: Support customers of {{company_name}}.",
+		Rules: []ir.Rule{
+			{ID: "constraint-tone", Description: "Use a {{tone}} tone"},
+			{ID: "constraint-date", Description: "Today is {{today}}"},
+		},
+		InputSchema:  ir.Schema{Type: "object"},
+		OutputSchema: ir.Schema{Type: "object"},
+		FailureModes: []ir.FailureMode{
+			{ID: "invalid-input", Condition: "Input does not match input_schema", Response: "Return error"},
+		},
+		Variables: []ir.Variable{
+			{Name: "company_name", Type: "string", Required: true},
+			{Name: "tone", Type: "string", Default: &defaultTone},
+			{Name: "today", Type: "date"},
+		},
+	}
+}
+
+func TestRender_SubstitutesValues(t *testing.T) {
+	rendered, err := Render(testIR(), map[string]string{
+		"company_name": "Acme",
+		"today":        "2024-05-01",
+	})
+	if err != nil {
+		t.Fatalf("Render() failed: %v", err)
+	}
+
+	if !strings.Contains(rendered.SystemRole, "Acme") {
+		t.Errorf("SystemRole not rendered: %q", rendered.SystemRole)
+	}
+	if rendered.Rules[0].Description != "Use a friendly tone" {
+		t.Errorf("Default not applied: %q", rendered.Rules[0].Description)
+	}
+	if rendered.Rules[1].Description != "Today is 2024-05-01" {
+		t.Errorf("Date not rendered: %q", rendered.Rules[1].Description)
+	}
+}
+
+func TestRender_DoesNotModifyInput(t *testing.T) {
+	original := testIR()
+	if _, err := Render(original, map[string]string{"company_name": "Acme"}); err != nil {
+		t.Fatalf("Render() failed: %v", err)
+	}
+	if !strings.Contains(original.Rules[0].Description, "{{tone}}") {
+		t.Error("Render() should not modify the input IR")
+	}
+}
+
+func TestRender_MissingRequired(t *testing.T) {
+	_, err := Render(testIR(), nil)
+	if err == nil || !strings.Contains(err.Error(), "company_name") {
+		t.Fatalf("Expected missing company_name error, got %v", err)
+	}
+}
+
+func TestRender_InvalidValue(t *testing.T) {
+	_, err := Render(testIR(), map[string]string{"company_name": "Acme", "today": "tomorrow"})
+	if err == nil {
+		t.Fatal("Render() should reject an invalid date")
+	}
+}
+
+func TestRender_UnknownVariable(t *testing.T) {
+	_, err := Render(testIR(), map[string]string{"company_name": "Acme", "colour": "red"})
+	if err == nil || !strings.Contains(err.Error(), "colour") {
+		t.Fatalf("Expected unknown variable error, got %v", err)
+	}
+}
+
+func TestRender_UndeclaredSlot(t *testing.T) {
+	promptIR := testIR()
+	promptIR.Rules = append(promptIR.Rules, ir.Rule{ID: "r", Description: "Mention {{product}}"})
+
+	_, err := Render(promptIR, map[string]string{"company_name": "Acme"})
+	if err == nil || !strings.Contains(err.Error(), "product") {
+		t.Fatalf("Expected undeclared variable error, got %v", err)
+	}
+}
+
+func TestSystemPrompt_IncludesRulesAndFailureModes(t *testing.T) {
+	rendered, err := Render(testIR(), map[string]string{"company_name": "Acme"})
+	if err != nil {
+		t.Fatalf("Render() failed: %v", err)
+	}
+
+	prompt := SystemPrompt(rendered)
+	for _, want := range []string{"Acme", "- [constraint-tone] Use a friendly tone", "- [invalid-input] If input does not match"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("SystemPrompt() missing %q:\n%s", want, prompt)
+		}
+	}
+}
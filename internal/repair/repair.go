@@ -0,0 +1,295 @@
+// Package repair applies safe, deterministic fix-ups to model responses that
+// almost satisfy a prompt contract. A repair is only applied when it cannot change
+// what the response means; otherwise it is reported as refused and the response
+// is left as it is.
+package repair
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/promptforge/promptforge/internal/contract"
+	"github.com/promptforge/promptforge/internal/ir"
+)
+
+// Repair kinds.
+const (
+	KindStripFence   = "strip-fence"
+	KindExtractJSON  = "extract-json"
+	KindEnumCase     = "enum-case"
+	KindDropProperty = "drop-property"
+)
+
+// Change describes a repair that was applied or refused.
+type Change struct {
+	Kind string `json:"kind"`
+
+	// Path is the JSON pointer of the repaired value; empty for the whole response.
+	Path string `json:"path,omitempty"`
+
+	Message string `json:"message"`
+}
+
+func (c Change) String() string {
+	if c.Path == "" {
+		return fmt.Sprintf("%s: %s", c.Kind, c.Message)
+	}
+	return fmt.Sprintf("%s %s: %s", c.Kind, c.Path, c.Message)
+}
+
+// Result is the outcome of Repairer.Repair.
+type Result struct {
+	// Output is the repaired response, or the original one when nothing was repaired.
+	Output []byte
+
+	// Applied lists the repairs made, in order.
+	Applied []Change
+
+	// Refused lists repairs that were not made because they could change meaning.
+	Refused []Change
+
+	// FailureMode and Violations describe Output, as reported by the contract validator.
+	FailureMode string
+	Violations  []contract.Violation
+}
+
+// Repairer repairs responses for one contract. It is safe for concurrent use.
+type Repairer struct {
+	output    ir.Schema
+	validator *contract.Validator
+}
+
+// New builds a Repairer for promptIR.
+func New(promptIR *ir.PromptIR) (*Repairer, error) {
+	validator, err := contract.NewValidator(promptIR)
+	if err != nil {
+		return nil, err
+	}
+	return &Repairer{output: promptIR.OutputSchema, validator: validator}, nil
+}
+
+// fenceRe matches a response that is a single Markdown code fence.
+var fenceRe = regexp.MustCompile("(?s)^```[A-Za-z0-9_-]*[ \\t]*\\r?\\n(.*?)\\r?\\n?```$")
+
+// Repair attempts the repairs in order: strip a Markdown code fence around the
+// whole response, extract the single JSON value from surrounding prose, coerce
+// the case of enum values, and drop undeclared properties from objects whose
+// schema sets additional_properties to false. Error responses naming a failure
+// mode are only unwrapped, never rewritten.
+func (r *Repairer) Repair(response []byte) Result {
+	result := Result{Output: response}
+	if r.validator.OutputValid(response) {
+		return result
+	}
+
+	text := bytes.TrimSpace(response)
+	if m := fenceRe.FindSubmatch(text); m != nil {
+		text = bytes.TrimSpace(m[1])
+		result.Applied = append(result.Applied, Change{Kind: KindStripFence, Message: "removed the Markdown code fence around the response"})
+	}
+
+	text, change, refused := extractJSON(text)
+	if change != nil {
+		result.Applied = append(result.Applied, *change)
+	}
+	if refused != nil {
+		result.Refused = append(result.Refused, *refused)
+	}
+
+	if out := r.validator.ValidateOutput(text); out.Value != nil && out.FailureMode == "" && len(out.Violations) > 0 {
+		w := walker{}
+		value := w.schema(out.Value, r.output, "")
+		if encoded, err := encode(value); err == nil {
+			if len(w.applied) > 0 {
+				text = encoded
+			}
+			result.Applied = append(result.Applied, w.applied...)
+			result.Refused = append(result.Refused, w.refused...)
+		}
+	}
+
+	if len(result.Applied) > 0 {
+		result.Output = text
+	}
+	out := r.validator.ValidateOutput(result.Output)
+	result.FailureMode = out.FailureMode
+	result.Violations = out.Violations
+	return result
+}
+
+// extractJSON returns the single JSON object or array embedded in text. It
+// refuses when the text holds several JSON values, since picking one would drop
+// content the model meant to return.
+func extractJSON(text []byte) ([]byte, *Change, *Change) {
+	if json.Valid(text) {
+		return text, nil, nil
+	}
+
+	var values [][2]int
+	for i := 0; i < len(text); i++ {
+		if text[i] != '{' && text[i] != '[' {
+			continue
+		}
+		decoder := json.NewDecoder(bytes.NewReader(text[i:]))
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			continue
+		}
+		end := i + int(decoder.InputOffset())
+		values = append(values, [2]int{i, end})
+		i = end - 1
+	}
+
+	switch len(values) {
+	case 0:
+		return text, nil, nil
+	case 1:
+		start, end := values[0][0], values[0][1]
+		dropped := len(bytes.TrimSpace(text[:start])) + len(bytes.TrimSpace(text[end:]))
+		return text[start:end], &Change{
+			Kind:    KindExtractJSON,
+			Message: fmt.Sprintf("extracted the JSON value and dropped %d byte(s) of surrounding text", dropped),
+		}, nil
+	default:
+		return text, nil, &Change{
+			Kind:    KindExtractJSON,
+			Message: fmt.Sprintf("response contains %d JSON values; refusing to pick one", len(values)),
+		}
+	}
+}
+
+// walker rewrites a decoded response against the output schema, recording the
+// repairs it applies and refuses.
+type walker struct {
+	applied []Change
+	refused []Change
+}
+
+func (w *walker) schema(value interface{}, schema ir.Schema, path string) interface{} {
+	switch schema.Type {
+	case "object":
+		if object, ok := value.(map[string]interface{}); ok {
+			w.object(object, schema.Properties, schema.AdditionalProperties, path)
+		}
+	case "array":
+		if items, ok := value.([]interface{}); ok && schema.Items != nil {
+			for i, item := range items {
+				items[i] = w.schema(item, *schema.Items, fmt.Sprintf("%s/%d", path, i))
+			}
+		}
+	}
+	return value
+}
+
+func (w *walker) property(value interface{}, property ir.Property, path string) interface{} {
+	if len(property.Enum) > 0 {
+		if s, ok := value.(string); ok {
+			return w.enum(s, property.Enum, path)
+		}
+	}
+	switch property.Type {
+	case "object":
+		if object, ok := value.(map[string]interface{}); ok {
+			w.object(object, property.Properties, property.AdditionalProperties, path)
+		}
+	case "array":
+		if items, ok := value.([]interface{}); ok && property.Items != nil {
+			for i, item := range items {
+				items[i] = w.schema(item, *property.Items, fmt.Sprintf("%s/%d", path, i))
+			}
+		}
+	}
+	return value
+}
+
+func (w *walker) object(object map[string]interface{}, properties map[string]ir.Property, additional *bool, path string) {
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		childPath := path + "/" + ir.EscapePointer(name)
+		property, declared := properties[name]
+		if declared {
+			object[name] = w.property(object[name], property, childPath)
+			continue
+		}
+		if additional == nil || *additional {
+			continue
+		}
+
+		// An undeclared property that only differs in case from a declared one is
+		// probably that property misspelled; dropping it would lose the value.
+		if match := caseInsensitiveMatch(name, ir.SortedPropertyNames(properties)); match != "" {
+			w.refused = append(w.refused, Change{
+				Kind:    KindDropProperty,
+				Path:    childPath,
+				Message: fmt.Sprintf("undeclared property looks like a misspelling of %q; not dropped", match),
+			})
+			continue
+		}
+		delete(object, name)
+		w.applied = append(w.applied, Change{Kind: KindDropProperty, Path: childPath, Message: "dropped undeclared property"})
+	}
+}
+
+func (w *walker) enum(value string, enum []interface{}, path string) interface{} {
+	var matches []string
+	for _, allowed := range enum {
+		s, ok := allowed.(string)
+		if !ok {
+			continue
+		}
+		if s == value {
+			return value
+		}
+		if strings.EqualFold(s, value) {
+			matches = append(matches, s)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return value
+	case 1:
+		w.applied = append(w.applied, Change{
+			Kind:    KindEnumCase,
+			Path:    path,
+			Message: fmt.Sprintf("changed %q to %q", value, matches[0]),
+		})
+		return matches[0]
+	default:
+		w.refused = append(w.refused, Change{
+			Kind:    KindEnumCase,
+			Path:    path,
+			Message: fmt.Sprintf("%q matches several allowed values (%s) ignoring case", value, strings.Join(matches, ", ")),
+		})
+		return value
+	}
+}
+
+func caseInsensitiveMatch(name string, candidates []string) string {
+	for _, candidate := range candidates {
+		if strings.EqualFold(name, candidate) {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// encode marshals a repaired value without HTML escaping, keeping numbers as written.
+func encode(value interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
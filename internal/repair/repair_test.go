@@ -0,0 +1,160 @@
+package repair
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/promptforge/promptforge/internal/contract"
+	"github.com/promptforge/promptforge/internal/ir"
+)
+
+func testIR() *ir.PromptIR {
+	closed := false
+	return &ir.PromptIR{
+		Version:    ir.CurrentVersion,
+		SystemRole: "You triage refund requests.",
+		Rules: []ir.Rule{
+			{ID: contract.RuleOutputJSON, Description: "Output must be valid JSON"},
+			{ID: contract.RuleNoExplanations, Description: "Do not include explanations unless explicitly requested"},
+		},
+		InputSchema: ir.Schema{Type: "object"},
+		OutputSchema: ir.Schema{
+			Type: "object",
+			Properties: map[string]ir.Property{
+				"decision": {Type: "string", Enum: []interface{}{"refund", "deny", "Deny"}},
+				"priority": {Type: "string", Enum: []interface{}{"low", "high"}},
+				"reason":   {Type: "string"},
+			},
+			Required:             []string{"decision"},
+			AdditionalProperties: &closed,
+		},
+		FailureModes: []ir.FailureMode{
+			{ID: contract.FailureInvalidInput, Condition: "Input does not match input_schema", Response: "Return error"},
+		},
+	}
+}
+
+func newTestRepairer(t *testing.T) *Repairer {
+	t.Helper()
+	r, err := New(testIR())
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	return r
+}
+
+func kinds(changes []Change) string {
+	var names []string
+	for _, change := range changes {
+		names = append(names, change.Kind)
+	}
+	return strings.Join(names, ",")
+}
+
+func TestRepair(t *testing.T) {
+	r := newTestRepairer(t)
+
+	tests := []struct {
+		name        string
+		response    string
+		wantOutput  string
+		wantApplied string
+		wantRefused string
+		wantValid   bool
+	}{
+		{
+			name:       "valid response is untouched",
+			response:   `{"decision": "refund"}`,
+			wantOutput: `{"decision": "refund"}`,
+			wantValid:  true,
+		},
+		{
+			name:        "code fence",
+			response:    "```json\n{\"decision\": \"refund\"}\n```",
+			wantOutput:  `{"decision": "refund"}`,
+			wantApplied: KindStripFence,
+			wantValid:   true,
+		},
+		{
+			name:        "surrounding prose",
+			response:    "Here is the result:\n{\"decision\": \"refund\"}\nLet me know if you need more.",
+			wantOutput:  `{"decision": "refund"}`,
+			wantApplied: KindExtractJSON,
+			wantValid:   true,
+		},
+		{
+			name:        "several JSON values",
+			response:    `First {"decision": "refund"} or maybe {"decision": "deny"}`,
+			wantOutput:  `First {"decision": "refund"} or maybe {"decision": "deny"}`,
+			wantRefused: KindExtractJSON,
+		},
+		{
+			name:        "enum case",
+			response:    `{"decision": "REFUND", "priority": "High"}`,
+			wantOutput:  `{"decision":"refund","priority":"high"}`,
+			wantApplied: KindEnumCase + "," + KindEnumCase,
+			wantValid:   true,
+		},
+		{
+			name:        "ambiguous enum case",
+			response:    `{"decision": "DENY"}`,
+			wantOutput:  `{"decision": "DENY"}`,
+			wantRefused: KindEnumCase,
+		},
+		{
+			name:        "undeclared property",
+			response:    `{"decision": "deny", "confidence": 0.9}`,
+			wantOutput:  `{"decision":"deny"}`,
+			wantApplied: KindDropProperty,
+			wantValid:   true,
+		},
+		{
+			name:        "misspelled property",
+			response:    `{"decision": "deny", "Reason": "late <request>"}`,
+			wantOutput:  `{"decision": "deny", "Reason": "late <request>"}`,
+			wantRefused: KindDropProperty,
+		},
+		{
+			name:        "fenced error response",
+			response:    "```\n{\"error\": \"invalid-input\"}\n```",
+			wantOutput:  `{"error": "invalid-input"}`,
+			wantApplied: KindStripFence,
+			wantValid:   true,
+		},
+		{
+			name:       "not JSON",
+			response:   "I cannot help with that.",
+			wantOutput: "I cannot help with that.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := r.Repair([]byte(tt.response))
+			if string(result.Output) != tt.wantOutput {
+				t.Errorf("Output = %s, want %s", result.Output, tt.wantOutput)
+			}
+			if got := kinds(result.Applied); got != tt.wantApplied {
+				t.Errorf("Applied = %q, want %q", got, tt.wantApplied)
+			}
+			if got := kinds(result.Refused); got != tt.wantRefused {
+				t.Errorf("Refused = %q, want %q", got, tt.wantRefused)
+			}
+			if valid := len(result.Violations) == 0; valid != tt.wantValid {
+				t.Errorf("Violations = %v, want valid %v", result.Violations, tt.wantValid)
+			}
+		})
+	}
+}
+
+func TestRepair_ErrorResponseIsNotRewritten(t *testing.T) {
+	r := newTestRepairer(t)
+
+	result := r.Repair([]byte(`{"error": "Invalid-Input", "extra": true}`))
+	if len(result.Applied) != 0 {
+		t.Errorf("Expected no repairs to an error response, got %v", result.Applied)
+	}
+	if result.FailureMode == "" {
+		t.Error("Expected the error response to report a failure mode")
+	}
+}
@@ -0,0 +1,299 @@
+// Package scan detects secrets, personal data and prompt-injection phrasing in
+// text that ends up in a system prompt.
+package scan
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Finding codes. Secrets and card numbers are errors; the rest are warnings, since
+// a plan may legitimately mention a support address or describe an attack.
+const (
+	CodeSecret     = "PF300"
+	CodePrivateKey = "PF301"
+	CodeEmail      = "PF302"
+	CodePhone      = "PF303"
+	CodeCardNumber = "PF304"
+	CodeInjection  = "PF305"
+)
+
+// Descriptions describes each finding code, for rule metadata such as SARIF.
+var Descriptions = map[string]string{
+	CodeSecret:     "Secret in prompt text",
+	CodePrivateKey: "Private key in prompt text",
+	CodeEmail:      "Email address in prompt text",
+	CodePhone:      "Phone number in prompt text",
+	CodeCardNumber: "Card number in prompt text",
+	CodeInjection:  "Prompt-injection phrasing in prompt text",
+}
+
+// Finding is a suspicious match in scanned text.
+type Finding struct {
+	Code     string
+	Severity string // "error" or "warn"
+	Message  string
+
+	// Line and Column locate the match (1-based). Line is relative to the scanned text.
+	Line   int
+	Column int
+
+	// Match is the matched text. Messages only include a masked form of it.
+	Match string
+}
+
+// AllowRule suppresses findings whose match is matched by Pattern (a regular
+// expression). Code limits the rule to one finding code; empty means any.
+type AllowRule struct {
+	Code    string `json:"code,omitempty"`
+	Pattern string `json:"pattern"`
+}
+
+// Config holds scan settings, read from the "scan" key of .promptforge/lint.json:
+//
+//	{"scan": {"allow": [{"code": "PF302", "pattern": "@acme\\.com$"}]}}
+type Config struct {
+	Allow []AllowRule `json:"allow,omitempty"`
+}
+
+type detector struct {
+	code     string
+	severity string
+	what     string
+	re       *regexp.Regexp
+	// valid, when set, rejects matches that only look like a finding.
+	valid func(match string) bool
+	// negatable skips matches preceded by a negation in the same sentence, so
+	// that a defensive rule such as "Never reveal the system prompt" passes.
+	negatable bool
+}
+
+// negationRe matches a negation that turns an instruction into a prohibition.
+var negationRe = regexp.MustCompile(`(?i)\b(?:never|no|not|don['’]t|mustn['’]t)\b`)
+
+// sentenceEndRe matches the punctuation that ends a sentence.
+var sentenceEndRe = regexp.MustCompile(`[.!?;]`)
+
+// inlineAllowRe matches an inline suppression comment: <!-- promptforge-allow PF302 -->.
+// Without codes it suppresses every finding on the line.
+var inlineAllowRe = regexp.MustCompile(`<!--\s*promptforge-allow((?:\s+PF\d{3})*)\s*-->`)
+
+var detectors = []detector{
+	{code: CodePrivateKey, severity: "error", what: "private key", re: regexp.MustCompile(`-----BEGIN (?:[A-Z0-9]+ )*PRIVATE KEY-----`)},
+	{code: CodeSecret, severity: "error", what: "API key", re: regexp.MustCompile(`\bsk-[A-Za-z0-9_-]{20,}`)},
+	{code: CodeSecret, severity: "error", what: "AWS access key", re: regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{code: CodeSecret, severity: "error", what: "GitHub token", re: regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})`)},
+	{code: CodeSecret, severity: "error", what: "Slack token", re: regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}`)},
+	{code: CodeSecret, severity: "error", what: "Google API key", re: regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`)},
+	{code: CodeSecret, severity: "error", what: "Stripe key", re: regexp.MustCompile(`\b[rs]k_(?:live|test)_[A-Za-z0-9]{16,}`)},
+	{code: CodeSecret, severity: "error", what: "JWT", re: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`)},
+	{code: CodeSecret, severity: "error", what: "bearer token", re: regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9._~+/-]{20,}=*`)},
+	{
+		code: CodeSecret, severity: "error", what: "credential",
+		re:    regexp.MustCompile(`(?i)\b(?:api[_-]?key|secret|token|password|passwd)\s*[:=]\s*["']?[^\s"']{8,}`),
+		valid: isCredentialValue,
+	},
+	{
+		code: CodeEmail, severity: "warn", what: "email address",
+		re:    regexp.MustCompile(`\b[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}\b`),
+		valid: isRealEmail,
+	},
+	{
+		code: CodeCardNumber, severity: "error", what: "credit card number",
+		re:    regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`),
+		valid: isCardNumber,
+	},
+	{code: CodePhone, severity: "warn", what: "phone number", re: regexp.MustCompile(`(?:\+\d{1,3}[\s.-]?)?(?:\(\d{3}\)\s?|\b\d{3}[\s.-])\d{3}[\s.-]\d{4}\b|\+\d{1,3}(?:[\s.-]\d{2,4}){3,4}\b`)},
+	{
+		code: CodeInjection, severity: "warn", what: "prompt-injection phrasing",
+		re:        regexp.MustCompile(`(?i)\b(?:ignore|disregard|forget|override)\s+(?:all\s+|any\s+)?(?:of\s+)?(?:the\s+|your\s+)?(?:previous|prior|above|earlier|preceding|system)\s+(?:instructions|prompts?|rules|messages|guidelines)\b`),
+		negatable: true,
+	},
+	{code: CodeInjection, severity: "warn", what: "prompt-injection phrasing", re: regexp.MustCompile(`(?i)\byou are now\b[^.]*\b(?:DAN|developer mode|jailbroken|unrestricted|unfiltered)\b`)},
+	{
+		code: CodeInjection, severity: "warn", what: "prompt-injection phrasing",
+		re:        regexp.MustCompile(`(?i)\b(?:reveal|print|show|output|repeat|leak)\s+(?:your|the)\s+(?:system prompt|hidden instructions|initial instructions|original instructions)\b`),
+		negatable: true,
+	},
+	{code: CodeInjection, severity: "warn", what: "chat role marker", re: regexp.MustCompile(`(?i)<\|(?:im_start|im_end|system|endoftext)\|>|\[/?INST\]|<</?SYS>>`)},
+}
+
+// Validate reports an allow rule whose pattern is not a valid regular expression.
+func (c Config) Validate() error {
+	_, err := compileAllow(c.Allow)
+	return err
+}
+
+// Text scans multi-line text. Findings are ordered by line and column; a span
+// already reported by an earlier detector is not reported again.
+func Text(text string, config Config) ([]Finding, error) {
+	allow, err := compileAllow(config.Allow)
+	if err != nil {
+		return nil, err
+	}
+
+	var findings []Finding
+	for i, line := range strings.Split(text, "\n") {
+		findings = append(findings, scanLine(line, i+1, allow)...)
+	}
+	return findings, nil
+}
+
+type compiledRule struct {
+	code string
+	re   *regexp.Regexp
+}
+
+func compileAllow(rules []AllowRule) ([]compiledRule, error) {
+	compiled := make([]compiledRule, 0, len(rules))
+	for _, rule := range rules {
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid scan allow pattern %q: %w", rule.Pattern, err)
+		}
+		compiled = append(compiled, compiledRule{code: rule.Code, re: re})
+	}
+	return compiled, nil
+}
+
+func scanLine(line string, lineNum int, allow []compiledRule) []Finding {
+	var inlineCodes map[string]bool
+	inlineAll := false
+	for _, match := range inlineAllowRe.FindAllStringSubmatch(line, -1) {
+		codes := strings.Fields(match[1])
+		if len(codes) == 0 {
+			inlineAll = true
+		}
+		if inlineCodes == nil {
+			inlineCodes = make(map[string]bool)
+		}
+		for _, code := range codes {
+			inlineCodes[code] = true
+		}
+	}
+	if inlineAll {
+		return nil
+	}
+
+	var findings []Finding
+	var taken [][2]int
+	for _, d := range detectors {
+		if inlineCodes[d.code] {
+			continue
+		}
+	matches:
+		for _, loc := range d.re.FindAllStringIndex(line, -1) {
+			for _, span := range taken {
+				if loc[0] < span[1] && span[0] < loc[1] {
+					continue matches
+				}
+			}
+			match := line[loc[0]:loc[1]]
+			if d.valid != nil && !d.valid(match) {
+				continue
+			}
+			if d.negatable && negated(line[:loc[0]]) {
+				continue
+			}
+			if allowed(allow, d.code, match) {
+				continue
+			}
+			taken = append(taken, [2]int{loc[0], loc[1]})
+			findings = append(findings, Finding{
+				Code:     d.code,
+				Severity: d.severity,
+				Message:  fmt.Sprintf("possible %s (%s)", d.what, mask(d.code, match)),
+				Line:     lineNum,
+				Column:   loc[0] + 1,
+				Match:    match,
+			})
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Column < findings[j].Column
+	})
+	return findings
+}
+
+// negated reports whether the sentence that ends with before contains a negation.
+func negated(before string) bool {
+	if ends := sentenceEndRe.FindAllStringIndex(before, -1); len(ends) > 0 {
+		before = before[ends[len(ends)-1][1]:]
+	}
+	return negationRe.MatchString(before)
+}
+
+func allowed(allow []compiledRule, code, match string) bool {
+	for _, rule := range allow {
+		if (rule.code == "" || rule.code == code) && rule.re.MatchString(match) {
+			return true
+		}
+	}
+	return false
+}
+
+// mask hides most of a secret or personal value so that findings can be printed
+// in logs without leaking it. Injection phrasing is shown as-is.
+func mask(code, match string) string {
+	if code == CodeInjection {
+		return fmt.Sprintf("%q", match)
+	}
+	runes := []rune(match)
+	keep := min(4, len(runes)/4)
+	return string(runes[:keep]) + strings.Repeat("*", min(8, len(runes)-keep))
+}
+
+// isCredentialValue rejects placeholders such as {{api_key}}, <token> or xxxxxxxx.
+func isCredentialValue(match string) bool {
+	_, value, _ := strings.Cut(strings.NewReplacer(":", "=").Replace(match), "=")
+	value = strings.Trim(strings.TrimSpace(value), `"'`)
+	if strings.Contains(value, "{{") || strings.HasPrefix(value, "<") || strings.HasPrefix(value, "$") {
+		return false
+	}
+	return strings.Trim(strings.ToLower(value), "x*.-_") != ""
+}
+
+// isRealEmail ignores the domains reserved for documentation (RFC 2606).
+func isRealEmail(match string) bool {
+	domain := strings.ToLower(match[strings.LastIndex(match, "@")+1:])
+	for _, reserved := range []string{"example.com", "example.org", "example.net"} {
+		if domain == reserved || strings.HasSuffix(domain, "."+reserved) {
+			return false
+		}
+	}
+	for _, tld := range []string{".example", ".test", ".invalid", ".localhost"} {
+		if strings.HasSuffix(domain, tld) {
+			return false
+		}
+	}
+	return true
+}
+
+// isCardNumber reports whether a digit run is a plausible card number: 13 to 19
+// digits passing the Luhn check.
+func isCardNumber(match string) bool {
+	var digits []int
+	for _, r := range match {
+		if r >= '0' && r <= '9' {
+			digits = append(digits, int(r-'0'))
+		}
+	}
+	if len(digits) < 13 || len(digits) > 19 {
+		return false
+	}
+	sum := 0
+	for i := range digits {
+		d := digits[len(digits)-1-i]
+		if i%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
@@ -0,0 +1,154 @@
+package server
+
+import (
+	"encoding/json"
+)
+
+// APIVersion is the version of the HTTP API reported in the OpenAPI document.
+const APIVersion = "1.0.0"
+
+type object = map[string]interface{}
+
+// endpoint describes a POST endpoint for the OpenAPI document.
+type endpoint struct {
+	path     string
+	summary  string
+	request  string
+	response string
+}
+
+var endpoints = []endpoint{
+	{path: "/lint", summary: "Lint plan.md content", request: "PlanRequest", response: "LintResponse"},
+	{path: "/compile", summary: "Compile plan.md content into prompt.ir.json", request: "PlanRequest", response: "CompileResponse"},
+	{path: "/explain", summary: "Compile plan.md content and map each IR field back to the plan", request: "PlanRequest", response: "CompileResponse"},
+	{path: "/audit", summary: "Validate an IR and scan its prompt text for secrets, PII and prompt injection", request: "IRRequest", response: "AuditResponse"},
+	{path: "/validate-output", summary: "Check a model response against the IR contract, optionally repairing it", request: "OutputRequest", response: "OutputResponse"},
+	{path: "/emit", summary: "Generate JSON Schemas, TypeScript and Go types for the IR contract", request: "IRRequest", response: "EmitResponse"},
+}
+
+// OpenAPIDocument returns the OpenAPI 3 description of the API served at /openapi.json.
+func OpenAPIDocument() ([]byte, error) {
+	paths := object{
+		"/openapi.json": object{
+			"get": object{
+				"summary":   "This document",
+				"responses": object{"200": object{"description": "OpenAPI document", "content": object{"application/json": object{}}}},
+			},
+		},
+	}
+	for _, e := range endpoints {
+		paths[e.path] = object{
+			"post": object{
+				"summary":     e.summary,
+				"requestBody": object{"required": true, "content": jsonContent(e.request)},
+				"responses": object{
+					"200": object{"description": "OK", "content": jsonContent(e.response)},
+					"400": errorResponse("Malformed request"),
+					"405": errorResponse("Method not allowed"),
+					"413": errorResponse("Request body too large"),
+					"422": errorResponse("The plan or IR cannot be compiled or validated"),
+				},
+			},
+		}
+	}
+
+	document := object{
+		"openapi": "3.0.3",
+		"info": object{
+			"title":   "PromptForge API",
+			"version": APIVersion,
+		},
+		"paths": paths,
+		"components": object{
+			"schemas": object{
+				"PlanRequest": objectSchema([]string{"plan"}, object{
+					"plan": object{"type": "string", "description": "plan.md content; include and extends directives are not expanded"},
+				}),
+				"IRRequest": objectSchema([]string{"ir"}, object{
+					"ir":         object{"type": "object", "description": "prompt.ir.json content"},
+					"go_package": object{"type": "string", "description": "Package clause of the generated Go file (/emit only)"},
+				}),
+				"OutputRequest": objectSchema([]string{"ir", "response"}, object{
+					"ir":       object{"type": "object", "description": "prompt.ir.json content"},
+					"response": object{"type": "string", "description": "Raw model response"},
+					"repair":   object{"type": "boolean", "description": "Apply safe deterministic repairs first"},
+				}),
+				"LintResponse": objectSchema([]string{"diagnostics"}, object{
+					"diagnostics": arrayOf(objectSchema([]string{"severity", "code", "message", "line", "column"}, object{
+						"severity": object{"type": "string", "enum": []string{"error", "warn"}},
+						"code":     object{"type": "string"},
+						"message":  object{"type": "string"},
+						"line":     object{"type": "integer"},
+						"column":   object{"type": "integer"},
+						"fix":      object{"type": "object"},
+						"score":    object{"type": "integer"},
+					})),
+				}),
+				"CompileResponse": objectSchema([]string{"ir"}, object{
+					"ir":      object{"type": "object", "description": "prompt.ir.json content"},
+					"explain": object{"type": "object", "description": "prompt.ir.explain.json content (/explain only)"},
+				}),
+				"AuditResponse": objectSchema([]string{"issues"}, object{
+					"issues": arrayOf(objectSchema([]string{"code", "severity", "message", "artifact"}, object{
+						"code":     object{"type": "string", "description": "Stable issue code, such as PF401"},
+						"severity": object{"type": "string", "enum": []string{"error", "warn"}},
+						"message":  object{"type": "string"},
+						"artifact": object{"type": "string", "description": "File the issue concerns, such as prompt.ir.json"},
+						"pointer":  object{"type": "string", "description": "JSON pointer of the value in the artifact"},
+					})),
+				}),
+				"OutputResponse": objectSchema([]string{"output", "applied", "refused", "violations"}, object{
+					"output":       object{"type": "string", "description": "The checked response: the repaired one when repairs were applied"},
+					"applied":      arrayOf(ref("Change")),
+					"refused":      arrayOf(ref("Change")),
+					"failure_mode": object{"type": "string"},
+					"violations": arrayOf(objectSchema([]string{"id", "message"}, object{
+						"id":      object{"type": "string"},
+						"path":    object{"type": "string"},
+						"message": object{"type": "string"},
+					})),
+				}),
+				"Change": objectSchema([]string{"kind", "message"}, object{
+					"kind":    object{"type": "string", "enum": []string{"strip-fence", "extract-json", "enum-case", "drop-property"}},
+					"path":    object{"type": "string"},
+					"message": object{"type": "string"},
+				}),
+				"EmitResponse": objectSchema([]string{"artifacts"}, object{
+					"artifacts": arrayOf(objectSchema([]string{"name", "content"}, object{
+						"name":    object{"type": "string"},
+						"content": object{"type": "string"},
+					})),
+				}),
+				"ErrorResponse": objectSchema([]string{"error"}, object{
+					"error":  object{"type": "string"},
+					"code":   object{"type": "string", "description": "Stable error code, such as plan-syntax or ir-invalid"},
+					"file":   object{"type": "string"},
+					"line":   object{"type": "integer"},
+					"column": object{"type": "integer"},
+					"hint":   object{"type": "string"},
+				}),
+			},
+		},
+	}
+	return json.MarshalIndent(document, "", "  ")
+}
+
+func ref(name string) object {
+	return object{"$ref": "#/components/schemas/" + name}
+}
+
+func jsonContent(schema string) object {
+	return object{"application/json": object{"schema": ref(schema)}}
+}
+
+func errorResponse(description string) object {
+	return object{"description": description, "content": jsonContent("ErrorResponse")}
+}
+
+func objectSchema(required []string, properties object) object {
+	return object{"type": "object", "required": required, "properties": properties}
+}
+
+func arrayOf(items object) object {
+	return object{"type": "array", "items": items}
+}
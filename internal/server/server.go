@@ -0,0 +1,372 @@
+// Package server exposes the compiler, linter, auditor and contract checks as a
+// local JSON API, so editors and the UI can use them without going through files
+// and the CLI binary. Every endpoint takes plan or IR content in the request body
+// and never reads or writes project files.
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+
+	"github.com/promptforge/promptforge"
+	"github.com/promptforge/promptforge/internal/codegen"
+	"github.com/promptforge/promptforge/internal/compiler"
+	"github.com/promptforge/promptforge/internal/contract"
+	"github.com/promptforge/promptforge/internal/core"
+	"github.com/promptforge/promptforge/internal/ir"
+	"github.com/promptforge/promptforge/internal/linter"
+	"github.com/promptforge/promptforge/internal/repair"
+)
+
+// DefaultMaxBodyBytes is the request body limit used when Options sets none.
+const DefaultMaxBodyBytes = 1 << 20
+
+// Options configures the API handler.
+type Options struct {
+	// LintConfig is used by /lint and, for its scan allowlist, by /audit.
+	LintConfig linter.Config
+
+	// MaxBodyBytes limits request bodies; larger requests get 413.
+	MaxBodyBytes int64
+
+	// UI, when set, is served at / next to the API.
+	UI fs.FS
+}
+
+// PlanRequest is the body of /lint, /compile and /explain.
+type PlanRequest struct {
+	Plan string `json:"plan"`
+}
+
+// IRRequest is the body of /audit and /emit.
+type IRRequest struct {
+	IR json.RawMessage `json:"ir"`
+
+	// GoPackage is the package clause of the generated Go file (/emit only).
+	GoPackage string `json:"go_package,omitempty"`
+}
+
+// OutputRequest is the body of /validate-output.
+type OutputRequest struct {
+	IR       json.RawMessage `json:"ir"`
+	Response string          `json:"response"`
+	Repair   bool            `json:"repair,omitempty"`
+}
+
+// LintResponse is returned by /lint.
+type LintResponse struct {
+	Diagnostics []linter.Diagnostic `json:"diagnostics"`
+}
+
+// CompileResponse is returned by /compile and /explain.
+type CompileResponse struct {
+	IR      *ir.PromptIR            `json:"ir"`
+	Explain *compiler.ExplainReport `json:"explain,omitempty"`
+}
+
+// AuditResponse is returned by /audit.
+type AuditResponse struct {
+	Issues []core.AuditIssue `json:"issues"`
+}
+
+// OutputResponse is returned by /validate-output.
+type OutputResponse struct {
+	// Output is the checked response: the repaired one when repairs were applied.
+	Output      string               `json:"output"`
+	Applied     []repair.Change      `json:"applied"`
+	Refused     []repair.Change      `json:"refused"`
+	FailureMode string               `json:"failure_mode,omitempty"`
+	Violations  []contract.Violation `json:"violations"`
+}
+
+// Artifact is one generated file returned by /emit.
+type Artifact struct {
+	Name    string `json:"name"`
+	Content string `json:"content"`
+}
+
+// EmitResponse is returned by /emit.
+type EmitResponse struct {
+	Artifacts []Artifact `json:"artifacts"`
+}
+
+// ErrorResponse is returned with every non-2xx status. Code, the position and
+// Hint are set when the error is a *promptforge.Error.
+type ErrorResponse struct {
+	Error  string           `json:"error"`
+	Code   promptforge.Code `json:"code,omitempty"`
+	File   string           `json:"file,omitempty"`
+	Line   int              `json:"line,omitempty"`
+	Column int              `json:"column,omitempty"`
+	Hint   string           `json:"hint,omitempty"`
+}
+
+// errorBody describes err, with its code and position when it has them.
+func errorBody(err error) ErrorResponse {
+	response := ErrorResponse{Error: err.Error()}
+	var e *promptforge.Error
+	if errors.As(err, &e) {
+		response.Code = e.Code
+		response.File = e.File
+		response.Line = e.Line
+		response.Column = e.Column
+		response.Hint = e.Hint
+	}
+	return response
+}
+
+type handler struct {
+	options Options
+	openAPI []byte
+}
+
+// New returns the API handler. POST endpoints answer 400 for malformed requests,
+// 413 for bodies over the size limit and 422 when the plan or IR cannot be
+// compiled or validated.
+func New(opts Options) (http.Handler, error) {
+	if opts.MaxBodyBytes <= 0 {
+		opts.MaxBodyBytes = DefaultMaxBodyBytes
+	}
+	document, err := OpenAPIDocument()
+	if err != nil {
+		return nil, err
+	}
+
+	h := &handler{options: opts, openAPI: document}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/lint", h.post(h.lint))
+	mux.HandleFunc("/compile", h.post(h.compile))
+	mux.HandleFunc("/explain", h.post(h.explain))
+	mux.HandleFunc("/audit", h.post(h.audit))
+	mux.HandleFunc("/validate-output", h.post(h.validateOutput))
+	mux.HandleFunc("/emit", h.post(h.emit))
+	mux.HandleFunc("/openapi.json", h.serveOpenAPI)
+	if opts.UI != nil {
+		mux.Handle("/", http.FileServer(http.FS(opts.UI)))
+	}
+	return mux, nil
+}
+
+// statusError is an error with the HTTP status it should be reported with.
+type statusError struct {
+	status int
+	err    error
+}
+
+func (e *statusError) Error() string {
+	return e.err.Error()
+}
+
+func (e *statusError) Unwrap() error {
+	return e.err
+}
+
+func badRequest(format string, args ...interface{}) error {
+	return &statusError{status: http.StatusBadRequest, err: fmt.Errorf(format, args...)}
+}
+
+func unprocessable(err error) error {
+	return &statusError{status: http.StatusUnprocessableEntity, err: err}
+}
+
+// post adapts an endpoint that decodes its body with decode and returns the
+// response value to encode.
+func (h *handler) post(endpoint func(decode func(interface{}) error) (interface{}, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeJSON(w, http.StatusMethodNotAllowed, ErrorResponse{Error: fmt.Sprintf("method %s not allowed", r.Method)})
+			return
+		}
+
+		body := http.MaxBytesReader(w, r.Body, h.options.MaxBodyBytes)
+		decode := func(v interface{}) error {
+			decoder := json.NewDecoder(body)
+			decoder.DisallowUnknownFields()
+			if err := decoder.Decode(v); err != nil {
+				var tooLarge *http.MaxBytesError
+				if errors.As(err, &tooLarge) {
+					return &statusError{
+						status: http.StatusRequestEntityTooLarge,
+						err:    fmt.Errorf("request body exceeds %d bytes", tooLarge.Limit),
+					}
+				}
+				if errors.Is(err, io.EOF) {
+					return badRequest("request body is empty")
+				}
+				return badRequest("invalid request body: %v", err)
+			}
+			if decoder.More() {
+				return badRequest("invalid request body: unexpected data after the JSON object")
+			}
+			return nil
+		}
+
+		response, err := endpoint(decode)
+		if err != nil {
+			status := http.StatusInternalServerError
+			var se *statusError
+			if errors.As(err, &se) {
+				status = se.status
+			}
+			writeJSON(w, status, errorBody(err))
+			return
+		}
+		writeJSON(w, http.StatusOK, response)
+	}
+}
+
+func (h *handler) lint(decode func(interface{}) error) (interface{}, error) {
+	var req PlanRequest
+	if err := decode(&req); err != nil {
+		return nil, err
+	}
+	diagnostics := linter.LintPlanWithConfig([]byte(req.Plan), h.options.LintConfig)
+	if diagnostics == nil {
+		diagnostics = []linter.Diagnostic{}
+	}
+	return LintResponse{Diagnostics: diagnostics}, nil
+}
+
+func (h *handler) compile(decode func(interface{}) error) (interface{}, error) {
+	var req PlanRequest
+	if err := decode(&req); err != nil {
+		return nil, err
+	}
+	promptIR, err := compiler.Compile([]byte(req.Plan))
+	if err != nil {
+		return nil, unprocessable(fmt.Errorf("compilation failed: %w", err))
+	}
+	if err := compiler.ValidateIR(promptIR); err != nil {
+		return nil, unprocessable(fmt.Errorf("IR validation failed: %w", err))
+	}
+	return CompileResponse{IR: promptIR}, nil
+}
+
+func (h *handler) explain(decode func(interface{}) error) (interface{}, error) {
+	var req PlanRequest
+	if err := decode(&req); err != nil {
+		return nil, err
+	}
+	promptIR, report, err := compiler.CompileWithExplain([]byte(req.Plan))
+	if err != nil {
+		return nil, unprocessable(fmt.Errorf("compilation failed: %w", err))
+	}
+	if err := compiler.ValidateIR(promptIR); err != nil {
+		return nil, unprocessable(fmt.Errorf("IR validation failed: %w", err))
+	}
+	return CompileResponse{IR: promptIR, Explain: report}, nil
+}
+
+func (h *handler) audit(decode func(interface{}) error) (interface{}, error) {
+	var req IRRequest
+	if err := decode(&req); err != nil {
+		return nil, err
+	}
+	promptIR, err := parseIR(req.IR)
+	if err != nil {
+		return nil, err
+	}
+	issues, err := core.AuditIR(promptIR, h.options.LintConfig.Scan)
+	if err != nil {
+		return nil, err
+	}
+	if issues == nil {
+		issues = []core.AuditIssue{}
+	}
+	return AuditResponse{Issues: issues}, nil
+}
+
+func (h *handler) validateOutput(decode func(interface{}) error) (interface{}, error) {
+	var req OutputRequest
+	if err := decode(&req); err != nil {
+		return nil, err
+	}
+	promptIR, err := parseIR(req.IR)
+	if err != nil {
+		return nil, err
+	}
+	report, err := core.ValidateOutputIR(promptIR, []byte(req.Response), req.Repair)
+	if err != nil {
+		return nil, unprocessable(err)
+	}
+
+	response := OutputResponse{
+		Output:      string(report.Output),
+		Applied:     report.Applied,
+		Refused:     report.Refused,
+		FailureMode: report.FailureMode,
+		Violations:  report.Violations,
+	}
+	if response.Applied == nil {
+		response.Applied = []repair.Change{}
+	}
+	if response.Refused == nil {
+		response.Refused = []repair.Change{}
+	}
+	if response.Violations == nil {
+		response.Violations = []contract.Violation{}
+	}
+	return response, nil
+}
+
+func (h *handler) emit(decode func(interface{}) error) (interface{}, error) {
+	var req IRRequest
+	if err := decode(&req); err != nil {
+		return nil, err
+	}
+	promptIR, err := parseIR(req.IR)
+	if err != nil {
+		return nil, err
+	}
+	if err := compiler.ValidateIR(promptIR); err != nil {
+		return nil, unprocessable(fmt.Errorf("IR validation failed: %w", err))
+	}
+
+	artifacts, err := codegen.Generate(promptIR, codegen.Options{GoPackage: req.GoPackage})
+	if err != nil {
+		return nil, unprocessable(fmt.Errorf("code generation failed: %w", err))
+	}
+	response := EmitResponse{Artifacts: make([]Artifact, 0, len(artifacts))}
+	for _, artifact := range artifacts {
+		response.Artifacts = append(response.Artifacts, Artifact{Name: artifact.Name, Content: string(artifact.Content)})
+	}
+	return response, nil
+}
+
+func (h *handler) serveOpenAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		writeJSON(w, http.StatusMethodNotAllowed, ErrorResponse{Error: fmt.Sprintf("method %s not allowed", r.Method)})
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(h.openAPI)
+}
+
+// parseIR decodes the "ir" field of a request.
+func parseIR(data json.RawMessage) (*ir.PromptIR, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return nil, badRequest("missing ir")
+	}
+	var promptIR ir.PromptIR
+	if err := json.Unmarshal(data, &promptIR); err != nil {
+		return nil, badRequest("failed to parse ir: %v", err)
+	}
+	return &promptIR, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		status = http.StatusInternalServerError
+		data, _ = json.Marshal(ErrorResponse{Error: fmt.Sprintf("failed to encode response: %v", err)})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(append(data, '\n'))
+}
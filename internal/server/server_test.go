@@ -0,0 +1,253 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/promptforge/promptforge"
+	"github.com/promptforge/promptforge/internal/codegen"
+	"github.com/promptforge/promptforge/internal/linter"
+)
+
+const testPlan = "# Prompt Plan\n\n## Goal\nTriage refund requests for an online store.\n\n## Constraints\n- Reply in English\n- reply in English\n\n## Out of Scope\n- Payments\n"
+
+func newTestServer(t *testing.T, maxBodyBytes int64) *httptest.Server {
+	t.Helper()
+	handler, err := New(Options{LintConfig: linter.DefaultConfig(), MaxBodyBytes: maxBodyBytes})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// post sends body as JSON to path, decodes the JSON response into out and
+// returns the status code.
+func post(t *testing.T, srv *httptest.Server, path string, body interface{}, out interface{}) int {
+	t.Helper()
+	data, err := json.Marshal(body)
+	if err != nil {
+		t.Fatalf("Failed to marshal request: %v", err)
+	}
+	resp, err := http.Post(srv.URL+path, "application/json", strings.NewReader(string(data)))
+	if err != nil {
+		t.Fatalf("POST %s failed: %v", path, err)
+	}
+	defer resp.Body.Close()
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		t.Fatalf("Failed to decode %s response: %v", path, err)
+	}
+	return resp.StatusCode
+}
+
+// compileTestPlan compiles testPlan through the API and returns the IR.
+func compileTestPlan(t *testing.T, srv *httptest.Server) json.RawMessage {
+	t.Helper()
+	var resp struct {
+		IR json.RawMessage `json:"ir"`
+	}
+	if status := post(t, srv, "/compile", PlanRequest{Plan: testPlan}, &resp); status != http.StatusOK {
+		t.Fatalf("POST /compile returned %d", status)
+	}
+	return resp.IR
+}
+
+func TestLint(t *testing.T) {
+	srv := newTestServer(t, 0)
+
+	var resp LintResponse
+	if status := post(t, srv, "/lint", PlanRequest{Plan: testPlan}, &resp); status != http.StatusOK {
+		t.Fatalf("POST /lint returned %d", status)
+	}
+	found := false
+	for _, diag := range resp.Diagnostics {
+		if diag.Code == "PF207" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected a PF207 duplicate constraint diagnostic, got %+v", resp.Diagnostics)
+	}
+}
+
+func TestCompileAndExplain(t *testing.T) {
+	srv := newTestServer(t, 0)
+
+	if ir := compileTestPlan(t, srv); !strings.Contains(string(ir), `"system_role"`) {
+		t.Errorf("Unexpected IR %s", ir)
+	}
+
+	var explained CompileResponse
+	if status := post(t, srv, "/explain", PlanRequest{Plan: testPlan}, &explained); status != http.StatusOK {
+		t.Fatalf("POST /explain returned %d", status)
+	}
+	if explained.IR == nil || explained.Explain == nil || len(explained.Explain.Rules) == 0 {
+		t.Errorf("Expected an IR and an explain report, got %+v", explained)
+	}
+
+	var failed ErrorResponse
+	if status := post(t, srv, "/compile", PlanRequest{}, &failed); status != http.StatusUnprocessableEntity || !strings.Contains(failed.Error, "compilation failed") {
+		t.Errorf("Expected 422 for an empty plan, got %d %q", status, failed.Error)
+	}
+	if failed.Code != promptforge.CodePlanEmpty {
+		t.Errorf("Expected code %s, got %q", promptforge.CodePlanEmpty, failed.Code)
+	}
+
+	var missingGoal ErrorResponse
+	post(t, srv, "/compile", PlanRequest{Plan: "## Goal\n\n## Constraints\n- x\n"}, &missingGoal)
+	if missingGoal.Code != promptforge.CodePlanSyntax || missingGoal.Hint == "" {
+		t.Errorf("Expected a plan-syntax error with a hint, got %+v", missingGoal)
+	}
+
+	// The plan compiles, but the tool name breaks the IR schema.
+	invalidTool := PlanRequest{Plan: "## Goal\nLook up orders\n\n## Tools\n### look up orders\nFind orders\n"}
+	for _, path := range []string{"/compile", "/explain"} {
+		var invalid ErrorResponse
+		status := post(t, srv, path, invalidTool, &invalid)
+		if status != http.StatusUnprocessableEntity || !strings.Contains(invalid.Error, "IR validation failed") {
+			t.Errorf("Expected 422 from %s for an invalid IR, got %d %q", path, status, invalid.Error)
+		}
+		if invalid.Code != promptforge.CodeIRInvalid {
+			t.Errorf("Expected code %s from %s, got %q", promptforge.CodeIRInvalid, path, invalid.Code)
+		}
+	}
+}
+
+func TestAuditAndEmit(t *testing.T) {
+	srv := newTestServer(t, 0)
+	promptIR := compileTestPlan(t, srv)
+
+	var audit AuditResponse
+	if status := post(t, srv, "/audit", IRRequest{IR: promptIR}, &audit); status != http.StatusOK {
+		t.Fatalf("POST /audit returned %d", status)
+	}
+	if len(audit.Issues) != 0 {
+		t.Errorf("Expected no audit issues for a freshly compiled IR, got %+v", audit.Issues)
+	}
+
+	var emitted EmitResponse
+	if status := post(t, srv, "/emit", IRRequest{IR: promptIR, GoPackage: "refunds"}, &emitted); status != http.StatusOK {
+		t.Fatalf("POST /emit returned %d", status)
+	}
+	if len(emitted.Artifacts) != 4 {
+		t.Fatalf("Expected 4 artifacts, got %d", len(emitted.Artifacts))
+	}
+	for _, artifact := range emitted.Artifacts {
+		if artifact.Name == codegen.GoFile && !strings.Contains(artifact.Content, "package refunds") {
+			t.Errorf("Expected the Go package to be refunds, got:\n%s", artifact.Content)
+		}
+	}
+
+	var failed ErrorResponse
+	if status := post(t, srv, "/audit", IRRequest{}, &failed); status != http.StatusBadRequest || failed.Error != "missing ir" {
+		t.Errorf("Expected 400 for a missing IR, got %d %q", status, failed.Error)
+	}
+}
+
+func TestValidateOutput(t *testing.T) {
+	srv := newTestServer(t, 0)
+	promptIR := compileTestPlan(t, srv)
+
+	var resp OutputResponse
+	status := post(t, srv, "/validate-output", OutputRequest{IR: promptIR, Response: "```json\n{}\n```", Repair: true}, &resp)
+	if status != http.StatusOK {
+		t.Fatalf("POST /validate-output returned %d", status)
+	}
+	if resp.Output != "{}" || len(resp.Applied) != 1 || len(resp.Violations) != 0 {
+		t.Errorf("Expected the code fence to be repaired, got %+v", resp)
+	}
+
+	status = post(t, srv, "/validate-output", OutputRequest{IR: promptIR, Response: "not json"}, &resp)
+	if status != http.StatusOK || len(resp.Violations) == 0 || resp.Violations[0].ID != "output-json" {
+		t.Errorf("Expected an output-json violation, got %d %+v", status, resp)
+	}
+}
+
+func TestRequestLimits(t *testing.T) {
+	srv := newTestServer(t, 64)
+
+	var failed ErrorResponse
+	if status := post(t, srv, "/lint", PlanRequest{Plan: strings.Repeat("x", 100)}, &failed); status != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected 413 for an oversized body, got %d %q", status, failed.Error)
+	}
+	if status := post(t, srv, "/lint", map[string]string{"plan": "", "extra": ""}, &failed); status != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an unknown field, got %d %q", status, failed.Error)
+	}
+
+	resp, err := http.Get(srv.URL + "/lint")
+	if err != nil {
+		t.Fatalf("GET /lint failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed || resp.Header.Get("Allow") != http.MethodPost {
+		t.Errorf("Expected 405 with Allow: POST, got %d %q", resp.StatusCode, resp.Header.Get("Allow"))
+	}
+}
+
+func TestOpenAPI(t *testing.T) {
+	srv := newTestServer(t, 0)
+
+	resp, err := http.Get(srv.URL + "/openapi.json")
+	if err != nil {
+		t.Fatalf("GET /openapi.json failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var document struct {
+		OpenAPI string                 `json:"openapi"`
+		Paths   map[string]interface{} `json:"paths"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&document); err != nil {
+		t.Fatalf("Failed to decode OpenAPI document: %v", err)
+	}
+	if document.OpenAPI != "3.0.3" {
+		t.Errorf("Unexpected openapi version %q", document.OpenAPI)
+	}
+
+	// Every documented endpoint must be served.
+	for _, e := range endpoints {
+		if _, ok := document.Paths[e.path]; !ok {
+			t.Errorf("OpenAPI document is missing %s", e.path)
+		}
+		resp, err := http.Post(srv.URL+e.path, "application/json", strings.NewReader("{}"))
+		if err != nil {
+			t.Fatalf("POST %s failed: %v", e.path, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusNotFound {
+			t.Errorf("%s is documented but not served", e.path)
+		}
+	}
+}
+
+func TestUI(t *testing.T) {
+	ui := fstest.MapFS{"index.html": {Data: []byte("<title>PromptForge Lens</title>")}}
+	handler, err := New(Options{LintConfig: linter.DefaultConfig(), UI: ui})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/")
+	if err != nil {
+		t.Fatalf("GET / failed: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), "PromptForge Lens") {
+		t.Errorf("Expected the UI at /, got %d %s", resp.StatusCode, body)
+	}
+
+	// The API still takes precedence over the UI.
+	var lint LintResponse
+	if status := post(t, srv, "/lint", PlanRequest{Plan: testPlan}, &lint); status != http.StatusOK {
+		t.Errorf("POST /lint returned %d with the UI mounted", status)
+	}
+}
@@ -0,0 +1,188 @@
+package templates
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/promptforge/promptforge/internal/parser"
+)
+
+// Template sources, from lowest to highest precedence.
+const (
+	SourceBuiltin = "built-in"
+	SourceUser    = "user"
+	SourceProject = "project"
+)
+
+var templateNameRe = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// UserDir returns the per-user template directory, ~/.config/promptforge/templates.
+func UserDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("cannot locate home directory: %w", err)
+	}
+	return filepath.Join(home, ".config", "promptforge", "templates"), nil
+}
+
+// ProjectDir returns the project template directory, <project>/.promptforge/templates.
+func ProjectDir(projectDir string) string {
+	return filepath.Join(projectDir, ".promptforge", "templates")
+}
+
+// Catalog returns the built-in templates together with those found in the user
+// and project template directories, sorted by name. Project templates shadow user
+// templates, which shadow built-ins of the same name. projectDir may be empty to
+// skip project templates. A template file that fails to load is listed with Err
+// set rather than failing the whole catalog.
+func Catalog(projectDir string) ([]Template, error) {
+	byName := make(map[string]Template)
+	for _, tmpl := range registry {
+		tmpl.Source = SourceBuiltin
+		byName[tmpl.Name] = tmpl
+	}
+
+	userDir, err := UserDir()
+	if err == nil {
+		if err := loadDir(userDir, SourceUser, byName); err != nil {
+			return nil, err
+		}
+	}
+	if projectDir != "" {
+		if err := loadDir(ProjectDir(projectDir), SourceProject, byName); err != nil {
+			return nil, err
+		}
+	}
+
+	catalog := make([]Template, 0, len(byName))
+	for _, tmpl := range byName {
+		catalog = append(catalog, tmpl)
+	}
+	sort.Slice(catalog, func(i, j int) bool { return catalog[i].Name < catalog[j].Name })
+	return catalog, nil
+}
+
+// Find returns the named template from Catalog(projectDir). It fails when that
+// template did not load.
+func Find(projectDir, name string) (Template, error) {
+	catalog, err := Catalog(projectDir)
+	if err != nil {
+		return Template{}, err
+	}
+	for _, tmpl := range catalog {
+		if tmpl.Name == name {
+			if tmpl.Err != nil {
+				return Template{}, tmpl.Err
+			}
+			return tmpl, nil
+		}
+	}
+	return Template{}, fmt.Errorf("unknown template: %s (run 'promptforge templates' to list templates)", name)
+}
+
+// loadDir adds the templates in dir to byName. A template is either a *.md file in
+// dir or a plan.md file in a subdirectory. A missing directory is not an error; a
+// template that fails to load is added with Err set.
+func loadDir(dir, source string, byName map[string]Template) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("cannot read template directory %s: %w", dir, err)
+	}
+
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		if entry.IsDir() {
+			path = filepath.Join(path, "plan.md")
+			if _, err := os.Stat(path); err != nil {
+				continue
+			}
+		} else if filepath.Ext(entry.Name()) != ".md" {
+			continue
+		}
+
+		tmpl, err := LoadFile(path)
+		if err != nil {
+			tmpl = Template{Name: fallbackName(path), Path: path, Err: err}
+		}
+		tmpl.Source = source
+		byName[tmpl.Name] = tmpl
+	}
+	return nil
+}
+
+// LoadFile reads a template plan. The name and description come from front matter:
+//
+//	---
+//	name: billing-triage
+//	description: Route billing tickets
+//	---
+//
+// Parameters are declared with repeated "param:" entries (see Template.Render).
+// When name is absent it is derived from the file name (or, for plan.md, from the
+// directory name). The front matter block is not part of the template plan.
+func LoadFile(path string) (Template, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return Template{}, fmt.Errorf("cannot read template %s: %w", path, err)
+	}
+	return parseTemplate(path, content)
+}
+
+func parseTemplate(path string, content []byte) (Template, error) {
+	text := strings.ReplaceAll(string(content), "\r\n", "\n")
+	tmpl := Template{Path: path, Plan: text}
+
+	if frontMatter := parser.ParseFrontMatter([]byte(text)); frontMatter != nil {
+		tmpl.Name = frontMatter.Get("name")
+		tmpl.Description = frontMatter.Get("description")
+		params, err := parseParams(path, frontMatter.All("param"))
+		if err != nil {
+			return Template{}, err
+		}
+		tmpl.Params = params
+		lines := strings.Split(text, "\n")
+		tmpl.Plan = strings.TrimLeft(strings.Join(lines[frontMatter.EndLine:], "\n"), "\n")
+	}
+
+	if tmpl.Name == "" {
+		tmpl.Name = defaultName(path)
+	}
+	if !templateNameRe.MatchString(tmpl.Name) {
+		return Template{}, fmt.Errorf("invalid template name %q in %s (use lowercase letters, digits, '-' or '_')", tmpl.Name, path)
+	}
+	if _, err := parser.ParsePlan([]byte(tmpl.Plan)); err != nil {
+		return Template{}, fmt.Errorf("invalid template %s: %w", path, err)
+	}
+
+	return tmpl, nil
+}
+
+// fallbackName names a template that failed to load: its front matter name when
+// that can be read and is valid, otherwise the name derived from its path.
+func fallbackName(path string) string {
+	content, err := os.ReadFile(path)
+	if err == nil {
+		if frontMatter := parser.ParseFrontMatter(content); frontMatter != nil {
+			if name := frontMatter.Get("name"); templateNameRe.MatchString(name) {
+				return name
+			}
+		}
+	}
+	return defaultName(path)
+}
+
+func defaultName(path string) string {
+	base := filepath.Base(path)
+	if base == "plan.md" {
+		return filepath.Base(filepath.Dir(path))
+	}
+	base = strings.TrimSuffix(base, ".md")
+	return strings.TrimSuffix(base, ".plan")
+}
@@ -0,0 +1,145 @@
+package templates
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const customPlan = `---
+name: %s
+description: %s
+---
+
+# Prompt Plan
+
+## Goal
+Route billing tickets to the right queue.
+
+## Constraints
+- Must return JSON only
+`
+
+func writeTemplate(t *testing.T, path, name, description string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Failed to create template directory: %v", err)
+	}
+	content := fmt.Sprintf(customPlan, name, description)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+}
+
+func TestCatalog_UserAndProjectTemplates(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	projectDir := t.TempDir()
+
+	userDir := filepath.Join(home, ".config", "promptforge", "templates")
+	writeTemplate(t, filepath.Join(userDir, "billing.md"), "billing", "User billing")
+	writeTemplate(t, filepath.Join(userDir, "code-review", "plan.md"), "code-review", "User review")
+	writeTemplate(t, filepath.Join(ProjectDir(projectDir), "billing.md"), "billing", "Project billing")
+
+	catalog, err := Catalog(projectDir)
+	if err != nil {
+		t.Fatalf("Catalog() failed: %v", err)
+	}
+	if len(catalog) != len(registry)+1 {
+		t.Fatalf("Expected %d templates, got %d", len(registry)+1, len(catalog))
+	}
+
+	billing, err := Find(projectDir, "billing")
+	if err != nil {
+		t.Fatalf("Find(billing) failed: %v", err)
+	}
+	if billing.Source != SourceProject || billing.Description != "Project billing" {
+		t.Errorf("Project template should shadow user template, got %+v", billing)
+	}
+	if strings.Contains(billing.Plan, "description:") || !strings.HasPrefix(billing.Plan, "# Prompt Plan") {
+		t.Errorf("Front matter should be stripped from the plan, got %q", billing.Plan)
+	}
+
+	review, err := Find(projectDir, "code-review")
+	if err != nil {
+		t.Fatalf("Find(code-review) failed: %v", err)
+	}
+	if review.Source != SourceUser {
+		t.Errorf("User template should shadow the built-in, got %+v", review)
+	}
+
+	if _, err := Find(projectDir, "missing"); err == nil {
+		t.Error("Expected error for unknown template")
+	}
+}
+
+func TestLoadFile_Validation(t *testing.T) {
+	dir := t.TempDir()
+
+	unnamed := filepath.Join(dir, "support-desk.plan.md")
+	if err := os.WriteFile(unnamed, []byte("## Goal\nAnswer questions\n"), 0644); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+	tmpl, err := LoadFile(unnamed)
+	if err != nil {
+		t.Fatalf("LoadFile() failed: %v", err)
+	}
+	if tmpl.Name != "support-desk" {
+		t.Errorf("Expected name derived from file name, got %q", tmpl.Name)
+	}
+
+	badName := filepath.Join(dir, "bad.md")
+	writeTemplate(t, badName, "Bad Name", "Invalid")
+	if _, err := LoadFile(badName); err == nil || !strings.Contains(err.Error(), "invalid template name") {
+		t.Errorf("Expected invalid name error, got %v", err)
+	}
+
+	noGoal := filepath.Join(dir, "no-goal.md")
+	if err := os.WriteFile(noGoal, []byte("---\nname: no-goal\n---\n## Constraints\n- x\n"), 0644); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+	if _, err := LoadFile(noGoal); err == nil {
+		t.Error("Expected error for template without a Goal")
+	}
+}
+
+func TestCatalog_InvalidTemplate(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	projectDir := t.TempDir()
+
+	userDir := filepath.Join(home, ".config", "promptforge", "templates")
+	writeTemplate(t, filepath.Join(userDir, "billing.md"), "billing", "User billing")
+	if err := os.WriteFile(filepath.Join(userDir, "nogoal.md"), []byte("## Constraints\n- Must return JSON only\n"), 0644); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+
+	catalog, err := Catalog(projectDir)
+	if err != nil {
+		t.Fatalf("Catalog() should not fail on an invalid template: %v", err)
+	}
+	if len(catalog) != len(registry)+2 {
+		t.Fatalf("Expected %d templates, got %d", len(registry)+2, len(catalog))
+	}
+	var broken *Template
+	for i := range catalog {
+		if catalog[i].Name == "nogoal" {
+			broken = &catalog[i]
+		}
+	}
+	if broken == nil || broken.Err == nil || broken.Source != SourceUser || !strings.Contains(broken.Err.Error(), "Goal section is required") {
+		t.Fatalf("Expected nogoal to be listed with its error, got %+v", broken)
+	}
+
+	if _, err := Find(projectDir, "api-guardrails"); err != nil {
+		t.Errorf("Find(api-guardrails) should not be affected by an invalid template: %v", err)
+	}
+	if _, err := Find(projectDir, "billing"); err != nil {
+		t.Errorf("Find(billing) should not be affected by an invalid template: %v", err)
+	}
+	if _, err := Find(projectDir, "nogoal"); err == nil || !strings.Contains(err.Error(), "Goal section is required") {
+		t.Errorf("Expected Find(nogoal) to return its load error, got %v", err)
+	}
+}
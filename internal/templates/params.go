@@ -0,0 +1,210 @@
+package templates
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/promptforge/promptforge/internal/ir"
+	"github.com/promptforge/promptforge/internal/parser"
+)
+
+// Template parameters are declared in front matter, one "param:" entry each, using
+// the same syntax as tool parameters and plan variables:
+//
+//	---
+//	name: triage
+//	param: domain (string, required): Product the tickets belong to
+//	param: categories (string[], required): Ticket categories
+//	---
+//
+// The plan refers to them as {{domain}} and {{categories}}. List values are
+// comma-separated; a list default separates values with "|". Placeholders that are
+// not template parameters are left for runtime variables.
+
+// listBulletRe matches a list item consisting only of a placeholder, which a
+// list parameter expands into one item per value.
+var listBulletRe = regexp.MustCompile(`^(\s*(?:[-*•]|\d+\.)\s+)\{\{\s*([A-Za-z_][A-Za-z0-9_.-]*)\s*\}\}\s*$`)
+
+// MissingParamsError reports required template parameters that were not given.
+type MissingParamsError struct {
+	Template string
+	Params   []parser.Parameter
+}
+
+func (e *MissingParamsError) Error() string {
+	names := make([]string, len(e.Params))
+	for i, param := range e.Params {
+		names[i] = param.Name
+	}
+	return fmt.Sprintf("missing required parameters for template %s: %s (use --set name=value)", e.Template, strings.Join(names, ", "))
+}
+
+// parseParams parses the "param:" front matter entries of a template.
+func parseParams(path string, entries []parser.FrontMatterEntry) ([]parser.Parameter, error) {
+	var params []parser.Parameter
+	seen := make(map[string]bool)
+	for _, entry := range entries {
+		param, err := parser.ParseParameter(entry.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid parameter in %s:%d: %w", path, entry.Line, err)
+		}
+		if !isParamType(param.Type) {
+			return nil, fmt.Errorf("invalid parameter in %s:%d: unknown type %q for %s", path, entry.Line, param.Type, param.Name)
+		}
+		if seen[param.Name] {
+			return nil, fmt.Errorf("invalid parameter in %s:%d: duplicate parameter %s", path, entry.Line, param.Name)
+		}
+		if param.HasDefault {
+			if err := checkParamValue(param, param.Default); err != nil {
+				return nil, fmt.Errorf("invalid parameter in %s:%d: default for %s: %w", path, entry.Line, param.Name, err)
+			}
+		}
+		seen[param.Name] = true
+		param.Line = entry.Line
+		params = append(params, param)
+	}
+	return params, nil
+}
+
+func isParamType(typeName string) bool {
+	if elem, ok := parser.IsArrayType(typeName); ok {
+		return ir.IsVariableType(elem)
+	}
+	return ir.IsVariableType(typeName)
+}
+
+// splitList splits a list parameter value on commas (or "|", used in defaults).
+func splitList(value string) []string {
+	fields := strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == '|' })
+	values := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if field = strings.TrimSpace(field); field != "" {
+			values = append(values, field)
+		}
+	}
+	return values
+}
+
+func checkParamValue(param parser.Parameter, value string) error {
+	elem, isList := parser.IsArrayType(param.Type)
+	if !isList {
+		return ir.CheckVariableValue(param.Type, value)
+	}
+	values := splitList(value)
+	if len(values) == 0 && param.Required {
+		return fmt.Errorf("expected at least one value")
+	}
+	for _, v := range values {
+		if err := ir.CheckVariableValue(elem, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MissingParams returns the required parameters that have no value and no default.
+func (t Template) MissingParams(values map[string]string) []parser.Parameter {
+	var missing []parser.Parameter
+	for _, param := range t.Params {
+		if _, ok := values[param.Name]; ok || param.HasDefault || !param.Required {
+			continue
+		}
+		missing = append(missing, param)
+	}
+	return missing
+}
+
+// Render returns the template plan with its parameters filled in from values.
+// It fails on unknown parameters, values of the wrong type and missing required
+// parameters (as a *MissingParamsError).
+func (t Template) Render(values map[string]string) (string, error) {
+	declared := make(map[string]parser.Parameter, len(t.Params))
+	for _, param := range t.Params {
+		declared[param.Name] = param
+	}
+
+	var unknown []string
+	for name := range values {
+		if _, ok := declared[name]; !ok {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return "", fmt.Errorf("unknown parameters for template %s: %s", t.Name, strings.Join(unknown, ", "))
+	}
+
+	if missing := t.MissingParams(values); len(missing) > 0 {
+		return "", &MissingParamsError{Template: t.Name, Params: missing}
+	}
+
+	resolved := make(map[string][]string, len(t.Params))
+	for _, param := range t.Params {
+		value, ok := values[param.Name]
+		if !ok {
+			value = param.Default
+		}
+		if ok {
+			if err := checkParamValue(param, value); err != nil {
+				return "", fmt.Errorf("invalid value for template parameter %s: %w", param.Name, err)
+			}
+		}
+		if _, isList := parser.IsArrayType(param.Type); isList {
+			resolved[param.Name] = splitList(value)
+		} else {
+			resolved[param.Name] = []string{strings.TrimSpace(value)}
+		}
+	}
+	if len(resolved) == 0 {
+		return t.Plan, nil
+	}
+
+	lookup := func(name string) (string, bool) {
+		values, ok := resolved[name]
+		return strings.Join(values, ", "), ok
+	}
+
+	lines := strings.Split(t.Plan, "\n")
+	out := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if match := listBulletRe.FindStringSubmatch(line); match != nil {
+			if _, isList := parser.IsArrayType(declared[match[2]].Type); isList {
+				for _, value := range resolved[match[2]] {
+					out = append(out, match[1]+value)
+				}
+				continue
+			}
+		}
+		out = append(out, parser.ReplacePlaceholders(line, lookup))
+	}
+	return strings.Join(out, "\n"), nil
+}
+
+// SampleParams returns a value for every parameter, using defaults where declared
+// and a placeholder value of the right type otherwise. It is used to verify that a
+// template compiles without asking for real values.
+func (t Template) SampleParams() map[string]string {
+	samples := map[string]string{
+		"string":  "example",
+		"number":  "1.5",
+		"integer": "1",
+		"boolean": "true",
+		"date":    "2024-01-01",
+	}
+
+	values := make(map[string]string, len(t.Params))
+	for _, param := range t.Params {
+		if param.HasDefault {
+			values[param.Name] = param.Default
+			continue
+		}
+		typeName := param.Type
+		if elem, isList := parser.IsArrayType(typeName); isList {
+			typeName = elem
+		}
+		values[param.Name] = samples[typeName]
+	}
+	return values
+}
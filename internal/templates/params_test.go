@@ -0,0 +1,84 @@
+package templates
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRender_TriageTemplate(t *testing.T) {
+	tmpl, err := Get("triage")
+	if err != nil {
+		t.Fatalf("Get(triage) failed: %v", err)
+	}
+
+	plan, err := tmpl.Render(map[string]string{"domain": "Payments", "categories": "bug, billing"})
+	if err != nil {
+		t.Fatalf("Render() failed: %v", err)
+	}
+	if !strings.Contains(plan, "Triage incoming Payments support tickets") {
+		t.Errorf("domain was not substituted:\n%s", plan)
+	}
+	if !strings.Contains(plan, "exactly one of: bug, billing\n") {
+		t.Errorf("categories were not substituted:\n%s", plan)
+	}
+}
+
+func TestRender_MissingAndInvalidParams(t *testing.T) {
+	tmpl, err := Get("triage")
+	if err != nil {
+		t.Fatalf("Get(triage) failed: %v", err)
+	}
+
+	_, err = tmpl.Render(nil)
+	var missing *MissingParamsError
+	if !errors.As(err, &missing) {
+		t.Fatalf("Expected MissingParamsError, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "domain, categories") {
+		t.Errorf("Error should list missing parameters in order: %v", err)
+	}
+
+	if _, err := tmpl.Render(map[string]string{"domain": "x", "categories": "bug", "tone": "dry"}); err == nil || !strings.Contains(err.Error(), "unknown parameters for template triage: tone") {
+		t.Errorf("Expected unknown parameter error, got %v", err)
+	}
+}
+
+func TestParseTemplate_ParamsAndListExpansion(t *testing.T) {
+	content := `---
+name: intake
+param: queues (string[], default=general|urgent): Queues to route to
+param: max_items (integer, required): Maximum tickets per batch
+param: team: Owning team
+---
+## Goal
+Route up to {{max_items}} tickets for {{team}} on {{ today }}.
+
+## Constraints
+- {{queues}}
+`
+	tmpl, err := parseTemplate("intake.md", []byte(content))
+	if err != nil {
+		t.Fatalf("parseTemplate() failed: %v", err)
+	}
+	if len(tmpl.Params) != 3 {
+		t.Fatalf("Expected 3 params, got %+v", tmpl.Params)
+	}
+
+	if _, err := tmpl.Render(map[string]string{"max_items": "many"}); err == nil || !strings.Contains(err.Error(), "not an integer") {
+		t.Errorf("Expected type error, got %v", err)
+	}
+
+	plan, err := tmpl.Render(map[string]string{"max_items": "20"})
+	if err != nil {
+		t.Fatalf("Render() failed: %v", err)
+	}
+	want := "## Goal\nRoute up to 20 tickets for  on {{ today }}.\n\n## Constraints\n- general\n- urgent\n"
+	if plan != want {
+		t.Errorf("Render() =\n%q\nwant\n%q", plan, want)
+	}
+
+	if _, err := parseTemplate("bad.md", []byte("---\nparam: x (color): Bad\n---\n## Goal\nGoal\n")); err == nil {
+		t.Error("Expected error for unknown parameter type")
+	}
+}
@@ -1,11 +1,29 @@
 package templates
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/promptforge/promptforge/internal/parser"
+)
 
 type Template struct {
 	Name        string
 	Description string
 	Plan        string
+
+	// Params declares the {{placeholders}} filled in when the template is used.
+	Params []parser.Parameter
+
+	// Source is SourceBuiltin, SourceUser or SourceProject. It is empty for
+	// templates returned by List and Get, which only cover built-ins.
+	Source string
+
+	// Path is the file a user or project template was loaded from.
+	Path string
+
+	// Err is set for a user or project template that failed to load. Catalog
+	// still lists it so the problem can be reported; Find returns Err instead.
+	Err error
 }
 
 var registry = []Template{
@@ -61,16 +79,38 @@ Provide a concise, high-signal code review focused on correctness, security, and
 ## Out of Scope
 - Large refactors without a clear bug
 - Formatting-only feedback
+`,
+	},
+	{
+		Name:        "triage",
+		Description: "Classify incoming tickets into a fixed set of categories",
+		Params: []parser.Parameter{
+			{Name: "domain", Type: "string", Required: true, Description: "Product or team the tickets belong to"},
+			{Name: "categories", Type: "string[]", Required: true, Description: "Allowed ticket categories"},
+		},
+		Plan: `# Prompt Plan
+
+## Goal
+Triage incoming {{domain}} support tickets into exactly one category.
+
+## Constraints
+- Must classify each ticket into exactly one of: {{categories}}
+- Must ask a clarifying question when the category is unclear
+- Must return JSON only
+
+## Out of Scope
+- Resolving the ticket
+- Replying to the customer directly
 `,
 	},
 }
 
-// List returns all available templates.
+// List returns the built-in templates. Use Catalog to include user and project templates.
 func List() []Template {
 	return registry
 }
 
-// Get returns a template by name.
+// Get returns a built-in template by name.
 func Get(name string) (Template, error) {
 	for _, tmpl := range registry {
 		if tmpl.Name == name {
@@ -0,0 +1,10 @@
+// Package ui embeds the PromptForge Lens web UI (index.html) so that
+// `promptforge ui` can serve it from the binary.
+package ui
+
+import "embed"
+
+// FS holds index.html.
+//
+//go:embed index.html
+var FS embed.FS
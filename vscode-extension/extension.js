@@ -7,11 +7,28 @@ let activePanel = null;
 let activePlanPath = null;
 let diagnostics = null;
 const lintTimers = new Map();
+// Fixes reported by `promptforge lint --json`, keyed by plan path and then by diagnostic.
+const lintFixes = new Map();
 
 function activate(context) {
     diagnostics = vscode.languages.createDiagnosticCollection('promptforge');
     context.subscriptions.push(diagnostics);
 
+    context.subscriptions.push(vscode.languages.registerCodeActionsProvider(
+        { language: 'markdown', scheme: 'file' },
+        { provideCodeActions: providePlanCodeActions },
+        { providedCodeActionKinds: [vscode.CodeActionKind.QuickFix] }
+    ));
+
+    context.subscriptions.push(vscode.commands.registerCommand('promptforge.fixAll', async () => {
+        const editor = vscode.window.activeTextEditor;
+        if (!editor || !isPlanFile(editor.document.fileName)) {
+            vscode.window.showErrorMessage('Open a promptforge/plan.md file first.');
+            return;
+        }
+        await fixAllPlan(editor.document);
+    }));
+
     context.subscriptions.push(vscode.workspace.onDidSaveTextDocument((doc) => {
         if (!isPlanFile(doc.fileName)) {
             return;
@@ -261,7 +278,7 @@ async function lintPlan(filePath, panel) {
     const cliPath = config.cliPath;
 
     try {
-        const output = await runPromptforge(cliPath, ['lint'], projectDir);
+        const output = await runPromptforge(cliPath, ['lint', '--json'], projectDir);
         const diagnosticsList = parseLintOutput(output, filePath);
         const uri = vscode.Uri.file(filePath);
         diagnostics.set(uri, diagnosticsList);
@@ -287,47 +304,103 @@ async function lintPlan(filePath, panel) {
 
 function parseLintOutput(output, filePath) {
     const diagnosticsList = [];
-    const lines = output.split(/\r?\n/);
-    const regex = /^(.+?):(\d+):(\d+):\s+(\w+)\s+(\w+)\s+(.*)$/;
+    const fixes = new Map();
+    let envelope;
+    try {
+        envelope = JSON.parse(output);
+    } catch (error) {
+        throw new Error('Could not parse promptforge lint output');
+    }
+    // lint --json prints a result envelope; a failed run carries only an error
+    if (envelope.status === 'error' && envelope.error) {
+        throw new Error(envelope.error.message);
+    }
+    const results = Array.isArray(envelope) ? envelope : envelope.diagnostics || [];
 
-    for (const line of lines) {
-        if (!line.trim()) {
+    for (const result of results) {
+        if (path.resolve(result.file) !== path.resolve(filePath)) {
             continue;
         }
-        const match = line.match(regex);
-        if (!match) {
-            continue;
+        const lineNum = Math.max(0, result.line - 1);
+        const colNum = Math.max(0, result.column - 1);
+        const range = new vscode.Range(lineNum, colNum, lineNum, colNum + 1);
+
+        const severity = result.severity === 'error' ? vscode.DiagnosticSeverity.Error : vscode.DiagnosticSeverity.Warning;
+        const diagnostic = new vscode.Diagnostic(range, `${result.code} ${result.message}`, severity);
+        diagnostic.source = 'promptforge';
+        diagnostic.code = result.code;
+        diagnosticsList.push(diagnostic);
+        if (result.fix) {
+            fixes.set(diagnostic, result.fix);
         }
-        const pathMatch = match[1];
-        if (pathMatch !== filePath) {
+    }
+
+    lintFixes.set(filePath, fixes);
+    return diagnosticsList;
+}
+
+function providePlanCodeActions(document, range, context) {
+    const fixes = lintFixes.get(document.fileName);
+    if (!fixes) {
+        return [];
+    }
+    const actions = [];
+    for (const diagnostic of context.diagnostics) {
+        const fix = fixes.get(diagnostic);
+        if (!fix) {
             continue;
         }
-        const lineNum = parseInt(match[2], 10);
-        const colNum = parseInt(match[3], 10);
-        const severityText = match[4];
-        const code = match[5];
-        const message = match[6];
-
-        const range = new vscode.Range(
-            Math.max(0, lineNum - 1),
-            Math.max(0, colNum - 1),
-            Math.max(0, lineNum - 1),
-            Math.max(0, colNum)
-        );
+        const action = new vscode.CodeAction(fix.title, vscode.CodeActionKind.QuickFix);
+        action.diagnostics = [diagnostic];
+        action.edit = new vscode.WorkspaceEdit();
+        for (const edit of fix.edits) {
+            applyLintEdit(action.edit, document, edit);
+        }
+        actions.push(action);
+    }
+    if (actions.length > 0) {
+        const fixAll = new vscode.CodeAction('Fix all PromptForge issues', vscode.CodeActionKind.QuickFix);
+        fixAll.command = { command: 'promptforge.fixAll', title: 'Fix all PromptForge issues' };
+        actions.push(fixAll);
+    }
+    return actions;
+}
 
-        const severity = severityText === 'error' ? vscode.DiagnosticSeverity.Error : vscode.DiagnosticSeverity.Warning;
-        const diagnostic = new vscode.Diagnostic(range, `${code} ${message}`, severity);
-        diagnosticsList.push(diagnostic);
+// applyLintEdit adds a line-based lint edit (1-based, inclusive startLine..endLine;
+// endLine = startLine - 1 inserts before startLine) to a WorkspaceEdit.
+function applyLintEdit(workspaceEdit, document, edit) {
+    const newLines = edit.newLines || [];
+    const start = edit.startLine - 1;
+    if (start >= document.lineCount) {
+        const end = document.lineAt(document.lineCount - 1).range.end;
+        workspaceEdit.insert(document.uri, end, newLines.map(line => '\n' + line).join(''));
+        return;
     }
+    const text = newLines.map(line => line + '\n').join('');
+    const range = new vscode.Range(start, 0, Math.max(start, edit.endLine), 0);
+    workspaceEdit.replace(document.uri, range, text);
+}
 
-    return diagnosticsList;
+async function fixAllPlan(document) {
+    if (document.isDirty) {
+        await document.save();
+    }
+    const filePath = document.fileName;
+    const projectDir = path.dirname(path.dirname(filePath));
+    try {
+        await runPromptforge(getConfig().cliPath, ['lint', '--fix', '--json'], projectDir);
+    } catch (error) {
+        vscode.window.showWarningMessage(error.message || 'Fix failed');
+    }
+    await lintPlan(filePath, activePanel);
 }
 
 function runPromptforge(cliPath, args, cwd) {
     return new Promise((resolve, reject) => {
         const cmd = `"${cliPath}" ${args.join(' ')}`;
         exec(cmd, { cwd: cwd, timeout: 30000 }, (error, stdout, stderr) => {
-            if (error) {
+            // with --json the result envelope is printed whatever the exit code
+            if (error && !(stdout && stdout.trim().length > 0)) {
                 const message = stderr && stderr.trim().length > 0 ? stderr : error.message;
                 reject(new Error(message));
                 return;
@@ -337,6 +410,20 @@ function runPromptforge(cliPath, args, cwd) {
     });
 }
 
+// parseEnvelope returns the result envelope printed by a --json command, or null
+// when the output is not one (such as a CLI too old to print it).
+function parseEnvelope(output) {
+    if (!output) {
+        return null;
+    }
+    try {
+        const envelope = JSON.parse(output);
+        return envelope && typeof envelope.status === 'string' ? envelope : null;
+    } catch (error) {
+        return null;
+    }
+}
+
 function postToPanel(panel, message) {
     if (!panel || !panel.webview) {
         return;
@@ -427,7 +514,18 @@ function compileToJSON(projectDir, outputPath, panel, explain) {
         // Run compile command with timeout
         const timeout = 30000; // 30 seconds
         const explainFlag = explain ? ' --explain' : '';
-        const compileProcess = exec(`"${promptforgeCmd}" compile${explainFlag}`, { cwd: projectDir, timeout: timeout }, (error, stdout, stderr) => {
+        const compileProcess = exec(`"${promptforgeCmd}" compile --json${explainFlag}`, { cwd: projectDir, timeout: timeout }, (error, stdout, stderr) => {
+            // A failed compile leaves the previous prompt.ir.json in place; report the
+            // error from the result envelope instead of showing the stale IR
+            const envelope = parseEnvelope(stdout);
+            if (envelope && envelope.status === 'error' && envelope.error) {
+                const errorMsg = `Compilation failed: ${envelope.error.message}`;
+                vscode.window.showErrorMessage(errorMsg);
+                postToPanel(panel, { command: 'error', message: errorMsg });
+                reject(new Error(errorMsg));
+                return;
+            }
+
             // Read the generated JSON
             try {
                 if (fs.existsSync(outputPath)) {
@@ -769,7 +867,7 @@ function getWebviewContent(goal, constraints, outOfScope, showJson = true) {
             let warnCount = 0;
             items.forEach(item => {
                 const li = document.createElement('li');
-                li.textContent = `Line ${item.line}:${item.column} ${item.severity.toUpperCase()} ${item.message}`;
+                li.textContent = \`Line \${item.line}:\${item.column} \${item.severity.toUpperCase()} \${item.message}\`;
                 lintList.appendChild(li);
                 if (item.severity === 'error') {
                     errorCount++;
@@ -778,7 +876,7 @@ function getWebviewContent(goal, constraints, outOfScope, showJson = true) {
                 }
             });
 
-            lintSummary.textContent = `${errorCount} error(s), ${warnCount} warning(s)`;
+            lintSummary.textContent = \`\${errorCount} error(s), \${warnCount} warning(s)\`;
             lintSection.style.display = 'block';
         }
 